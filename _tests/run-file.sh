@@ -0,0 +1,4 @@
+# I am a comment.
+echo "I am the first command from run-file.sh"
+
+echo "I am the second command from run-file.sh"
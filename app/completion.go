@@ -0,0 +1,317 @@
+package app
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	OrbitError "github.com/gulien/orbit/app/error"
+	"github.com/gulien/orbit/app/logger"
+	"github.com/gulien/orbit/app/runner"
+
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// bashCompletionFunction completes the names of the tasks for the commands run and watch.
+// It's called by the bash completion generated by cobra when no others suggestions are available.
+const bashCompletionFunction = `__orbit_tasks()
+{
+    local tasks
+    tasks=$(orbit __tasks 2>/dev/null | cut -f1)
+    COMPREPLY=( $(compgen -W "${tasks}" -- "$cur") )
+}
+
+__custom_func()
+{
+    case ${last_command} in
+        orbit_run | orbit_watch)
+            __orbit_tasks
+            return
+            ;;
+    esac
+}
+`
+
+var (
+	// completionCmd is the instance of completion command.
+	completionCmd = &cobra.Command{
+		Use:           "completion <bash|zsh|fish|powershell>",
+		Short:         "Prints the shell completion script of Orbit",
+		Long:          "Prints the shell completion script of Orbit, which also completes the tasks from the configuration file of the current directory.",
+		ValidArgs:     []string{"bash", "zsh", "fish", "powershell"},
+		Args:          cobra.ExactArgs(1),
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		RunE:          completion,
+	}
+
+	// tasksCmd is the instance of the hidden command printing the tasks for the shell completion.
+	tasksCmd = &cobra.Command{
+		Use:           "__tasks",
+		Short:         "Prints the tasks which are not private, for the shell completion",
+		Hidden:        true,
+		Args:          cobra.NoArgs,
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		Run:           completionTasks,
+	}
+)
+
+// init adds the completionCmd and tasksCmd instances to the RootCmd.
+func init() {
+	RootCmd.BashCompletionFunction = bashCompletionFunction
+	RootCmd.AddCommand(completionCmd)
+	RootCmd.AddCommand(tasksCmd)
+}
+
+// completion prints the completion script of the given shell to Stdout.
+func completion(cmd *cobra.Command, args []string) error {
+	switch args[0] {
+	case "bash":
+		return RootCmd.GenBashCompletion(os.Stdout)
+	case "zsh":
+		return genZshCompletion(os.Stdout)
+	case "fish":
+		return genFishCompletion(os.Stdout)
+	case "powershell":
+		return genPowerShellCompletion(os.Stdout)
+	default:
+		return OrbitError.NewOrbitErrorf("unknown shell %s, expected bash, zsh, fish or powershell", args[0])
+	}
+}
+
+// completionTasks prints the tasks which are not private to Stdout, one per line, followed by a tab and their short description.
+func completionTasks(cmd *cobra.Command, args []string) {
+	// nothing but the tasks should be printed.
+	logger.SetLevel(logrus.PanicLevel)
+
+	if templateFilePath == "" {
+		templateFilePath = orbitFilePath
+	}
+
+	for _, task := range runner.CompletionTasks(templateFilePath) {
+		fmt.Printf("%s\t%s\n", task.Use, task.Short)
+	}
+}
+
+// completionCommands returns the commands of Orbit which are not hidden.
+func completionCommands() []*cobra.Command {
+	var commands []*cobra.Command
+	for _, cmd := range RootCmd.Commands() {
+		if !cmd.Hidden && cmd.Name() != "help" {
+			commands = append(commands, cmd)
+		}
+	}
+
+	return commands
+}
+
+// completionFlags returns the flags of the given command, including the global ones.
+func completionFlags(cmd *cobra.Command) []*pflag.Flag {
+	var flags []*pflag.Flag
+	visit := func(flag *pflag.Flag) {
+		if !flag.Hidden {
+			flags = append(flags, flag)
+		}
+	}
+
+	cmd.NonInheritedFlags().VisitAll(visit)
+	RootCmd.PersistentFlags().VisitAll(visit)
+
+	return flags
+}
+
+// completesTasks returns true if the arguments of the given command are tasks.
+func completesTasks(cmd *cobra.Command) bool {
+	return cmd == runCmd || cmd == watchCmd
+}
+
+// zshReplacer escapes the descriptions of the zsh completion.
+var zshReplacer = strings.NewReplacer("'", `'\''`, "[", `\[`, "]", `\]`, ":", `\:`)
+
+// genZshCompletion writes the zsh completion script to the given writer.
+func genZshCompletion(w io.Writer) error {
+	var b strings.Builder
+
+	b.WriteString(`#compdef orbit
+
+_orbit_tasks() {
+  local -a tasks
+  tasks=(${(f)"$(orbit __tasks 2>/dev/null | sed -e 's/:/\\:/g' -e 's/	/:/')"})
+  _describe -t tasks 'task' tasks
+}
+
+_orbit() {
+  local -a commands
+  commands=(
+`)
+
+	for _, cmd := range completionCommands() {
+		fmt.Fprintf(&b, "    '%s:%s'\n", cmd.Name(), zshReplacer.Replace(cmd.Short))
+	}
+
+	b.WriteString(`  )
+
+  if (( CURRENT == 2 )); then
+    _describe -t commands 'command' commands
+    return
+  fi
+
+  local command=$words[2]
+  shift words
+  (( CURRENT-- ))
+
+  case $command in
+`)
+
+	for _, cmd := range completionCommands() {
+		fmt.Fprintf(&b, "    %s)\n      _arguments -s", cmd.Name())
+		for _, flag := range completionFlags(cmd) {
+			value := ""
+			if flag.Value.Type() != "bool" {
+				value = ":" + flag.Name + ":"
+			}
+
+			usage := zshReplacer.Replace(flag.Usage)
+			fmt.Fprintf(&b, " \\\n        '--%s[%s]%s'", flag.Name, usage, value)
+			if flag.Shorthand != "" {
+				fmt.Fprintf(&b, " \\\n        '-%s[%s]%s'", flag.Shorthand, usage, value)
+			}
+		}
+
+		if completesTasks(cmd) {
+			b.WriteString(" \\\n        '*::task:_orbit_tasks'")
+		}
+
+		b.WriteString("\n      ;;\n")
+	}
+
+	b.WriteString(`  esac
+}
+
+_orbit "$@"
+`)
+
+	_, err := io.WriteString(w, b.String())
+
+	return err
+}
+
+// fishReplacer escapes the descriptions of the fish completion.
+var fishReplacer = strings.NewReplacer(`\`, `\\`, "'", `\'`)
+
+// genFishCompletion writes the fish completion script to the given writer.
+func genFishCompletion(w io.Writer) error {
+	var b strings.Builder
+
+	b.WriteString(`function __orbit_using_command
+    set -l cmd (commandline -opc)
+    test (count $cmd) -gt 1; and contains -- $cmd[2] $argv
+end
+
+complete -c orbit -f
+`)
+
+	var taskCommands []string
+	for _, cmd := range completionCommands() {
+		fmt.Fprintf(&b, "complete -c orbit -n '__fish_use_subcommand' -a %s -d '%s'\n", cmd.Name(), fishReplacer.Replace(cmd.Short))
+
+		for _, flag := range completionFlags(cmd) {
+			fmt.Fprintf(&b, "complete -c orbit -n '__orbit_using_command %s' -l %s", cmd.Name(), flag.Name)
+			if flag.Shorthand != "" {
+				fmt.Fprintf(&b, " -s %s", flag.Shorthand)
+			}
+
+			if flag.Value.Type() != "bool" {
+				b.WriteString(" -r")
+			}
+
+			fmt.Fprintf(&b, " -d '%s'\n", fishReplacer.Replace(flag.Usage))
+		}
+
+		if completesTasks(cmd) {
+			taskCommands = append(taskCommands, cmd.Name())
+		}
+	}
+
+	fmt.Fprintf(&b, "complete -c orbit -n '__orbit_using_command %s' -a '(orbit __tasks 2>/dev/null)'\n", strings.Join(taskCommands, " "))
+
+	_, err := io.WriteString(w, b.String())
+
+	return err
+}
+
+// powerShellReplacer escapes the strings of the PowerShell completion.
+var powerShellReplacer = strings.NewReplacer("'", "''")
+
+// genPowerShellCompletion writes the PowerShell completion script to the given writer.
+func genPowerShellCompletion(w io.Writer) error {
+	var b strings.Builder
+
+	b.WriteString(`Register-ArgumentCompleter -Native -CommandName 'orbit' -ScriptBlock {
+    param($wordToComplete, $commandAst, $cursorPosition)
+
+    $commands = [ordered]@{
+`)
+
+	for _, cmd := range completionCommands() {
+		fmt.Fprintf(&b, "        '%s' = '%s'\n", cmd.Name(), powerShellReplacer.Replace(cmd.Short))
+	}
+
+	b.WriteString("    }\n\n    $flags = @{\n")
+
+	var taskCommands []string
+	for _, cmd := range completionCommands() {
+		var names []string
+		for _, flag := range completionFlags(cmd) {
+			names = append(names, "'--"+flag.Name+"'")
+			if flag.Shorthand != "" {
+				names = append(names, "'-"+flag.Shorthand+"'")
+			}
+		}
+
+		fmt.Fprintf(&b, "        '%s' = @(%s)\n", cmd.Name(), strings.Join(names, ", "))
+
+		if completesTasks(cmd) {
+			taskCommands = append(taskCommands, "'"+cmd.Name()+"'")
+		}
+	}
+
+	fmt.Fprintf(&b, `    }
+
+    $words = @($commandAst.CommandElements | Select-Object -Skip 1 | Where-Object { $_.Extent.EndOffset -lt $cursorPosition } | ForEach-Object { $_.ToString() })
+    $command = $words | Where-Object { $commands.Contains($_) } | Select-Object -First 1
+
+    if (-not $command) {
+        $commands.GetEnumerator() | Where-Object { $_.Key -like "$wordToComplete*" } | ForEach-Object {
+            [System.Management.Automation.CompletionResult]::new($_.Key, $_.Key, 'ParameterValue', $_.Value)
+        }
+        return
+    }
+
+    if ($wordToComplete -like '-*') {
+        $flags[$command] | Where-Object { $_ -like "$wordToComplete*" } | ForEach-Object {
+            [System.Management.Automation.CompletionResult]::new($_, $_, 'ParameterName', $_)
+        }
+        return
+    }
+
+    if ($command -in @(%s)) {
+        orbit __tasks 2>$null | ForEach-Object {
+            $name, $short = $_ -split "`+"`t"+`", 2
+            if (-not $short) { $short = $name }
+            if ($name -like "$wordToComplete*") {
+                [System.Management.Automation.CompletionResult]::new($name, $name, 'ParameterValue', $short)
+            }
+        }
+    }
+}
+`, strings.Join(taskCommands, ", "))
+
+	_, err := io.WriteString(w, b.String())
+
+	return err
+}
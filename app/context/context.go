@@ -6,44 +6,108 @@ The main goal of the application context is to gather all the data which will be
 package context
 
 import (
+	"io"
+	"os"
+	"path/filepath"
+	"text/template"
+
 	OrbitError "github.com/gulien/orbit/app/error"
 	"github.com/gulien/orbit/app/helpers"
 	"github.com/gulien/orbit/app/logger"
 )
 
+/*
+OrbitSettings contains the settings of an OrbitContext, which also apply to the generator
+and to the runner instantiated with it.
+
+The zero value reads the remote files over HTTPS only, logs a warning for a .env file accessible by others users,
+ignores the missing git metadata, merges the local configuration file and does not profile the startup.
+*/
+type OrbitSettings struct {
+	// Insecure allows to download remote files over plain HTTP, or over HTTPS without verifying the certificates.
+	Insecure bool
+
+	// StrictPermissions forbids reading a .env file which is accessible by others users if true.
+	// Otherwise, a warning is logged.
+	StrictPermissions bool
+
+	// StrictGit makes the git functions fail if the git metadata are not available if true.
+	// Otherwise, they return an empty string.
+	StrictGit bool
+
+	// SkipLocalConfig disables the loading of the local configuration file if true.
+	SkipLocalConfig bool
+
+	// ProfileOutput receives the duration of each startup phase of the runner.
+	// If nil, the durations are not printed.
+	ProfileOutput io.Writer
+}
+
 // OrbitContext contains the data necessary for executing a data-driven template.
 type OrbitContext struct {
 	// TemplateFilePath is the path of a data-driven template.
+	// For a remote data-driven template, it's the path of its cached copy.
 	TemplateFilePath string
 
+	// TemplateURL is the URL of the data-driven template, if it's a remote file.
+	TemplateURL string
+
 	// Payload map contains data from various entries.
 	Payload map[string]interface{}
 
 	// Templates array contains the list of additional templates to parse.
 	Templates []string
+
+	// Env contains the variables from the dotenv files, accessible
+	// in a data-driven template through {{ .Env }}.
+	Env map[string]string
+
+	// Funcs contains additional functions available in a data-driven template.
+	// They win over the built-in functions with the same names.
+	Funcs template.FuncMap
+
+	// Settings contains the settings of the context.
+	Settings OrbitSettings
 }
 
-// NewOrbitContext creates an instance of OrbitContext.
+// NewOrbitContext creates an instance of OrbitContext with the default settings.
 func NewOrbitContext(templateFilePath string, payload string, templates string) (*OrbitContext, error) {
+	return NewOrbitContextWithSettings(templateFilePath, payload, templates, OrbitSettings{})
+}
+
+// NewOrbitContextWithSettings creates an instance of OrbitContext with the given settings.
+func NewOrbitContextWithSettings(templateFilePath string, payload string, templates string, settings OrbitSettings) (*OrbitContext, error) {
 	// as the data-driven template is mandatory, we must check its validity.
 	if templateFilePath == "" {
 		return nil, OrbitError.NewOrbitErrorf("no data-driven template given")
 	}
 
-	if !helpers.FileExists(templateFilePath) {
-		return nil, OrbitError.NewOrbitErrorf("the data-driven template %s does not exist", templateFilePath)
-	}
-
 	// let's instantiates our OrbitContext!
 	ctx := &OrbitContext{
 		TemplateFilePath: templateFilePath,
+		Settings:         settings,
+	}
+
+	// a remote data-driven template is downloaded first.
+	if IsRemote(templateFilePath) {
+		filePath, err := fetchRemote(templateFilePath, settings.Insecure)
+		if err != nil {
+			return nil, err
+		}
+
+		ctx.TemplateURL = templateFilePath
+		ctx.TemplateFilePath = filePath
+	}
+
+	if !helpers.FileExists(ctx.TemplateFilePath) {
+		return nil, OrbitError.NewOrbitErrorf("the data-driven template %s does not exist", templateFilePath)
 	}
 
 	logger.Debugf("context has been instantiated with the data-driven template %s", ctx.TemplateFilePath)
 
 	// last but not least, instantiates an orbitPayload which will allow us
 	// to retrieves the data provided by the entries given by the user.
-	p := &orbitPayload{}
+	p := &orbitPayload{settings: settings}
 
 	if err := p.populateFromFile(""); err != nil {
 		return nil, err
@@ -66,3 +130,20 @@ func NewOrbitContext(templateFilePath string, payload string, templates string)
 
 	return ctx, nil
 }
+
+/*
+BaseDir returns the directory from which the relative paths of the data-driven template are resolved:
+the directory of the data-driven template, or the current directory if it's a remote file.
+*/
+func (ctx *OrbitContext) BaseDir() string {
+	if ctx.TemplateURL == "" {
+		return filepath.Dir(ctx.TemplateFilePath)
+	}
+
+	dir, err := os.Getwd()
+	if err != nil {
+		return "."
+	}
+
+	return dir
+}
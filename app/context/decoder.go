@@ -48,6 +48,9 @@ type (
 	orbitEnvFileDecoder struct {
 		// value represents a path to a .env file.
 		value string
+
+		// strictPermissions forbids reading the .env file if it's accessible by others users.
+		strictPermissions bool
 	}
 )
 
@@ -107,7 +110,12 @@ func (d *orbitJSONDecoder) decode() (interface{}, error) {
 }
 
 // decode from orbitEnvFileDecoder reads a .env file and retrieves its data.
+// As it may contain secrets, its permissions are checked first.
 func (d *orbitEnvFileDecoder) decode() (interface{}, error) {
+	if err := checkPermissions(d.value, d.strictPermissions); err != nil {
+		return nil, err
+	}
+
 	result, err := godotenv.Read(d.value)
 	if err != nil {
 		return nil, OrbitError.NewOrbitErrorf("unable to decode the .env file %s. Details:\n%s", d.value, err)
@@ -0,0 +1,46 @@
+package context
+
+import (
+	OrbitError "github.com/gulien/orbit/app/error"
+	"github.com/gulien/orbit/app/logger"
+
+	"github.com/joho/godotenv"
+)
+
+/*
+LoadDotenv reads the given .env files and adds their variables to Env.
+
+The files are read in the order of declaration: if a variable is defined in many files,
+the last definition wins, including over the variables already in Env. As they may contain secrets,
+their permissions are checked first.
+*/
+func (ctx *OrbitContext) LoadDotenv(filesPaths ...string) error {
+	for _, filePath := range filesPaths {
+		if err := ctx.CheckPermissions(filePath); err != nil {
+			return err
+		}
+
+		variables, err := godotenv.Read(filePath)
+		if err != nil {
+			return OrbitError.NewOrbitErrorf("unable to read the dotenv file %s. Details:\n%s", filePath, err)
+		}
+
+		if ctx.Env == nil {
+			ctx.Env = make(map[string]string)
+		}
+
+		for key, value := range variables {
+			ctx.Env[key] = value
+		}
+
+		logger.Debugf("context has been populated with the variables of the dotenv file %s", filePath)
+	}
+
+	return nil
+}
+
+// CheckPermissions verifies that the given file, which may contain secrets, is not accessible by others users.
+// If the permissions are too open, it logs a warning or returns an error with the strict permissions setting.
+func (ctx *OrbitContext) CheckPermissions(filePath string) error {
+	return checkPermissions(filePath, ctx.Settings.StrictPermissions)
+}
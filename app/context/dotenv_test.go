@@ -0,0 +1,28 @@
+package context
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+// Tests if the variables of the dotenv files are added to the context.
+func TestLoadDotenv(t *testing.T) {
+	templateFilePath, _ := filepath.Abs("../../_tests/template.yml")
+	ctx, _ := NewOrbitContext(templateFilePath, "", "")
+
+	// case 1: uses many dotenv files, the last definition winning.
+	missionFilePath, _ := filepath.Abs("../../_tests/mission.env")
+	overrideFilePath, _ := filepath.Abs("../../_tests/mission-override.env")
+	if err := ctx.LoadDotenv(missionFilePath, overrideFilePath); err != nil {
+		t.Errorf("Dotenv files should have been loaded, got %s!", err)
+	}
+
+	if ctx.Env["MISSION"] != "artemis" || ctx.Env["ROCKET"] != "saturn" {
+		t.Errorf("Variables of the last dotenv file should have won, got %v!", ctx.Env)
+	}
+
+	// case 2: uses a non existing dotenv file.
+	if err := ctx.LoadDotenv("non-existing.env"); err == nil {
+		t.Error("Non existing dotenv file should have thrown an error!")
+	}
+}
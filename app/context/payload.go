@@ -2,6 +2,7 @@ package context
 
 import (
 	"io/ioutil"
+	"net/url"
 	"path/filepath"
 	"strings"
 
@@ -23,6 +24,9 @@ type (
 
 		// TemplatesEntries is a simple array of string.
 		TemplatesEntries []string `yaml:"templates,omitempty"`
+
+		// settings contains the settings of the context, used to read the entries.
+		settings OrbitSettings
 	}
 
 	// orbitPayloadEntry is an entry from a file or from a string.
@@ -93,6 +97,15 @@ func (p *orbitPayload) populateFromString(payload string, templates string) erro
 		// path,path,path.
 		entries := strings.Split(templates, ",")
 		for _, entry := range entries {
+			if IsRemote(entry) {
+				filePath, err := fetchRemote(entry, p.settings.Insecure)
+				if err != nil {
+					return err
+				}
+
+				entry = filePath
+			}
+
 			p.TemplatesEntries = append(p.TemplatesEntries, entry)
 		}
 	}
@@ -106,7 +119,18 @@ func (p *orbitPayload) retrievePayloadData() (map[string]interface{}, error) {
 	result := make(map[string]interface{})
 
 	for _, payloadEntry := range p.PayloadEntries {
-		d := getDecoder(payloadEntry.Value)
+		// a URL is raw data, unless it's the URL of a data file.
+		source := payloadEntry.Value
+		if IsRemote(source) && isDataFile(source) {
+			filePath, err := fetchRemote(source, p.settings.Insecure)
+			if err != nil {
+				return nil, err
+			}
+
+			source = filePath
+		}
+
+		d := getDecoder(source, p.settings.StrictPermissions)
 
 		value, err := d.decode()
 		if err != nil {
@@ -119,8 +143,23 @@ func (p *orbitPayload) retrievePayloadData() (map[string]interface{}, error) {
 	return result, nil
 }
 
+// isDataFile returns true if the given path or URL has the extension of a YAML, TOML, JSON or .env file.
+func isDataFile(value string) bool {
+	if u, err := url.Parse(value); err == nil && u.Path != "" {
+		value = u.Path
+	}
+
+	switch filepath.Ext(value) {
+	case ".yaml", ".yml", ".toml", ".json", ".env":
+		return true
+	}
+
+	return false
+}
+
 // getDecoder returns the correct decoder for a given value.
-func getDecoder(value string) orbitDecoder {
+// The permissions of a .env file are checked strictly if strictPermissions is true.
+func getDecoder(value string, strictPermissions bool) orbitDecoder {
 	if !helpers.FileExists(value) {
 		return &orbitDumbDecoder{value: value}
 	}
@@ -134,5 +173,5 @@ func getDecoder(value string) orbitDecoder {
 		return &orbitJSONDecoder{value: value}
 	}
 
-	return &orbitEnvFileDecoder{value: value}
+	return &orbitEnvFileDecoder{value: value, strictPermissions: strictPermissions}
 }
@@ -76,7 +76,7 @@ func TestRetrievePayloadData(t *testing.T) {
 // instance of decoder.
 func TestGetDecoder(t *testing.T) {
 	// case 1: should returns an instance of orbitDumbDecoder
-	d := getDecoder("raw data")
+	d := getDecoder("raw data", false)
 	dumbDecoder := &orbitDumbDecoder{}
 	if reflect.TypeOf(d) != reflect.TypeOf(dumbDecoder) {
 		t.Error("Decoder should have been an instance of orbitDumbDecoder!")
@@ -84,14 +84,14 @@ func TestGetDecoder(t *testing.T) {
 
 	// case 2: should returns an instance of orbitYAMLDecoder
 	YAMLDataSourceFilePath, _ := filepath.Abs("../../_tests/data-source.yml")
-	d = getDecoder(YAMLDataSourceFilePath)
+	d = getDecoder(YAMLDataSourceFilePath, false)
 	YAMLDecoder := &orbitYAMLDecoder{}
 	if reflect.TypeOf(d) != reflect.TypeOf(YAMLDecoder) {
 		t.Error("Decoder should have been an instance of orbitYAMLDecoder!")
 	}
 
 	YAMLDataSourceFilePath, _ = filepath.Abs("../../_tests/data-source.yaml")
-	d = getDecoder(YAMLDataSourceFilePath)
+	d = getDecoder(YAMLDataSourceFilePath, false)
 	YAMLDecoder = &orbitYAMLDecoder{}
 	if reflect.TypeOf(d) != reflect.TypeOf(YAMLDecoder) {
 		t.Error("Decoder should have been an instance of orbitYAMLDecoder!")
@@ -99,7 +99,7 @@ func TestGetDecoder(t *testing.T) {
 
 	// case 3: should returns an instance of orbitTOMLDecoder
 	TOMLDataSourceFilePath, _ := filepath.Abs("../../_tests/data-source.toml")
-	d = getDecoder(TOMLDataSourceFilePath)
+	d = getDecoder(TOMLDataSourceFilePath, false)
 	TOMLDecoder := &orbitTOMLDecoder{}
 	if reflect.TypeOf(d) != reflect.TypeOf(TOMLDecoder) {
 		t.Error("Decoder should have been an instance of orbitTOMLDecoder!")
@@ -107,7 +107,7 @@ func TestGetDecoder(t *testing.T) {
 
 	// case 4: should returns an instance of orbitJSONDecoder
 	JSONDataSourceFilePath, _ := filepath.Abs("../../_tests/data-source.json")
-	d = getDecoder(JSONDataSourceFilePath)
+	d = getDecoder(JSONDataSourceFilePath, false)
 	JSONDecoder := &orbitJSONDecoder{}
 	if reflect.TypeOf(d) != reflect.TypeOf(JSONDecoder) {
 		t.Error("Decoder should have been an instance of orbitJSONDecoder!")
@@ -115,7 +115,7 @@ func TestGetDecoder(t *testing.T) {
 
 	// case 5: should returns an instance of orbitEnvFileDecoder
 	envFileDataSourceFilePath, _ := filepath.Abs("../../_tests/.env")
-	d = getDecoder(envFileDataSourceFilePath)
+	d = getDecoder(envFileDataSourceFilePath, false)
 	envFileDecoder := &orbitEnvFileDecoder{}
 	if reflect.TypeOf(d) != reflect.TypeOf(envFileDecoder) {
 		t.Error("Decoder should have been an instance of orbitEnvFileDecoder!")
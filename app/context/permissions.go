@@ -0,0 +1,36 @@
+//go:build !windows
+// +build !windows
+
+package context
+
+import (
+	"os"
+
+	OrbitError "github.com/gulien/orbit/app/error"
+	"github.com/gulien/orbit/app/logger"
+)
+
+/*
+checkPermissions verifies that the given file is not accessible by others users,
+like ssh does with private keys.
+
+If the permissions are too open, it logs a warning or returns an error if strict is true.
+*/
+func checkPermissions(filePath string, strict bool) error {
+	info, err := os.Stat(filePath)
+	if err != nil {
+		return OrbitError.NewOrbitErrorf("unable to retrieve the permissions of the file %s. Details:\n%s", filePath, err)
+	}
+
+	if info.Mode().Perm()&0007 == 0 {
+		return nil
+	}
+
+	if strict {
+		return OrbitError.NewOrbitErrorf("permissions %s of the file %s are too open, it should not be accessible by others users", info.Mode().Perm(), filePath)
+	}
+
+	logger.Warnf("permissions %s of the file %s are too open, it should not be accessible by others users", info.Mode().Perm(), filePath)
+
+	return nil
+}
@@ -0,0 +1,42 @@
+package context
+
+import (
+	"io/ioutil"
+	"os"
+	"runtime"
+	"testing"
+)
+
+// Tests if reading a .env file accessible by others users
+// fails only if the permissions check is strict.
+func TestCheckPermissions(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("permissions are only checked on POSIX systems")
+	}
+
+	file, _ := ioutil.TempFile("", "orbit-permissions")
+	file.Close()
+	defer os.Remove(file.Name())
+
+	// case 1: uses a file accessible by others users.
+	os.Chmod(file.Name(), 0644)
+	if err := checkPermissions(file.Name(), false); err != nil {
+		t.Error("Permissions check should only have logged a warning!")
+	}
+
+	// case 2: uses a file accessible by others users with a strict check.
+	if err := checkPermissions(file.Name(), true); err == nil {
+		t.Error("Permissions check should have failed!")
+	}
+
+	// case 3: uses a file only accessible by its owner with a strict check.
+	os.Chmod(file.Name(), 0600)
+	if err := checkPermissions(file.Name(), true); err != nil {
+		t.Error("Permissions check should have been successful!")
+	}
+
+	// case 4: uses a non existing file.
+	if err := checkPermissions("non_existing_file", true); err == nil {
+		t.Error("Permissions check should have failed with a non existing file!")
+	}
+}
@@ -0,0 +1,6 @@
+package context
+
+// checkPermissions does nothing on Windows as files do not have POSIX permissions.
+func checkPermissions(filePath string, strict bool) error {
+	return nil
+}
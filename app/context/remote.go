@@ -0,0 +1,206 @@
+package context
+
+import (
+	"crypto/sha256"
+	"crypto/tls"
+	"encoding/hex"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+
+	OrbitError "github.com/gulien/orbit/app/error"
+	"github.com/gulien/orbit/app/helpers"
+	"github.com/gulien/orbit/app/logger"
+)
+
+// remoteTimeout is the maximum duration of the download of a remote file.
+const remoteTimeout = 30 * time.Second
+
+// remote cache metadata file name, next to each cached remote file.
+const remoteMetadataFileName = ".metadata.json"
+
+// remoteCacheDir returns the directory in which the remote files are cached.
+var remoteCacheDir = func() (string, error) {
+	dir, err := userCacheDir()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(dir, "orbit", "remote"), nil
+}
+
+/*
+userCacheDir returns the cache directory of the user: %LocalAppData% on Windows,
+$HOME/Library/Caches on macOS, and $XDG_CACHE_HOME or $HOME/.cache on others systems.
+*/
+func userCacheDir() (string, error) {
+	var dir string
+
+	switch runtime.GOOS {
+	case "windows":
+		if dir = os.Getenv("LocalAppData"); dir == "" {
+			return "", OrbitError.NewOrbitError("%LocalAppData% is not defined")
+		}
+	case "darwin":
+		if dir = os.Getenv("HOME"); dir == "" {
+			return "", OrbitError.NewOrbitError("$HOME is not defined")
+		}
+
+		dir = filepath.Join(dir, "Library", "Caches")
+	default:
+		if dir = os.Getenv("XDG_CACHE_HOME"); dir != "" {
+			break
+		}
+
+		if dir = os.Getenv("HOME"); dir == "" {
+			return "", OrbitError.NewOrbitError("neither $XDG_CACHE_HOME nor $HOME are defined")
+		}
+
+		dir = filepath.Join(dir, ".cache")
+	}
+
+	return dir, nil
+}
+
+// orbitRemoteMetadata contains the validators of a cached remote file, sent back to the server on the next download.
+type orbitRemoteMetadata struct {
+	// ETag is the entity tag of the cached remote file.
+	ETag string `json:"etag,omitempty"`
+
+	// LastModified is the modification date of the cached remote file.
+	LastModified string `json:"last_modified,omitempty"`
+}
+
+// IsRemote returns true if the given value is the URL of a remote file.
+func IsRemote(value string) bool {
+	return strings.HasPrefix(value, "https://") || strings.HasPrefix(value, "http://")
+}
+
+/*
+fetchRemote downloads the remote file at the given URL into the cache and returns the path of the cached file,
+which has the same name as the remote file.
+
+A cached file is downloaded again only if it has changed on the server. If the server is not reachable
+or responds with a server error (5xx), the cached file is used anyway: any other response (e.g. 404) is an error. Unless insecure is true, the URL must use HTTPS and its certificate is verified.
+*/
+func fetchRemote(rawURL string, insecure bool) (string, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return "", OrbitError.NewOrbitErrorf("unable to parse the URL %s. Details:\n%s", rawURL, err)
+	}
+
+	if u.Scheme != "https" && !insecure {
+		return "", OrbitError.NewOrbitErrorf("remote file %s is not served over HTTPS, use the flag --insecure to download it anyway", rawURL)
+	}
+
+	cacheDir, err := remoteCacheDir()
+	if err != nil {
+		return "", OrbitError.NewOrbitErrorf("unable to find the cache directory of the remote files. Details:\n%s", err)
+	}
+
+	hash := sha256.Sum256([]byte(rawURL))
+	dir := filepath.Join(cacheDir, hex.EncodeToString(hash[:]))
+
+	name := path.Base(u.Path)
+	if name == "/" || name == "." {
+		name = "index"
+	}
+
+	filePath := filepath.Join(dir, name)
+	metadataFilePath := filepath.Join(dir, remoteMetadataFileName)
+
+	var metadata orbitRemoteMetadata
+	if data, err := ioutil.ReadFile(metadataFilePath); err == nil {
+		json.Unmarshal(data, &metadata)
+	}
+
+	cached := helpers.FileExists(filePath)
+	if unavailable, err := downloadRemote(rawURL, filePath, metadataFilePath, &metadata, cached, insecure); err != nil {
+		if !cached || !unavailable {
+			return "", err
+		}
+
+		logger.Warnf("using the cached copy of remote file %s: %s", rawURL, err)
+	}
+
+	return filePath, nil
+}
+
+/*
+downloadRemote downloads the remote file at the given URL into the given file, unless the given cached file is still valid.
+If insecure is true, the certificate of the server is not verified.
+
+If the download fails, also returns true if the server is not reachable or has responded with a server error (5xx).
+*/
+func downloadRemote(rawURL string, filePath string, metadataFilePath string, metadata *orbitRemoteMetadata, cached bool, insecure bool) (bool, error) {
+	request, err := http.NewRequest(http.MethodGet, rawURL, nil)
+	if err != nil {
+		return false, OrbitError.NewOrbitErrorf("unable to download the remote file %s. Details:\n%s", rawURL, err)
+	}
+
+	if cached {
+		if metadata.ETag != "" {
+			request.Header.Set("If-None-Match", metadata.ETag)
+		}
+
+		if metadata.LastModified != "" {
+			request.Header.Set("If-Modified-Since", metadata.LastModified)
+		}
+	}
+
+	client := &http.Client{Timeout: remoteTimeout}
+	if insecure {
+		client.Transport = &http.Transport{
+			Proxy:           http.ProxyFromEnvironment,
+			TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
+		}
+	}
+
+	response, err := client.Do(request)
+	if err != nil {
+		return true, OrbitError.NewOrbitErrorf("unable to download the remote file %s. Details:\n%s", rawURL, err)
+	}
+
+	defer response.Body.Close()
+
+	if response.StatusCode == http.StatusNotModified && cached {
+		logger.Debugf("remote file %s has not changed, using its cached copy %s", rawURL, filePath)
+		return false, nil
+	}
+
+	if response.StatusCode != http.StatusOK {
+		return response.StatusCode >= 500, OrbitError.NewOrbitErrorf("unable to download the remote file %s. Details:\nserver responded with %s", rawURL, response.Status)
+	}
+
+	data, err := ioutil.ReadAll(response.Body)
+	if err != nil {
+		return true, OrbitError.NewOrbitErrorf("unable to download the remote file %s. Details:\n%s", rawURL, err)
+	}
+
+	metadata.ETag = response.Header.Get("ETag")
+	metadata.LastModified = response.Header.Get("Last-Modified")
+	metadataData, _ := json.Marshal(metadata)
+
+	if err := os.MkdirAll(filepath.Dir(filePath), 0755); err != nil {
+		return false, OrbitError.NewOrbitErrorf("unable to cache the remote file %s. Details:\n%s", rawURL, err)
+	}
+
+	if err := ioutil.WriteFile(filePath, data, 0644); err != nil {
+		return false, OrbitError.NewOrbitErrorf("unable to cache the remote file %s. Details:\n%s", rawURL, err)
+	}
+
+	if err := ioutil.WriteFile(metadataFilePath, metadataData, 0644); err != nil {
+		return false, OrbitError.NewOrbitErrorf("unable to cache the remote file %s. Details:\n%s", rawURL, err)
+	}
+
+	logger.Debugf("remote file %s has been downloaded to %s", rawURL, filePath)
+
+	return false, nil
+}
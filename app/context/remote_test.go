@@ -0,0 +1,151 @@
+package context
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+)
+
+// Tests if the remote files are downloaded, cached and downloaded again only if they have changed.
+func TestFetchRemote(t *testing.T) {
+	cacheDir, _ := ioutil.TempDir("", "orbit-remote")
+	defer os.RemoveAll(cacheDir)
+
+	defaultCacheDir := remoteCacheDir
+	remoteCacheDir = func() (string, error) { return cacheDir, nil }
+	defer func() { remoteCacheDir = defaultCacheDir }()
+
+	downloads := 0
+	status := http.StatusOK
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if status != http.StatusOK {
+			w.WriteHeader(status)
+			return
+		}
+
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+
+		downloads++
+		w.Header().Set("ETag", `"v1"`)
+		w.Write([]byte("rocket: Falcon 9\n"))
+	}))
+	defer server.Close()
+
+	url := server.URL + "/data-source.yml"
+
+	// case 1: uses a remote file over plain HTTP.
+	if _, err := fetchRemote(url, false); err == nil {
+		t.Error("Remote file over plain HTTP should not have been downloaded!")
+	}
+
+	// case 2: uses a remote file over plain HTTP with insecure.
+	filePath, err := fetchRemote(url, true)
+	if err != nil {
+		t.Fatalf("Remote file should have been downloaded, got %s!", err)
+	}
+
+	if data, _ := ioutil.ReadFile(filePath); string(data) != "rocket: Falcon 9\n" {
+		t.Errorf("Remote file should have been cached, got %q!", data)
+	}
+
+	// case 3: uses a remote file which has not changed.
+	if _, err := fetchRemote(url, true); err != nil || downloads != 1 {
+		t.Errorf("Cached remote file should have been used, got %d downloads!", downloads)
+	}
+
+	// case 4: uses an unavailable remote file with a cached copy.
+	status = http.StatusServiceUnavailable
+	if cached, err := fetchRemote(url, true); err != nil || cached != filePath {
+		t.Error("Cached copy of the remote file should have been used!")
+	}
+
+	// case 5: uses an unavailable remote file without cached copy.
+	if _, err := fetchRemote(server.URL+"/other.yml", true); err == nil {
+		t.Error("Unavailable remote file should have thrown an error!")
+	}
+
+	// case 6: uses a remote file with a cached copy which is not found anymore.
+	status = http.StatusNotFound
+	if _, err := fetchRemote(url, true); err == nil {
+		t.Error("Remote file not found should have thrown an error despite its cached copy!")
+	}
+
+	// case 7: uses a remote file with a cached copy on a server which is not reachable.
+	status = http.StatusOK
+	closed := httptest.NewServer(server.Config.Handler)
+	closedURL := closed.URL + "/data-source.yml"
+	if _, err := fetchRemote(closedURL, true); err != nil {
+		t.Fatalf("Remote file should have been downloaded, got %s!", err)
+	}
+
+	closed.Close()
+	if _, err := fetchRemote(closedURL, true); err != nil {
+		t.Errorf("Cached copy of the remote file should have been used when the server is not reachable, got %s!", err)
+	}
+
+	// case 8: uses a remote data file in the payload and a URL as raw data.
+	settings := OrbitSettings{Insecure: true}
+	ctx, err := NewOrbitContextWithSettings(filePath, "launcher,"+url+";site,"+server.URL, "", settings)
+	if err != nil {
+		t.Fatalf("OrbitContext should have been instantiated, got %s!", err)
+	}
+
+	if launcher, ok := ctx.Payload["launcher"].(map[string]interface{}); !ok || launcher["rocket"] != "Falcon 9" {
+		t.Errorf("Remote data file should have been decoded, got %v!", ctx.Payload["launcher"])
+	}
+
+	if ctx.Payload["site"] != server.URL {
+		t.Errorf("URL should have been kept as raw data, got %v!", ctx.Payload["site"])
+	}
+
+	// case 9: uses a remote data-driven template.
+	ctx, err = NewOrbitContextWithSettings(url, "", "", settings)
+	if err != nil || ctx.TemplateURL != url || ctx.TemplateFilePath != filePath {
+		t.Error("Remote data-driven template should have been downloaded!")
+	}
+
+	if dir, _ := os.Getwd(); ctx.BaseDir() != dir {
+		t.Errorf("Relative paths of a remote data-driven template should be resolved from the current directory, got %s!", ctx.BaseDir())
+	}
+
+	// case 10: uses a remote data-driven template over plain HTTP with the default settings.
+	if _, err := NewOrbitContext(url, "", ""); err == nil {
+		t.Error("Remote data-driven template over plain HTTP should not have been downloaded without insecure!")
+	}
+}
+
+// Tests if the cache directory of the user is read from the environment.
+func TestUserCacheDir(t *testing.T) {
+	if runtime.GOOS == "windows" || runtime.GOOS == "darwin" {
+		t.Skip("$XDG_CACHE_HOME is only used on others systems")
+	}
+
+	defer os.Setenv("XDG_CACHE_HOME", os.Getenv("XDG_CACHE_HOME"))
+	defer os.Setenv("HOME", os.Getenv("HOME"))
+
+	// case 1: uses $XDG_CACHE_HOME.
+	os.Setenv("XDG_CACHE_HOME", "/tmp/cache")
+	if dir, err := userCacheDir(); err != nil || dir != "/tmp/cache" {
+		t.Errorf("Cache directory should have been $XDG_CACHE_HOME, got %s!", dir)
+	}
+
+	// case 2: uses $HOME.
+	os.Setenv("XDG_CACHE_HOME", "")
+	os.Setenv("HOME", "/home/orbit")
+	if dir, err := userCacheDir(); err != nil || dir != filepath.Join("/home/orbit", ".cache") {
+		t.Errorf("Cache directory should have been in $HOME, got %s!", dir)
+	}
+
+	// case 3: uses neither $XDG_CACHE_HOME nor $HOME.
+	os.Setenv("HOME", "")
+	if _, err := userCacheDir(); err == nil {
+		t.Error("Cache directory should not have been found!")
+	}
+}
@@ -35,11 +35,15 @@ If no output file is given, prints the result to Stdout.
 */
 func generate(cmd *cobra.Command, args []string) error {
 	// first, let's instantiate our Orbit context.
-	ctx, err := context.NewOrbitContext(templateFilePath, payload, templates)
+	ctx, err := context.NewOrbitContextWithSettings(templateFilePath, payload, templates, contextSettings())
 	if err != nil {
 		return err
 	}
 
+	if err := ctx.LoadDotenv(envFiles...); err != nil {
+		return err
+	}
+
 	// then retrieves the data from the template file.
 	g := generator.NewOrbitGenerator(ctx)
 	data, err := g.Execute()
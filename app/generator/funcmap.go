@@ -3,6 +3,7 @@ package generator
 import (
 	"fmt"
 	"runtime"
+	"strconv"
 	"strings"
 
 	"github.com/gulien/orbit/app/logger"
@@ -50,3 +51,26 @@ a data-driven template by using "run".
 func run(tasks ...string) string {
 	return fmt.Sprintf("run@%s", strings.Join(tasks, ","))
 }
+
+/*
+runIf returns a string which will be parsed by a regex pattern
+in our runner. The tasks will only be called if the condition is true.
+
+This function is available in
+a data-driven template by using "runIf".
+*/
+func runIf(condition interface{}, tasks ...string) string {
+	return fmt.Sprintf("run?%v@%s", condition, strings.Join(tasks, ","))
+}
+
+/*
+notify returns a string which will be parsed by a regex pattern
+in our runner. The string is a double-quoted YAML scalar,
+so that the message may contain special characters like ": ".
+
+This function is available in
+a data-driven template by using "notify".
+*/
+func notify(message string) string {
+	return strconv.Quote("notify@" + message)
+}
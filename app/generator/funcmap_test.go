@@ -3,6 +3,8 @@ package generator
 import (
 	"runtime"
 	"testing"
+
+	"gopkg.in/yaml.v2"
 )
 
 // A dumb test to improve code coverage.
@@ -32,3 +34,24 @@ func TestRun(t *testing.T) {
 		t.Error("String returned by run function is malformated!")
 	}
 }
+
+// Tests runIf function to check if it returns a well-formed string.
+func TestRunIf(t *testing.T) {
+	if runIf(true, "explorer", "falcon") != "run?true@explorer,falcon" {
+		t.Error("String returned by runIf function is malformated!")
+	}
+}
+
+// Tests notify function to check if it returns a well-formed string.
+func TestNotify(t *testing.T) {
+	// case 1: uses a simple message.
+	if notify("Falcon 9 has landed") != `"notify@Falcon 9 has landed"` {
+		t.Error("String returned by notify function is malformated!")
+	}
+
+	// case 2: uses a message with a colon, which remains a string once parsed as YAML.
+	var commands []string
+	if err := yaml.Unmarshal([]byte("- "+notify("Falcon 9: landed")), &commands); err != nil || len(commands) != 1 || commands[0] != "notify@Falcon 9: landed" {
+		t.Errorf("String returned by notify function should have been a YAML string, got %v (%v)!", commands, err)
+	}
+}
@@ -25,7 +25,7 @@ type (
 		// context is an instance of OrbitContext.
 		context *context.OrbitContext
 
-		// funcMap contains sprig functions and custom os function.
+		// funcMap contains sprig functions, custom os function and the functions from the context.
 		funcMap template.FuncMap
 	}
 
@@ -35,6 +35,10 @@ type (
 		// The goal here is to allow the use of the syntax {{ .Orbit }}
 		// in a data-driven template.
 		Orbit map[string]interface{}
+
+		// Env will be filled by the variables from the dotenv files,
+		// allowing the use of the syntax {{ .Env.VAR }}.
+		Env map[string]string
 	}
 )
 
@@ -45,13 +49,24 @@ func NewOrbitGenerator(context *context.OrbitContext) *OrbitGenerator {
 	funcMap["verbose"] = isVerbose
 	funcMap["debug"] = isDebug
 	funcMap["run"] = run
+	funcMap["runIf"] = runIf
+	funcMap["notify"] = notify
+
+	git := newOrbitGit(context.BaseDir(), context.Settings.StrictGit)
+	funcMap["gitCommit"] = git.commit
+	funcMap["gitBranch"] = git.branch
+	funcMap["gitTag"] = git.tag
+
+	for name, function := range context.Funcs {
+		funcMap[name] = function
+	}
 
 	g := &OrbitGenerator{
 		context: context,
 		funcMap: funcMap,
 	}
 
-	logger.Debugf("generator has been instantiated with context %s", g.context)
+	logger.Debugf("generator has been instantiated with context %v", g.context)
 
 	return g
 }
@@ -62,6 +77,21 @@ Execute executes a data-driven template by applying it the data structure provid
 Returns the resulting bytes.
 */
 func (g *OrbitGenerator) Execute() (bytes.Buffer, error) {
+	return g.execute("missingkey=error")
+}
+
+/*
+ExecuteLenient executes a data-driven template like Execute does, except that
+a missing key of the data structure is replaced by its zero value instead of throwing an error.
+
+It allows reading the parts of a data-driven template which do not depend on the missing data.
+*/
+func (g *OrbitGenerator) ExecuteLenient() (bytes.Buffer, error) {
+	return g.execute("missingkey=zero")
+}
+
+// execute executes a data-driven template with the given missing key option.
+func (g *OrbitGenerator) execute(missingKey string) (bytes.Buffer, error) {
 	var (
 		files []string
 		data  bytes.Buffer
@@ -74,10 +104,11 @@ func (g *OrbitGenerator) Execute() (bytes.Buffer, error) {
 		return data, OrbitError.NewOrbitErrorf("unable to parse the template file %s. Details:\n%s", g.context.TemplateFilePath, err)
 	}
 
-	tmpl.Option("missingkey=error")
+	tmpl.Option(missingKey)
 
 	orbitData := &orbitData{
 		Orbit: g.context.Payload,
+		Env:   g.context.Env,
 	}
 
 	if err := tmpl.Execute(&data, orbitData); err != nil {
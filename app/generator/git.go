@@ -0,0 +1,93 @@
+package generator
+
+import (
+	"os/exec"
+	"strings"
+	"sync"
+
+	OrbitError "github.com/gulien/orbit/app/error"
+	"github.com/gulien/orbit/app/logger"
+)
+
+// orbitGit retrieves the git metadata of a directory, calling git only once per metadata.
+type orbitGit struct {
+	// dir is the directory from which git is called.
+	dir string
+
+	// strict makes the git functions fail if the git metadata are not available if true.
+	// Otherwise, they return an empty string.
+	strict bool
+
+	// mutex protects the values.
+	mutex sync.Mutex
+
+	// values contains the output of each git command already called.
+	values map[string]string
+}
+
+// newOrbitGit creates an instance of orbitGit for the given directory, strict if the given strict is true.
+func newOrbitGit(dir string, strict bool) *orbitGit {
+	return &orbitGit{
+		dir:    dir,
+		strict: strict,
+		values: make(map[string]string),
+	}
+}
+
+// get returns the first line of the output of git with the given arguments, calling it only once.
+func (g *orbitGit) get(args ...string) (string, error) {
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+
+	key := strings.Join(args, " ")
+	if value, ok := g.values[key]; ok {
+		return value, nil
+	}
+
+	cmd := exec.Command("git", args...)
+	cmd.Dir = g.dir
+
+	out, err := cmd.Output()
+	if err != nil {
+		if g.strict {
+			return "", OrbitError.NewOrbitErrorf("unable to retrieve the git metadata with git %s. Details:\n%s", key, err)
+		}
+
+		logger.Debugf("unable to retrieve the git metadata with git %s: %s", key, err)
+	}
+
+	value := strings.TrimSpace(strings.SplitN(string(out), "\n", 2)[0])
+	g.values[key] = value
+
+	return value, nil
+}
+
+/*
+commit returns the hash of the current git commit.
+
+This function is available in
+a data-driven template by using "gitCommit".
+*/
+func (g *orbitGit) commit() (string, error) {
+	return g.get("rev-parse", "HEAD")
+}
+
+/*
+branch returns the name of the current git branch.
+
+This function is available in
+a data-driven template by using "gitBranch".
+*/
+func (g *orbitGit) branch() (string, error) {
+	return g.get("rev-parse", "--abbrev-ref", "HEAD")
+}
+
+/*
+tag returns the first git tag pointing to the current commit, or an empty string if there is none.
+
+This function is available in
+a data-driven template by using "gitTag".
+*/
+func (g *orbitGit) tag() (string, error) {
+	return g.get("tag", "--points-at", "HEAD")
+}
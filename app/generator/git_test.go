@@ -0,0 +1,45 @@
+package generator
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// Tests if the git metadata are retrieved from a git repository.
+func TestOrbitGit(t *testing.T) {
+	dir, _ := filepath.Abs("../..")
+	if _, err := os.Stat(filepath.Join(dir, ".git")); err != nil {
+		t.Skip("the sources are not in a git repository")
+	}
+
+	// case 1: uses a git repository.
+	g := newOrbitGit(dir, false)
+	if commit, err := g.commit(); err != nil || len(commit) != 40 {
+		t.Errorf("Commit should have been retrieved, got %s!", commit)
+	}
+
+	if branch, err := g.branch(); err != nil || branch == "" {
+		t.Error("Branch should have been retrieved!")
+	}
+
+	if _, err := g.tag(); err != nil {
+		t.Error("Tag should have been retrieved!")
+	}
+
+	// case 2: uses a directory which is not a git repository.
+	tmp, _ := ioutil.TempDir("", "orbit")
+	defer os.RemoveAll(tmp)
+
+	g = newOrbitGit(tmp, false)
+	if commit, err := g.commit(); err != nil || commit != "" {
+		t.Error("Commit should have been empty outside a git repository!")
+	}
+
+	// case 3: uses a directory which is not a git repository with strict git.
+	g = newOrbitGit(tmp, true)
+	if _, err := g.commit(); err == nil {
+		t.Error("Commit should have thrown an error outside a git repository with strict git!")
+	}
+}
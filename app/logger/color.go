@@ -0,0 +1,72 @@
+package logger
+
+import (
+	"os"
+	"strings"
+
+	OrbitError "github.com/gulien/orbit/app/error"
+
+	"golang.org/x/crypto/ssh/terminal"
+)
+
+const (
+	// ColorAuto is the color mode which relies on the environment variables and the terminal detection.
+	ColorAuto = "auto"
+
+	// ColorAlways is the color mode which always enables the colors.
+	ColorAlways = "always"
+
+	// ColorNever is the color mode which always disables the colors.
+	ColorNever = "never"
+)
+
+/*
+SetColor enables or disables the colors of the output according to the given mode.
+
+When the mode is auto, the environment variable FORCE_COLOR takes precedence over
+the environment variable NO_COLOR, which takes precedence over the terminal detection.
+*/
+func SetColor(mode string) error {
+	enabled, err := useColor(mode, os.Getenv, terminal.IsTerminal(int(os.Stdout.Fd())))
+	if err != nil {
+		return err
+	}
+
+	houston.formatter.ForceColors = enabled
+	houston.formatter.DisableColors = !enabled
+
+	return nil
+}
+
+// UseColor returns true if the output is colored.
+func UseColor() bool {
+	return houston.formatter.ForceColors
+}
+
+// useColor decides if the output should be colored, from the most relevant source to the least one.
+func useColor(mode string, getenv func(string) string, isTerminal bool) (bool, error) {
+	switch mode {
+	case ColorAlways:
+		return true, nil
+	case ColorNever:
+		return false, nil
+	case ColorAuto, "":
+	default:
+		return false, OrbitError.NewOrbitErrorf("unknown color mode %s, expected %s, %s or %s", mode, ColorAuto, ColorAlways, ColorNever)
+	}
+
+	if force := getenv("FORCE_COLOR"); force != "" {
+		switch strings.ToLower(force) {
+		case "0", "false":
+			return false, nil
+		default:
+			return true, nil
+		}
+	}
+
+	if getenv("NO_COLOR") != "" {
+		return false, nil
+	}
+
+	return isTerminal, nil
+}
@@ -0,0 +1,47 @@
+package logger
+
+import "testing"
+
+// Tests if the color decision follows the precedence flag > FORCE_COLOR > NO_COLOR > terminal detection.
+func TestUseColor(t *testing.T) {
+	env := func(values map[string]string) func(string) string {
+		return func(key string) string {
+			return values[key]
+		}
+	}
+
+	// case 1: uses an unknown mode.
+	if _, err := useColor("rainbow", env(nil), true); err == nil {
+		t.Error("Unknown color mode should have thrown an error!")
+	}
+
+	// case 2: uses the never mode with FORCE_COLOR.
+	if enabled, _ := useColor(ColorNever, env(map[string]string{"FORCE_COLOR": "1"}), true); enabled {
+		t.Error("Never mode should have disabled the colors!")
+	}
+
+	// case 3: uses the always mode with NO_COLOR.
+	if enabled, _ := useColor(ColorAlways, env(map[string]string{"NO_COLOR": "1"}), false); !enabled {
+		t.Error("Always mode should have enabled the colors!")
+	}
+
+	// case 4: uses FORCE_COLOR with NO_COLOR and without terminal.
+	if enabled, _ := useColor(ColorAuto, env(map[string]string{"FORCE_COLOR": "1", "NO_COLOR": "1"}), false); !enabled {
+		t.Error("FORCE_COLOR should have enabled the colors!")
+	}
+
+	// case 5: uses FORCE_COLOR=0 with a terminal.
+	if enabled, _ := useColor(ColorAuto, env(map[string]string{"FORCE_COLOR": "0"}), true); enabled {
+		t.Error("FORCE_COLOR=0 should have disabled the colors!")
+	}
+
+	// case 6: uses NO_COLOR with a terminal.
+	if enabled, _ := useColor(ColorAuto, env(map[string]string{"NO_COLOR": "1"}), true); enabled {
+		t.Error("NO_COLOR should have disabled the colors!")
+	}
+
+	// case 7: uses the terminal detection.
+	if enabled, _ := useColor(ColorAuto, env(nil), true); !enabled {
+		t.Error("Terminal should have enabled the colors!")
+	}
+}
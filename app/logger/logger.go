@@ -4,6 +4,7 @@ Package logger implements a simple helper for displaying output to the user.
 package logger
 
 import (
+	"io"
 	"os"
 
 	OrbitError "github.com/gulien/orbit/app/error"
@@ -15,16 +16,22 @@ import (
 type orbitLogger struct {
 	// logger is an instance of logrus logger.
 	logger *logrus.Logger
+
+	// formatter is the formatter of the logrus logger.
+	formatter *logrus.TextFormatter
 }
 
 // newOrbitLogged creates an instance of orbitLogger.
 func newOrbitLogger() *orbitLogger {
+	f := &logrus.TextFormatter{}
 	l := logrus.New()
 	l.Out = os.Stdout
 	l.Level = logrus.ErrorLevel
+	l.Formatter = f
 
 	return &orbitLogger{
-		logger: l,
+		logger:    l,
+		formatter: f,
 	}
 }
 
@@ -36,6 +43,11 @@ func SetLevel(level logrus.Level) {
 	houston.logger.SetLevel(level)
 }
 
+// SetOutput updates the writer of the logs, which is Stdout by default.
+func SetOutput(w io.Writer) {
+	houston.logger.Out = w
+}
+
 // GetLevel returns the current level of messages which are logged.
 func GetLevel() logrus.Level {
 	return houston.logger.Level
@@ -51,6 +63,11 @@ func Debugf(message string, args ...interface{}) {
 	houston.logger.Debugf(message, args...)
 }
 
+// Warnf logs warning information using the Houston logger.
+func Warnf(message string, args ...interface{}) {
+	houston.logger.Warnf(message, args...)
+}
+
 // Error logs error information using the Houston logger.
 func Error(err error) {
 	if _, ok := err.(*OrbitError.OrbitError); ok {
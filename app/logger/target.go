@@ -0,0 +1,150 @@
+package logger
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"strings"
+	"sync"
+
+	OrbitError "github.com/gulien/orbit/app/error"
+
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// LogTargetStdout is the log target which writes the logs to the standard output.
+	LogTargetStdout = "stdout"
+
+	// LogTargetSyslog is the log target which sends the logs to the system logger:
+	// syslog on POSIX systems, the event log on Windows.
+	LogTargetSyslog = "syslog"
+)
+
+// systemLogger provides the severities of the system logger.
+type systemLogger interface {
+	Debug(message string) error
+	Info(message string) error
+	Warning(message string) error
+	Err(message string) error
+}
+
+// system is the system logger, if the logs are sent to it.
+var system systemLogger
+
+/*
+SetLogTarget sends the logs of the application to the given target.
+
+With the syslog target, the logs are not written to the standard output anymore:
+each entry is sent to the system logger with the severity matching its level.
+*/
+func SetLogTarget(target string) error {
+	switch target {
+	case LogTargetStdout, "":
+		return nil
+	case LogTargetSyslog:
+	default:
+		return OrbitError.NewOrbitErrorf("unknown log target %s, expected %s or %s", target, LogTargetStdout, LogTargetSyslog)
+	}
+
+	l, err := newSystemLogger()
+	if err != nil {
+		return OrbitError.NewOrbitErrorf("unable to connect to the system logger. Details:\n%s", err)
+	}
+
+	system = l
+	houston.logger.Out = ioutil.Discard
+	houston.logger.AddHook(&systemHook{logger: l})
+
+	return nil
+}
+
+// systemHook is a logrus hook which sends the entries to the system logger.
+type systemHook struct {
+	// logger is the system logger.
+	logger systemLogger
+}
+
+// Levels returns the levels of the entries sent to the system logger.
+func (hook *systemHook) Levels() []logrus.Level {
+	return logrus.AllLevels
+}
+
+// Fire sends the given entry to the system logger with the severity matching its level.
+func (hook *systemHook) Fire(entry *logrus.Entry) error {
+	switch entry.Level {
+	case logrus.DebugLevel:
+		return hook.logger.Debug(entry.Message)
+	case logrus.InfoLevel:
+		return hook.logger.Info(entry.Message)
+	case logrus.WarnLevel:
+		return hook.logger.Warning(entry.Message)
+	default:
+		return hook.logger.Err(entry.Message)
+	}
+}
+
+/*
+SystemWriters returns the writers sending the standard output and error of the commands
+to the system logger, line by line, with the info and err severities.
+
+If the logs are not sent to the system logger, returns nil writers.
+*/
+func SystemWriters() (io.WriteCloser, io.WriteCloser) {
+	if system == nil {
+		return nil, nil
+	}
+
+	return &systemWriter{send: system.Info}, &systemWriter{send: system.Err}
+}
+
+// systemWriter is a writer which sends each line to the system logger.
+type systemWriter struct {
+	// send sends a line to the system logger with a given severity.
+	send func(message string) error
+
+	// mutex protects the buffer from the commands executed in parallel.
+	mutex sync.Mutex
+
+	// buffer contains the beginning of the current line.
+	buffer bytes.Buffer
+}
+
+// Write sends the complete lines of the given data to the system logger
+// and keeps the beginning of the current line.
+func (w *systemWriter) Write(data []byte) (int, error) {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	w.buffer.Write(data)
+
+	for {
+		line, err := w.buffer.ReadString('\n')
+		if err != nil {
+			// the line is not complete yet.
+			w.buffer.WriteString(line)
+			break
+		}
+
+		if err := w.send(strings.TrimRight(line, "\r\n")); err != nil {
+			return len(data), err
+		}
+	}
+
+	return len(data), nil
+}
+
+// Close sends the current line to the system logger, if any.
+func (w *systemWriter) Close() error {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	if w.buffer.Len() == 0 {
+		return nil
+	}
+
+	line := w.buffer.String()
+	w.buffer.Reset()
+
+	return w.send(line)
+}
@@ -0,0 +1,60 @@
+package logger
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+)
+
+// fakeSystemLogger records the messages sent to the system logger, prefixed by their severities.
+type fakeSystemLogger struct {
+	messages []string
+}
+
+func (l *fakeSystemLogger) record(severity string, message string) error {
+	l.messages = append(l.messages, severity+": "+message)
+	return nil
+}
+
+func (l *fakeSystemLogger) Debug(message string) error   { return l.record("debug", message) }
+func (l *fakeSystemLogger) Info(message string) error    { return l.record("info", message) }
+func (l *fakeSystemLogger) Warning(message string) error { return l.record("warning", message) }
+func (l *fakeSystemLogger) Err(message string) error     { return l.record("err", message) }
+
+// Tests if the logs and the outputs of the commands are sent to the system logger with the right severities.
+func TestSystemLogger(t *testing.T) {
+	// case 1: uses an unknown log target.
+	if err := SetLogTarget("carrier-pigeon"); err == nil {
+		t.Error("Unknown log target should have thrown an error!")
+	}
+
+	// case 2: uses the entries of some levels.
+	l := &fakeSystemLogger{}
+	hook := &systemHook{logger: l}
+	for _, level := range []logrus.Level{logrus.DebugLevel, logrus.InfoLevel, logrus.WarnLevel, logrus.ErrorLevel} {
+		hook.Fire(&logrus.Entry{Level: level, Message: "houston"})
+	}
+
+	expected := []string{"debug: houston", "info: houston", "warning: houston", "err: houston"}
+	if !reflect.DeepEqual(l.messages, expected) {
+		t.Errorf("Entries should have been sent with the severities matching their levels, got %v!", l.messages)
+	}
+
+	// case 3: uses some outputs split across writes.
+	l = &fakeSystemLogger{}
+	w := &systemWriter{send: l.Info}
+	w.Write([]byte("I am explorer"))
+	w.Write([]byte(" task\nI am sputnik task\r\nI am"))
+	w.Close()
+
+	expected = []string{"info: I am explorer task", "info: I am sputnik task", "info: I am"}
+	if !reflect.DeepEqual(l.messages, expected) {
+		t.Errorf("Outputs should have been sent line by line, got %v!", l.messages)
+	}
+
+	// case 4: uses no system logger.
+	if stdout, stderr := SystemWriters(); stdout != nil || stderr != nil {
+		t.Error("Writers should not have been returned without system logger!")
+	}
+}
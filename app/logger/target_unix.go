@@ -0,0 +1,13 @@
+//go:build !windows
+// +build !windows
+
+package logger
+
+import (
+	"log/syslog"
+)
+
+// newSystemLogger connects to the syslog daemon, with the user facility and the orbit tag.
+func newSystemLogger() (systemLogger, error) {
+	return syslog.New(syslog.LOG_USER|syslog.LOG_INFO, "orbit")
+}
@@ -0,0 +1,44 @@
+package logger
+
+import (
+	"golang.org/x/sys/windows/svc/eventlog"
+)
+
+// eventID is the identifier of the events sent to the event log.
+const eventID = 1
+
+// eventLogger provides the severities of the system logger thanks to the Windows event log.
+type eventLogger struct {
+	// log is the handle of the event log.
+	log *eventlog.Log
+}
+
+// newSystemLogger opens the event log with the orbit source.
+func newSystemLogger() (systemLogger, error) {
+	l, err := eventlog.Open("orbit")
+	if err != nil {
+		return nil, err
+	}
+
+	return &eventLogger{log: l}, nil
+}
+
+// Debug sends the given message as an information event, as the event log has no debug level.
+func (l *eventLogger) Debug(message string) error {
+	return l.log.Info(eventID, message)
+}
+
+// Info sends the given message as an information event.
+func (l *eventLogger) Info(message string) error {
+	return l.log.Info(eventID, message)
+}
+
+// Warning sends the given message as a warning event.
+func (l *eventLogger) Warning(message string) error {
+	return l.log.Warning(eventID, message)
+}
+
+// Err sends the given message as an error event.
+func (l *eventLogger) Err(message string) error {
+	return l.log.Error(eventID, message)
+}
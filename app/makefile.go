@@ -0,0 +1,78 @@
+package app
+
+import (
+	"github.com/gulien/orbit/app/context"
+	"github.com/gulien/orbit/app/generator"
+	"github.com/gulien/orbit/app/runner"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	// makefileFilePath is the path of the resulting Makefile.
+	makefileFilePath string
+
+	// makefileCmd is the instance of makefile command.
+	makefileCmd = &cobra.Command{
+		Use:           "makefile",
+		Short:         "Generates a Makefile running the tasks defined in a configuration file",
+		Long:          "Generates a Makefile running the tasks defined in a configuration file.",
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		RunE:          makefile,
+	}
+)
+
+// init initializes a makefileCmd instance with some flags and adds it to the RootCmd.
+func init() {
+	makefileCmd.Flags().StringVarP(&makefileFilePath, "output", "o", "", "specify the Makefile which will be generated")
+	RootCmd.AddCommand(makefileCmd)
+}
+
+/*
+makefile generates a Makefile with a target for each task which is not private.
+
+If no output file is given, prints the result to Stdout.
+*/
+func makefile(cmd *cobra.Command, args []string) error {
+	// the targets run the tasks with the same configuration file, payload and templates.
+	var options []string
+	if templateFilePath == "" {
+		templateFilePath = orbitFilePath
+	} else {
+		options = append(options, "-f", templateFilePath)
+	}
+
+	if payload != "" {
+		options = append(options, "-p", payload)
+	}
+
+	if templates != "" {
+		options = append(options, "-t", templates)
+	}
+
+	for _, envFile := range envFiles {
+		options = append(options, "--env-file", envFile)
+	}
+
+	ctx, err := context.NewOrbitContextWithSettings(templateFilePath, payload, templates, contextSettings())
+	if err != nil {
+		return err
+	}
+
+	if err := ctx.LoadDotenv(envFiles...); err != nil {
+		return err
+	}
+
+	r, err := runner.NewOrbitRunner(ctx)
+	if err != nil {
+		return err
+	}
+
+	data, err := r.Makefile(options)
+	if err != nil {
+		return err
+	}
+
+	return generator.NewOrbitGenerator(ctx).Flush(makefileFilePath, data)
+}
@@ -0,0 +1,276 @@
+/*
+Package orbit allows to embed Orbit in a Go program: it runs the tasks of a configuration file and generates
+files from data-driven templates, without going through the command line interface.
+
+	o, err := orbit.Load("orbit.yml", orbit.WithData(map[string]interface{}{"env": "prod"}), orbit.WithStdout(&output))
+	if err != nil {
+		return err
+	}
+
+	results, err := o.Run(context.Background(), "build")
+
+Each instance has its own settings (see WithInsecure, WithStrictPermissions, WithStrictGit, WithoutLocalConfig and
+WithProfileOutput), so that several instances may be used at the same time. The logs are the exception: they are written
+by the logger package, which is shared by the whole process. Use logger.SetOutput and logger.SetLevel to configure them.
+*/
+package orbit
+
+import (
+	gocontext "context"
+	"io"
+	"strings"
+	"text/template"
+
+	"github.com/gulien/orbit/app/context"
+	"github.com/gulien/orbit/app/generator"
+	"github.com/gulien/orbit/app/runner"
+)
+
+type (
+	// Option configures the loading of a configuration file or of a data-driven template.
+	Option func(*options)
+
+	// options contains the settings given by the options.
+	options struct {
+		// payload is a map of YAML files, TOML files, JSON files, .env files and raw data,
+		// in the format of the flag --payload.
+		payload string
+
+		// templates contains the paths of the additional templates.
+		templates []string
+
+		// envFiles contains the paths of the .env files whose variables are accessible through {{ .Env }}.
+		envFiles []string
+
+		// data is added to the payload.
+		data map[string]interface{}
+
+		// funcs contains the additional template functions.
+		funcs template.FuncMap
+
+		// stdin is the standard input of the commands.
+		stdin io.Reader
+
+		// stdout is the writer of the standard output of the commands.
+		stdout io.Writer
+
+		// stderr is the writer of the standard error of the commands.
+		stderr io.Writer
+
+		// contextSettings contains the settings of the context, the generator and the runner.
+		contextSettings context.OrbitSettings
+	}
+
+	// Orbit is a configuration file whose tasks may be run.
+	Orbit struct {
+		// runner is the instance of OrbitRunner running the tasks.
+		runner *runner.OrbitRunner
+	}
+
+	// TaskResult represents a task which has been run.
+	TaskResult = runner.TaskResult
+)
+
+// WithPayload adds a map of YAML files, TOML files, JSON files, .env files and raw data to the payload,
+// in the format of the flag --payload: key,path;key,path;key,data...
+func WithPayload(payload string) Option {
+	return func(o *options) {
+		o.payload = payload
+	}
+}
+
+// WithData adds the given data to the payload, accessible through {{ .Orbit }}. It wins over the entries from WithPayload.
+func WithData(data map[string]interface{}) Option {
+	return func(o *options) {
+		if o.data == nil {
+			o.data = make(map[string]interface{})
+		}
+
+		for key, value := range data {
+			o.data[key] = value
+		}
+	}
+}
+
+// WithTemplates adds the given additional templates.
+func WithTemplates(filesPaths ...string) Option {
+	return func(o *options) {
+		o.templates = append(o.templates, filesPaths...)
+	}
+}
+
+// WithEnvFiles adds the given .env files, whose variables are accessible through {{ .Env }} and given to the commands.
+func WithEnvFiles(filesPaths ...string) Option {
+	return func(o *options) {
+		o.envFiles = append(o.envFiles, filesPaths...)
+	}
+}
+
+// WithFuncs adds the given functions to the template functions. They win over the built-in functions with the same names.
+func WithFuncs(funcs template.FuncMap) Option {
+	return func(o *options) {
+		if o.funcs == nil {
+			o.funcs = make(template.FuncMap)
+		}
+
+		for name, function := range funcs {
+			o.funcs[name] = function
+		}
+	}
+}
+
+// WithStdin sets the standard input of the commands, from which the confirmations are also read. It's Stdin by default.
+func WithStdin(r io.Reader) Option {
+	return func(o *options) {
+		o.stdin = r
+	}
+}
+
+// WithStdout sets the writer of the standard output of the commands and of the printed tasks. It's Stdout by default.
+func WithStdout(w io.Writer) Option {
+	return func(o *options) {
+		o.stdout = w
+	}
+}
+
+// WithStderr sets the writer of the standard error of the commands and of the prompts. It's Stderr by default.
+func WithStderr(w io.Writer) Option {
+	return func(o *options) {
+		o.stderr = w
+	}
+}
+
+// WithInsecure allows to download the remote files over plain HTTP, or over HTTPS without verifying the certificates.
+func WithInsecure() Option {
+	return func(o *options) {
+		o.contextSettings.Insecure = true
+	}
+}
+
+// WithStrictPermissions forbids reading the .env files accessible by others users. Otherwise, a warning is logged.
+func WithStrictPermissions() Option {
+	return func(o *options) {
+		o.contextSettings.StrictPermissions = true
+	}
+}
+
+// WithStrictGit makes the git functions fail if the git metadata are not available. Otherwise, they return an empty string.
+func WithStrictGit() Option {
+	return func(o *options) {
+		o.contextSettings.StrictGit = true
+	}
+}
+
+// WithoutLocalConfig disables the loading of the local configuration file (e.g. orbit.local.yml).
+func WithoutLocalConfig() Option {
+	return func(o *options) {
+		o.contextSettings.SkipLocalConfig = true
+	}
+}
+
+// WithProfileOutput makes the runner print the duration of each startup phase into the given writer.
+func WithProfileOutput(w io.Writer) Option {
+	return func(o *options) {
+		o.contextSettings.ProfileOutput = w
+	}
+}
+
+// newOptions returns the settings given by the given options.
+func newOptions(opts []Option) *options {
+	o := &options{}
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	return o
+}
+
+// newContext creates an instance of OrbitContext for the given data-driven template, populated according to the given settings.
+func newContext(templateFilePath string, settings *options) (*context.OrbitContext, error) {
+	ctx, err := context.NewOrbitContextWithSettings(templateFilePath, settings.payload, strings.Join(settings.templates, ","), settings.contextSettings)
+	if err != nil {
+		return nil, err
+	}
+
+	for key, value := range settings.data {
+		ctx.Payload[key] = value
+	}
+
+	ctx.Funcs = settings.funcs
+
+	if err := ctx.LoadDotenv(settings.envFiles...); err != nil {
+		return nil, err
+	}
+
+	return ctx, nil
+}
+
+// Load reads the given configuration file, which may be a local path or a URL, with the given options.
+func Load(configFilePath string, opts ...Option) (*Orbit, error) {
+	settings := newOptions(opts)
+
+	ctx, err := newContext(configFilePath, settings)
+	if err != nil {
+		return nil, err
+	}
+
+	r, err := runner.NewOrbitRunner(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if settings.stdin != nil {
+		r.Stdin = settings.stdin
+	}
+
+	if settings.stdout != nil {
+		r.Stdout = settings.stdout
+	}
+
+	if settings.stderr != nil {
+		r.Stderr = settings.stderr
+	}
+
+	return &Orbit{runner: r}, nil
+}
+
+// Runner returns the instance of OrbitRunner running the tasks, e.g. to enable a dry run or to print the tasks.
+func (o *Orbit) Runner() *runner.OrbitRunner {
+	return o.runner
+}
+
+/*
+Run runs the given tasks, whose commands are cancelled once the given context is done.
+
+Returns the tasks which have been run, including the tasks they call, in the order they have ended.
+If a task has failed, the error is returned with the results of the tasks run until then.
+*/
+func (o *Orbit) Run(ctx gocontext.Context, names ...string) ([]TaskResult, error) {
+	return o.RunWithArgs(ctx, nil, names...)
+}
+
+// RunWithArgs runs the given tasks like Run does, except that their commands receive the given arguments.
+func (o *Orbit) RunWithArgs(ctx gocontext.Context, args []string, names ...string) ([]TaskResult, error) {
+	previous := len(o.runner.Results())
+
+	o.runner.Context = ctx
+	err := o.runner.RunWithArgs(args, names...)
+
+	return o.runner.Results()[previous:], err
+}
+
+// Generate executes the given data-driven template, which may be a local path or a URL, with the given options.
+// The options about the commands are ignored. Returns the resulting bytes.
+func Generate(templateFilePath string, opts ...Option) ([]byte, error) {
+	ctx, err := newContext(templateFilePath, newOptions(opts))
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := generator.NewOrbitGenerator(ctx).Execute()
+	if err != nil {
+		return nil, err
+	}
+
+	return data.Bytes(), nil
+}
@@ -0,0 +1,93 @@
+package orbit
+
+import (
+	"bytes"
+	gocontext "context"
+	"path/filepath"
+	"strings"
+	"testing"
+	"text/template"
+)
+
+// Tests if a configuration file is loaded and its tasks are run with the given options.
+func TestLoad(t *testing.T) {
+	configFilePath, _ := filepath.Abs("../../_tests/orbit-embed.yml")
+	funcs := template.FuncMap{"shout": strings.ToUpper}
+
+	// case 1: uses a configuration file without the data it requires.
+	if _, err := Load(configFilePath, WithFuncs(funcs)); err == nil {
+		t.Error("Configuration file without its data should not have been loaded!")
+	}
+
+	// case 2: uses a task printing the given data with the given function.
+	var stdout bytes.Buffer
+	o, err := Load(configFilePath, WithData(map[string]interface{}{"name": "orbit"}), WithFuncs(funcs), WithStdout(&stdout))
+	if err != nil {
+		t.Fatalf("Configuration file should have been loaded, got %s!", err)
+	}
+
+	results, err := o.Run(gocontext.Background(), "greet")
+	if err != nil || len(results) != 1 || results[0].Task != "greet" || results[0].Err != nil {
+		t.Errorf("Task should have succeeded, got %v and %v!", err, results)
+	}
+
+	if !strings.Contains(stdout.String(), "ORBIT") {
+		t.Errorf("Task should have printed to the given writer, got %q!", stdout.String())
+	}
+
+	// case 3: uses a task calling another task then failing.
+	results, err = o.Run(gocontext.Background(), "launch")
+	if err == nil || len(results) != 2 || results[0].Task != "greet" || results[1].Task != "launch" {
+		t.Fatalf("Task should have failed after the task it calls, got %v and %v!", err, results)
+	}
+
+	if results[1].Err == nil || len(results[1].Failures) != 1 || results[1].Failures[0].ExitCode != 3 {
+		t.Errorf("Failed command should have been returned with its exit code, got %v!", results[1].Failures)
+	}
+}
+
+// Tests if a data-driven template is executed with the given options.
+func TestGenerate(t *testing.T) {
+	templateFilePath, _ := filepath.Abs("../../_tests/orbit-embed.yml")
+
+	// case 1: uses the given data and function.
+	data, err := Generate(templateFilePath, WithData(map[string]interface{}{"name": "orbit"}), WithFuncs(template.FuncMap{"shout": strings.ToUpper}))
+	if err != nil || !strings.Contains(string(data), `echo "ORBIT"`) {
+		t.Errorf("Data-driven template should have been executed, got %v and %q!", err, data)
+	}
+
+	// case 2: uses a data-driven template which does not exist.
+	if _, err := Generate("../../_tests/orbit-embed.toml"); err == nil {
+		t.Error("Data-driven template which does not exist should not have been executed!")
+	}
+}
+
+// Tests if each instance has its own settings.
+func TestSettings(t *testing.T) {
+	configFilePath, _ := filepath.Abs("../../_tests/orbit-local.yml")
+
+	var profile bytes.Buffer
+	withoutLocal, err := Load(configFilePath, WithoutLocalConfig(), WithProfileOutput(&profile), WithStdout(&bytes.Buffer{}))
+	if err != nil {
+		t.Fatalf("Configuration file should have been loaded, got %s!", err)
+	}
+
+	withLocal, err := Load(configFilePath, WithStdout(&bytes.Buffer{}))
+	if err != nil {
+		t.Fatalf("Configuration file should have been loaded, got %s!", err)
+	}
+
+	// case 1: uses an instance skipping the local configuration file.
+	if _, err := withoutLocal.Run(gocontext.Background(), "vostok"); err == nil {
+		t.Error("Task from the local configuration file should not have been loaded!")
+	}
+
+	if !strings.Contains(profile.String(), "startup: local configuration took ") {
+		t.Errorf("Startup phases should have been printed into the given writer, got %q!", profile.String())
+	}
+
+	// case 2: uses an instance with the default settings.
+	if _, err := withLocal.Run(gocontext.Background(), "vostok"); err != nil {
+		t.Errorf("Task from the local configuration file should have been loaded, got %s!", err)
+	}
+}
@@ -2,6 +2,7 @@
 package app
 
 import (
+	"github.com/gulien/orbit/app/context"
 	"github.com/gulien/orbit/app/logger"
 
 	"github.com/sirupsen/logrus"
@@ -20,19 +21,43 @@ var (
 	// Value format: path,path,path...
 	templates string
 
+	// envFiles contains the paths of the .env files whose variables are accessible through {{ .Env }}.
+	envFiles []string
+
 	// verbose enables info logs if true.
 	verbose bool
 
 	// debug enables debug logs if true.
 	debug bool
 
+	// strictPermissions forbids reading .env files accessible by others users if true.
+	strictPermissions bool
+
+	// insecure allows to download remote files over plain HTTP or without verifying the certificates if true.
+	insecure bool
+
+	// strictGit makes the git functions fail if the git metadata are not available if true.
+	strictGit bool
+
+	// noLocal disables the loading of the local configuration file if true.
+	noLocal bool
+
+	// logTarget is the target of the logs: stdout or syslog.
+	logTarget string
+
+	// color is the color mode of the output: auto, always or never.
+	color string
+
+	// noColor disables the colors of the output if true. Alias of "--color never".
+	noColor bool
+
 	// RootCmd is the instance of the root of all commands.
 	RootCmd = &cobra.Command{
 		Use:           "orbit",
 		Short:         "A cross-platform task runner for executing commands and generating files from templates",
 		Long:          "A cross-platform task runner for executing commands and generating files from templates.",
 		SilenceErrors: true,
-		PersistentPreRun: func(cmd *cobra.Command, args []string) {
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
 			if verbose {
 				logger.SetLevel(logrus.InfoLevel)
 			}
@@ -40,6 +65,16 @@ var (
 			if debug {
 				logger.SetLevel(logrus.DebugLevel)
 			}
+
+			if noColor {
+				color = logger.ColorNever
+			}
+
+			if err := logger.SetColor(color); err != nil {
+				return err
+			}
+
+			return logger.SetLogTarget(logTarget)
 		},
 	}
 )
@@ -48,6 +83,24 @@ func init() {
 	RootCmd.PersistentFlags().StringVarP(&templateFilePath, "file", "f", "", "specify the path of a data-driven template")
 	RootCmd.PersistentFlags().StringVarP(&payload, "payload", "p", "", "specify a map of YAML files, TOML files, JSON files, .env files and raw data")
 	RootCmd.PersistentFlags().StringVarP(&templates, "templates", "t", "", "specify a map of additional templates")
+	RootCmd.PersistentFlags().StringArrayVar(&envFiles, "env-file", nil, "specify a .env file whose variables are accessible through {{ .Env }} and given to the commands")
 	RootCmd.PersistentFlags().BoolVarP(&verbose, "verbose", "v", false, "set logging to info level")
 	RootCmd.PersistentFlags().BoolVarP(&debug, "debug", "d", false, "set logging to debug level")
+	RootCmd.PersistentFlags().StringVar(&logTarget, "log-target", logger.LogTargetStdout, "set the target of the logs: stdout or syslog (event log on Windows)")
+	RootCmd.PersistentFlags().StringVar(&color, "color", logger.ColorAuto, "set the color mode of the output: auto, always or never")
+	RootCmd.PersistentFlags().BoolVar(&noColor, "no-color", false, "disable the colors of the output, alias of --color never")
+	RootCmd.PersistentFlags().BoolVar(&insecure, "insecure", false, "allow to download remote files over plain HTTP or without verifying the certificates")
+	RootCmd.PersistentFlags().BoolVar(&strictGit, "strict-git", false, "make the git functions fail if the git metadata are not available")
+	RootCmd.PersistentFlags().BoolVar(&noLocal, "no-local", false, "do not merge the local configuration file (e.g. orbit.local.yml)")
+	RootCmd.PersistentFlags().BoolVar(&strictPermissions, "strict-permissions", false, "forbid reading .env files accessible by others users")
+}
+
+// contextSettings returns the settings of the contexts, given by the flags.
+func contextSettings() context.OrbitSettings {
+	return context.OrbitSettings{
+		Insecure:          insecure,
+		StrictPermissions: strictPermissions,
+		StrictGit:         strictGit,
+		SkipLocalConfig:   noLocal,
+	}
 }
@@ -1,7 +1,18 @@
 package app
 
 import (
+	gocontext "context"
+	"fmt"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
 	"github.com/gulien/orbit/app/context"
+	OrbitError "github.com/gulien/orbit/app/error"
+	"github.com/gulien/orbit/app/logger"
 	"github.com/gulien/orbit/app/runner"
 
 	"github.com/spf13/cobra"
@@ -11,46 +22,454 @@ import (
 const orbitFilePath = "orbit.yml"
 
 var (
+	// listCommands is the name of the task from which the commands should be printed.
+	listCommands string
+
+	// expand enables the expansion of the commands calling others tasks if true.
+	expand bool
+
+	// envPrefix is the prefix of the environment variables injected by Orbit.
+	envPrefix string
+
+	// workingEnv enables the capture of the environment exported from one command to another if true.
+	workingEnv bool
+
+	// durationThreshold is the duration above which a command is reported as slow.
+	durationThreshold time.Duration
+
+	// gracePeriod is the duration between the termination signal sent to a cancelled command and its kill.
+	gracePeriod time.Duration
+
+	// failSummaryFilePath is the path of the file which will contain the commands which have failed.
+	failSummaryFilePath string
+
+	// sortTasks is the order of the printed tasks.
+	sortTasks string
+
+	// yes allows to execute the destructive commands without confirmation if true.
+	yes bool
+
+	// output is the output mode.
+	output string
+
+	// depth is the number of namespace levels displayed when printing the tasks.
+	depth int
+
+	// continueFrom is the name of the task from which the given tasks are run.
+	continueFrom string
+
+	// printPlan enables the printing of the tasks to run with their last durations if true.
+	printPlan bool
+
+	// group is the name of the group of tasks to run.
+	group string
+
+	// pushgatewayURL is the URL of the Prometheus pushgateway which receives the metrics of the tasks.
+	pushgatewayURL string
+
+	// sinceCommit is the git reference since which the sources of the given tasks should have changed.
+	sinceCommit string
+
+	// skipWithoutSources skips the tasks without sources when selecting the changed tasks if true.
+	skipWithoutSources bool
+
+	// interactiveEnv enables the prompt of the missing required environment variables if true.
+	interactiveEnv bool
+
+	// force runs the tasks having a run_if_changed or a generates attribute even if none of their files has changed if true.
+	force bool
+
+	// stdinFilePath is the path of the file given as standard input to the commands.
+	stdinFilePath string
+
+	// dir is the working directory of the commands.
+	dir string
+
+	// format is the format of the printed tasks.
+	format string
+
+	// profileStartup enables the printing of the duration of each startup phase if true.
+	profileStartup bool
+
+	// listPrivateDeps enables the printing of the tasks called by each task which is not private if true.
+	listPrivateDeps bool
+
+	// shuffle is either "off", "on" or the seed of the order in which the given tasks are run.
+	shuffle string
+
+	// printEffectiveShell is the name of the task from which the shell invocations of the commands should be printed.
+	printEffectiveShell string
+
+	// dryRun enables the printing of the commands instead of their execution if true.
+	dryRun bool
+
+	// explain enables the details of the steps of a dry run if true.
+	explain bool
+
+	// maxWorkers is the maximum number of commands executed in parallel.
+	maxWorkers int
+
+	// outputBufferSize is the maximum size of an incomplete line of the prefixed outputs.
+	outputBufferSize int
+
+	// prefixOutput enables the prefixing of the outputs of the commands by their task if true.
+	prefixOutput bool
+
+	// noInteractive disables the interactive picker of the task to run if true.
+	noInteractive bool
+
+	// timestamps enables the prefixing of the outputs of the commands by their time if true.
+	timestamps bool
+
+	// logFilePath is the path of the file receiving a copy of the outputs of the commands.
+	logFilePath string
+
+	// logTaskOutput enables the sending of the outputs of the commands to the system logger if true.
+	logTaskOutput bool
+
+	// showCommands enables the printing of the commands of each task with the tasks if true.
+	showCommands bool
+
+	// report is the format and the path of the report of the run, e.g. junit:report.xml or json:report.json.
+	report string
+
+	// list enables the printing of the available tasks, even if a default task is defined, if true.
+	list bool
+
+	// check enables the validation of the configuration file if true.
+	check bool
+
 	// runCmd is the instance of run command.
 	runCmd = &cobra.Command{
 		Use:           "run",
 		Short:         "Runs one or more tasks defined in a configuration file",
-		Long:          "Runs one or more tasks defined in a configuration file.",
+		Long:          "Runs one or more tasks defined in a configuration file.\nThe arguments after -- are given to the tasks, e.g. orbit run release -- myapp 1.2.0.",
 		SilenceUsage:  true,
 		SilenceErrors: true,
 		RunE:          run,
 	}
 )
 
-// init initializes a runCmd instance and adds it to the RootCmd.
+// init initializes a runCmd instance with some flags and adds it to the RootCmd.
 func init() {
+	runCmd.Flags().StringVar(&listCommands, "list-commands", "", "print the commands of the given task, one per line")
+	runCmd.Flags().BoolVar(&expand, "expand", false, "expand the commands calling others tasks when printing commands")
+	runCmd.Flags().StringVar(&envPrefix, "env-prefix", runner.DefaultEnvPrefix, "specify the prefix of the environment variables injected by Orbit")
+	runCmd.Flags().BoolVar(&check, "check", false, "validate the configuration file without executing any command")
+	runCmd.Flags().BoolVar(&workingEnv, "working-env", false, "carry the environment exported by a command into the next command of the same task (POSIX only)")
+	runCmd.Flags().DurationVar(&durationThreshold, "print-duration-threshold", 0, "report the commands which run longer than the given duration (e.g. 30s)")
+	runCmd.Flags().DurationVar(&gracePeriod, "grace-period", runner.DefaultGracePeriod, "specify the duration between the termination signal sent to a cancelled command and its kill (POSIX only)")
+	runCmd.Flags().StringVar(&failSummaryFilePath, "fail-summary-file", "", "write the commands which have failed into the given file as JSON")
+	runCmd.Flags().StringVar(&sortTasks, "sort", runner.SortByOrder, "sort the printed tasks by order of declaration or by name (order|name)")
+	runCmd.Flags().BoolVarP(&yes, "yes", "y", false, "execute the destructive commands without asking for a confirmation")
+	runCmd.Flags().StringVar(&output, "output", runner.DefaultOutput, "set the output mode (default|teamcity)")
+	runCmd.Flags().IntVar(&depth, "depth", 0, "collapse the printed tasks into their namespaces at the given depth")
+	runCmd.Flags().StringVar(&continueFrom, "continue-from", "", "skip the given tasks which are before the given task")
+	runCmd.Flags().BoolVar(&printPlan, "print-plan", false, "print the given tasks and the tasks they call with the durations of their last runs")
+	runCmd.Flags().StringVar(&group, "group", "", "run the tasks of the given group before the given tasks")
+	runCmd.Flags().StringVar(&pushgatewayURL, "pushgateway-url", os.Getenv(runner.PushgatewayURLEnvVariable), "push the durations and the outcomes of the tasks to the given Prometheus pushgateway")
+	runCmd.Flags().StringVar(&sinceCommit, "since-commit", "", "only run the given tasks whose sources have changed since the given git reference")
+	runCmd.Flags().BoolVar(&skipWithoutSources, "skip-without-sources", false, "skip the tasks without sources when using --since-commit")
+	runCmd.Flags().BoolVar(&interactiveEnv, "interactive-env", false, "ask for the missing required environment variables if the standard input is a terminal")
+	runCmd.Flags().BoolVar(&force, "force", false, "run the tasks having a run_if_changed or a generates attribute even if none of their files has changed")
+	runCmd.Flags().StringVar(&stdinFilePath, "stdin-file", "", "give the content of the given file as standard input to the commands")
+	runCmd.Flags().StringVar(&dir, "dir", "", "run the commands in the given directory, overriding the dir attribute of the tasks")
+	runCmd.Flags().StringVar(&format, "format", runner.TableFormat, "set the format of the printed tasks (table|plain|csv|json|yaml) and of the dry run (json)")
+	runCmd.Flags().BoolVar(&profileStartup, "profile-startup", false, "print the duration of each startup phase to Stderr")
+	runCmd.Flags().BoolVar(&listPrivateDeps, "list-private-deps", false, "print the tree of the tasks called by each task which is not private")
+	runCmd.Flags().StringVar(&shuffle, "shuffle", "off", "run the given tasks in a random order (off|on|seed)")
+	runCmd.Flags().Lookup("shuffle").NoOptDefVal = "on"
+	runCmd.Flags().StringVar(&printEffectiveShell, "print-effective-shell-per-command", "", "print the shell invocation of each command of the given task")
+	runCmd.Flags().BoolVar(&dryRun, "dry-run", false, "print the commands which would be executed, with their shell, without executing them")
+	runCmd.Flags().BoolVar(&explain, "explain", false, "detail each step of the dry run: its command, working directory, variables, retries, and the skipped steps")
+	runCmd.Flags().IntVar(&maxWorkers, "max-workers", 0, "set the maximum number of commands executed in parallel (0 for no limit)")
+	runCmd.Flags().BoolVar(&prefixOutput, "prefix-output", false, "prefix the outputs of the commands by their task, and by their index if they are executed in parallel")
+	runCmd.Flags().IntVar(&outputBufferSize, "output-buffer-size", runner.DefaultOutputBufferSize, "set the maximum size in bytes of an incomplete line of the prefixed outputs, a longer line being written in parts (0 for no limit)")
+	runCmd.Flags().BoolVar(&noInteractive, "no-interactive", false, "print the available tasks instead of picking one interactively when no task is given")
+	runCmd.Flags().BoolVar(&timestamps, "timestamps", false, "prefix the outputs of the commands by their time")
+	runCmd.Flags().StringVar(&logFilePath, "log-file", "", "specify a file receiving a copy of the outputs of the commands, prefixed by their time and task")
+	runCmd.Flags().BoolVar(&logTaskOutput, "log-task-output", false, "send the outputs of the commands to the system logger too, with --log-target syslog")
+	runCmd.Flags().BoolVar(&showCommands, "show-commands", false, "print the commands of each task with the tasks (table and plain formats)")
+	runCmd.Flags().StringVar(&report, "report", "", "write a report of the run into a file (junit:path or json:path)")
+	runCmd.Flags().BoolVar(&list, "list", false, "print the available tasks, even if a default task is defined")
 	RootCmd.AddCommand(runCmd)
 }
 
 // run runs one or more tasks defined in a configuration file.
 func run(cmd *cobra.Command, args []string) error {
+	// the explain flag details a dry run: it never executes anything by itself.
+	if explain && !dryRun {
+		return OrbitError.NewOrbitErrorf("flag --explain requires the flag --dry-run")
+	}
+
 	// alright, let's instantiate our Orbit context...
 	if templateFilePath == "" {
 		templateFilePath = orbitFilePath
 	}
 
-	ctx, err := context.NewOrbitContext(templateFilePath, payload, templates)
+	settings := contextSettings()
+	if profileStartup {
+		settings.ProfileOutput = os.Stderr
+	}
+
+	start := time.Now()
+
+	ctx, err := context.NewOrbitContextWithSettings(templateFilePath, payload, templates, settings)
 	if err != nil {
 		return err
 	}
 
-	// then our runner...
-	r, err := runner.NewOrbitRunner(ctx)
-	if err != nil {
+	if err := ctx.LoadDotenv(envFiles...); err != nil {
 		return err
 	}
 
-	// if no args, prints the available tasks to Stdout...
-	if len(args) == 0 {
-		r.Print()
+	runner.ProfilePhase(settings.ProfileOutput, "configuration read", start)
+
+	// then our runner...
+	// with the check flag, the problems found while loading the configuration file are reported with the others.
+	r, loadProblems := runner.LoadOrbitRunner(ctx)
+	if r == nil || (len(loadProblems) > 0 && !check) {
+		return loadProblems[0]
+	}
+
+	r.EnvPrefix = envPrefix
+	r.WorkingEnv = workingEnv
+	r.DurationThreshold = durationThreshold
+	r.GracePeriod = gracePeriod
+	r.Sort = sortTasks
+	r.Depth = depth
+	r.Format = format
+	r.ShowCommands = showCommands
+	r.LogTaskOutput = logTaskOutput
+	r.MaxWorkers = maxWorkers
+	r.DryRun = dryRun
+	r.Explain = explain
+	r.PrefixOutput = prefixOutput
+	r.OutputBufferSize = outputBufferSize
+	r.Timestamps = timestamps
+	r.Yes = yes
+	r.Output = output
+	r.PushgatewayURL = pushgatewayURL
+	r.InteractiveEnv = interactiveEnv
+	r.Force = force
+	r.StdinFile = stdinFilePath
+	r.Dir = dir
+
+	// copies the outputs of the commands to the log file...
+	if logFilePath != "" {
+		file, err := os.Create(logFilePath)
+		if err != nil {
+			return OrbitError.NewOrbitErrorf("unable to create the log file %s. Details:\n%s", logFilePath, err)
+		}
+
+		defer file.Close()
+		r.LogOutput = file
+	}
+
+	// if the check flag has been given, reports all the problems of the configuration file...
+	if check {
+		problems := append(loadProblems, r.Check()...)
+		for _, problem := range problems {
+			logger.Error(problem)
+		}
+
+		if len(problems) > 0 {
+			return OrbitError.NewOrbitErrorf("configuration file %s has %d problem(s)", templateFilePath, len(problems))
+		}
+
 		return nil
 	}
 
-	// ... or runs given tasks.
-	return r.Run(args[:]...)
+	// if a task has been given with the list-commands flag, prints its commands to Stdout...
+	if listCommands != "" {
+		return r.PrintCommands(listCommands, expand)
+	}
+
+	// the arguments after "--" are given to the tasks...
+	var taskArgs []string
+	if dash := cmd.ArgsLenAtDash(); dash >= 0 {
+		args, taskArgs = args[:dash], args[dash:]
+	}
+
+	// shuffles the given tasks...
+	if shuffle != "off" && len(args) > 0 {
+		args, err = shuffleTasks(shuffle, args)
+		if err != nil {
+			return err
+		}
+	}
+
+	// if a group has been given, its tasks are run first...
+	if group != "" {
+		tasks, err := r.GroupTasks(group)
+		if err != nil {
+			return err
+		}
+
+		args = append(tasks, args...)
+	}
+
+	// prints the shell invocations of the commands of the given task...
+	if printEffectiveShell != "" {
+		return r.PrintEffectiveShell(printEffectiveShell)
+	}
+
+	// prints the tasks called by the public tasks...
+	if listPrivateDeps {
+		return r.PrintPrivateDeps()
+	}
+
+	// if no args, runs the default task if any...
+	if len(args) == 0 && !list {
+		name, ok, err := r.DefaultTask()
+		if err != nil {
+			return err
+		}
+
+		if ok {
+			args = []string{name}
+		}
+	}
+
+	// or lets the user pick the task, if Orbit is run from a terminal...
+	if len(args) == 0 && !list && !noInteractive {
+		name, ok, err := r.PickTask()
+		if err != nil {
+			return err
+		}
+
+		if ok {
+			args = []string{name}
+		}
+	}
+
+	// or prints the available tasks to Stdout...
+	if len(args) == 0 || list {
+		return r.Print()
+	}
+
+	// skips the given tasks before the continue-from task...
+	if continueFrom != "" {
+		args, err = skipUntil(continueFrom, args)
+		if err != nil {
+			return err
+		}
+	}
+
+	// keeps the given tasks whose sources have changed...
+	if sinceCommit != "" {
+		args, err = r.ChangedTasks(sinceCommit, args, skipWithoutSources)
+		if err != nil {
+			return err
+		}
+	}
+
+	// ... then prints the plan of the given tasks...
+	if printPlan {
+		return r.PrintPlan(args...)
+	}
+
+	var reportFormat, reportFilePath string
+	if report != "" {
+		if reportFormat, reportFilePath, err = parseReport(report); err != nil {
+			return err
+		}
+
+		r.RecordCommands = reportFormat == runner.JSONReport
+	}
+
+	// ... or runs them, cancelling their commands if Orbit is interrupted.
+	signalContext, stop := newSignalContext()
+	defer stop()
+
+	r.Context = signalContext
+	err = r.RunWithArgs(taskArgs, args...)
+
+	// the fail summary and the report are written whatever the result of the tasks.
+	if failSummaryFilePath != "" {
+		if summaryErr := r.WriteFailSummary(failSummaryFilePath); summaryErr != nil {
+			if err != nil {
+				logger.Error(summaryErr)
+				return err
+			}
+
+			return summaryErr
+		}
+	}
+
+	if reportFilePath != "" {
+		writeReport := r.WriteJUnitReport
+		if reportFormat == runner.JSONReport {
+			writeReport = r.WriteJSONReport
+		}
+
+		if reportErr := writeReport(reportFilePath); reportErr != nil {
+			if err != nil {
+				logger.Error(reportErr)
+				return err
+			}
+
+			return reportErr
+		}
+	}
+
+	return err
+}
+
+// newSignalContext returns a context cancelled once Orbit is interrupted or terminated, and a function releasing it.
+func newSignalContext() (gocontext.Context, func()) {
+	ctx, cancel := gocontext.WithCancel(gocontext.Background())
+
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+
+	go func() {
+		select {
+		case <-signals:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	return ctx, func() {
+		signal.Stop(signals)
+		cancel()
+	}
+}
+
+// parseReport returns the format and the path of the report file from the given report flag value, e.g. junit:report.xml.
+func parseReport(value string) (string, string, error) {
+	parts := strings.SplitN(value, ":", 2)
+	if len(parts) != 2 || (parts[0] != runner.JUnitReport && parts[0] != runner.JSONReport) || parts[1] == "" {
+		return "", "", OrbitError.NewOrbitErrorf("report %s is not valid, expected %s:path or %s:path", value, runner.JUnitReport, runner.JSONReport)
+	}
+
+	return parts[0], parts[1], nil
+}
+
+// shuffleTasks returns the given tasks in a random order, printing the seed to Stderr so that the order is reproducible.
+func shuffleTasks(value string, tasks []string) ([]string, error) {
+	seed := time.Now().UnixNano()
+	if value != "on" {
+		var err error
+		if seed, err = strconv.ParseInt(value, 10, 64); err != nil {
+			return nil, OrbitError.NewOrbitErrorf("shuffle %s is neither off, on nor a seed", value)
+		}
+	}
+
+	fmt.Fprintf(os.Stderr, "running the tasks in a random order with --shuffle=%d\n", seed)
+
+	return runner.ShuffleTasks(tasks, seed), nil
+}
+
+// skipUntil returns the given tasks starting from the given task.
+func skipUntil(name string, tasks []string) ([]string, error) {
+	for index, task := range tasks {
+		if task == name {
+			logger.Infof("skipping tasks %s", tasks[:index])
+			return tasks[index:], nil
+		}
+	}
+
+	return nil, OrbitError.NewOrbitErrorf("task %s from continue-from flag is not in the given tasks %s", name, tasks)
 }
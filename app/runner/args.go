@@ -0,0 +1,54 @@
+package runner
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	OrbitError "github.com/gulien/orbit/app/error"
+)
+
+// argNameRegexp matches the valid names of the arguments declared by a task.
+var argNameRegexp = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// checkArgs returns an error if the given task declares arguments and does not receive as many values.
+func checkArgs(task *orbitTask, args []string) error {
+	if len(task.Args) == 0 || len(task.Args) == len(args) {
+		return nil
+	}
+
+	return OrbitError.NewOrbitErrorf("task %s expects the arguments %s, got %d value(s)", task.Use, strings.Join(task.Args, ", "), len(args))
+}
+
+/*
+argsEnv returns the environment variables holding the values of the arguments declared by the given running task.
+
+Each variable is named after its argument, e.g. the argument version is given
+as ORBIT_ARG_VERSION, the prefix being the one from EnvPrefix. As the configuration file has been
+executed by the generator before any task receives the values, they are not template data.
+*/
+func (r *OrbitRunner) argsEnv(state *orbitTaskState) []string {
+	var env []string
+	for index, name := range state.task.Args {
+		if index >= len(state.args) {
+			break
+		}
+
+		env = append(env, fmt.Sprintf("%sARG_%s=%s", r.EnvPrefix, strings.ToUpper(name), state.args[index]))
+	}
+
+	return env
+}
+
+/*
+positionalArgs returns the parameters appended to the shell invocation of a command from the given task
+so that the given arguments are available as $1, $2, etc.: the name of the task, which becomes $0,
+followed by the arguments. If there are no arguments, returns nil.
+*/
+func positionalArgs(task *orbitTask, args []string) []string {
+	if len(args) == 0 {
+		return nil
+	}
+
+	return append([]string{task.Use}, args...)
+}
@@ -0,0 +1,223 @@
+package runner
+
+import (
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	OrbitError "github.com/gulien/orbit/app/error"
+	"github.com/gulien/orbit/app/logger"
+)
+
+/*
+ChangedTasks returns the given tasks whose sources match at least one file
+changed since the given git reference.
+
+The changed files are given by git, relative to the directory of the configuration file.
+The tasks without sources are returned unless skipWithoutSources is true.
+*/
+func (r *OrbitRunner) ChangedTasks(ref string, names []string, skipWithoutSources bool) ([]string, error) {
+	files, err := gitChangedFiles(r.context.BaseDir(), ref)
+	if err != nil {
+		return nil, err
+	}
+
+	logger.Debugf("files changed since %s: %s", ref, files)
+
+	var tasks []string
+	for _, name := range names {
+		task := r.getTask(name)
+		if task == nil {
+			return nil, OrbitError.NewOrbitErrorf("task %s does not exist in configuration file %s", name, r.context.TemplateFilePath)
+		}
+
+		if len(task.Sources) == 0 {
+			if skipWithoutSources {
+				logger.Infof("skipping task %s as it has no sources", name)
+				continue
+			}
+
+			tasks = append(tasks, name)
+			continue
+		}
+
+		if matchSources(task.Sources, files) {
+			tasks = append(tasks, name)
+		} else {
+			logger.Infof("skipping task %s as none of its sources has changed since %s", name, ref)
+		}
+	}
+
+	return tasks, nil
+}
+
+// gitChangedFiles returns the files changed since the given git reference, relative to the given directory.
+func gitChangedFiles(dir string, ref string) ([]string, error) {
+	cmd := exec.Command("git", "diff", "--name-only", "--relative", ref)
+	cmd.Dir = dir
+
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, OrbitError.NewOrbitErrorf("unable to get the files changed since %s. Details:\n%s", ref, err)
+	}
+
+	var files []string
+	for _, line := range strings.Split(string(out), "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			files = append(files, line)
+		}
+	}
+
+	return files, nil
+}
+
+/*
+sourcesRoots returns the directories which may contain files matching the given glob patterns, relative
+to the configuration file and with slashes: the static prefixes of the patterns, without the ones inside
+another prefix. An empty string stands for the directory of the configuration file.
+
+The patterns outside the directory of the configuration file never match a file, so they have no prefix.
+*/
+func sourcesRoots(patterns []string) []string {
+	var prefixes []string
+	for _, pattern := range patterns {
+		pattern = path.Clean(filepath.ToSlash(pattern))
+		if path.IsAbs(pattern) || pattern == ".." || strings.HasPrefix(pattern, "../") {
+			continue
+		}
+
+		var static []string
+		for _, segment := range strings.Split(pattern, "/") {
+			if hasGlobMeta(segment) {
+				break
+			}
+
+			static = append(static, segment)
+		}
+
+		prefix := path.Join(static...)
+		if prefix == "." {
+			prefix = ""
+		}
+
+		prefixes = append(prefixes, prefix)
+	}
+
+	// an ancestor is sorted before its descendants.
+	sort.Strings(prefixes)
+
+	var roots []string
+	for _, prefix := range prefixes {
+		inside := false
+		for _, root := range roots {
+			if root == "" || prefix == root || strings.HasPrefix(prefix, root+"/") {
+				inside = true
+				break
+			}
+		}
+
+		if !inside {
+			roots = append(roots, prefix)
+		}
+	}
+
+	return roots
+}
+
+/*
+walkSources calls the given function for each file matching the given glob patterns, with its path relative
+to the configuration file and with slashes. If the function returns an error, the walk stops with this error.
+
+Only the static prefixes of the patterns are walked (e.g. src/app for src/app/*.go), and the cache directory and
+the .git directories are skipped. The missing directories and the files deleted during the walk are ignored.
+*/
+func (r *OrbitRunner) walkSources(patterns []string, fn func(rel string, info os.FileInfo) error) error {
+	root := r.context.BaseDir()
+
+	for _, prefix := range sourcesRoots(patterns) {
+		start := filepath.Join(root, filepath.FromSlash(prefix))
+
+		err := filepath.Walk(start, func(path string, info os.FileInfo, err error) error {
+			if err != nil {
+				if os.IsNotExist(err) {
+					return nil
+				}
+
+				return err
+			}
+
+			if info.IsDir() {
+				if name := info.Name(); path != start && (name == cacheDirName || name == ".git") {
+					return filepath.SkipDir
+				}
+
+				return nil
+			}
+
+			rel, err := filepath.Rel(root, path)
+			if err != nil {
+				return err
+			}
+
+			if rel = filepath.ToSlash(rel); matchSources(patterns, []string{rel}) {
+				return fn(rel, info)
+			}
+
+			return nil
+		})
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// matchSources returns true if at least one of the given files matches one of the given glob patterns.
+func matchSources(sources []string, files []string) bool {
+	for _, source := range sources {
+		for _, file := range files {
+			if matchGlob(strings.Split(path.Clean(filepath.ToSlash(source)), "/"), strings.Split(file, "/")) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+/*
+matchGlob returns true if the given path segments match the given pattern segments.
+
+Each segment is matched with path.Match, with the exception of ** which matches
+zero or more segments.
+*/
+func matchGlob(pattern []string, segments []string) bool {
+	if len(pattern) == 0 {
+		return len(segments) == 0
+	}
+
+	if pattern[0] == "**" {
+		for index := 0; index <= len(segments); index++ {
+			if matchGlob(pattern[1:], segments[index:]) {
+				return true
+			}
+		}
+
+		return false
+	}
+
+	if len(segments) == 0 {
+		return false
+	}
+
+	if ok, _ := path.Match(pattern[0], segments[0]); !ok {
+		return false
+	}
+
+	return matchGlob(pattern[1:], segments[1:])
+}
@@ -0,0 +1,49 @@
+package runner
+
+import (
+	"reflect"
+	"testing"
+)
+
+// Tests if the changed files are matched against the sources of a task.
+func TestMatchSources(t *testing.T) {
+	files := []string{"README.md", "app/runner/runner.go"}
+
+	// case 1: uses a pattern matching a file at the root.
+	if !matchSources([]string{"*.md"}, files) {
+		t.Error("*.md should have matched README.md!")
+	}
+
+	// case 2: uses a pattern matching any directory.
+	if !matchSources([]string{"app/**/*.go"}, files) {
+		t.Error("app/**/*.go should have matched app/runner/runner.go!")
+	}
+
+	// case 3: uses a pattern matching zero directory.
+	if !matchSources([]string{"**/README.md"}, files) {
+		t.Error("**/README.md should have matched README.md!")
+	}
+
+	// case 4: uses patterns matching no file.
+	if matchSources([]string{"*.go", "app/*.go", "vendor/**"}, files) {
+		t.Error("Patterns should not have matched any file!")
+	}
+}
+
+// Tests if only the static prefixes of the patterns are walked.
+func TestSourcesRoots(t *testing.T) {
+	// case 1: uses patterns with static prefixes, one of them inside another.
+	if roots := sourcesRoots([]string{"app/runner/*.go", "./app/**/*.yml", "_tests/orbit.yml"}); !reflect.DeepEqual(roots, []string{"_tests/orbit.yml", "app"}) {
+		t.Errorf("Roots should have been the static prefixes, got %v!", roots)
+	}
+
+	// case 2: uses a pattern matching any directory.
+	if roots := sourcesRoots([]string{"src/*.go", "**/*.md"}); !reflect.DeepEqual(roots, []string{""}) {
+		t.Errorf("Root should have been the directory of the configuration file, got %v!", roots)
+	}
+
+	// case 3: uses patterns outside the directory of the configuration file.
+	if roots := sourcesRoots([]string{"../*.go", "/etc/*.conf"}); len(roots) != 0 {
+		t.Errorf("Patterns outside the directory of the configuration file should not have roots, got %v!", roots)
+	}
+}
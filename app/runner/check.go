@@ -0,0 +1,212 @@
+package runner
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	OrbitError "github.com/gulien/orbit/app/error"
+)
+
+/*
+Check validates the configuration file without executing any command.
+
+As the configuration file has already been executed by the generator and its additional templates
+parsed when instantiating the OrbitRunner, it verifies the tasks, once the definitions for others
+platforms are discarded. Each task has a unique name, runs commands or has dependencies, and its os
+are known. Its retry policy and the names of its arguments are valid. Its dependencies and the tasks
+it calls with "run" exist, and its conditions are valid.
+Its executor is valid and available and, if it's run locally, its custom shell is available.
+Its env files, input file and working directory exist, and its directories to create are not files.
+
+It also verifies that a webhook is configured if a task sends notifications, and that the tasks
+of the groups and the default tasks exist.
+
+Returns all the problems found.
+*/
+func (r *OrbitRunner) Check() []error {
+	var problems []error
+	r.check(func(task *orbitTask, err error) {
+		problems = append(problems, err)
+	})
+
+	return problems
+}
+
+// check reports each problem of the configuration file to the given function, with the task it concerns or nil.
+func (r *OrbitRunner) check(report func(*orbitTask, error)) {
+	names := make(map[string]bool)
+
+	for _, task := range r.config.Tasks {
+		if names[task.Use] {
+			report(task, OrbitError.NewOrbitErrorf("task %s is defined more than once", task.Use))
+		}
+
+		names[task.Use] = true
+
+		if len(task.Run) == 0 && task.RunFile == "" && len(task.Deps) == 0 {
+			report(task, OrbitError.NewOrbitErrorf("task %s has no commands to run nor dependencies", task.Use))
+		}
+
+		for _, os := range task.OS {
+			if !knownPlatforms[os] {
+				report(task, OrbitError.NewOrbitErrorf("task %s has an unknown os %s", task.Use, os))
+			}
+		}
+
+		if _, err := r.getExecutor(task); err != nil {
+			report(task, err)
+		} else if !isLocal(task) {
+			if _, err := exec.LookPath(task.Executor.Type); err != nil {
+				report(task, OrbitError.NewOrbitErrorf("executor %s from task %s is not available", task.Executor.Type, task.Use))
+			}
+		}
+
+		// the shell of a task run inside a container or on a remote host is not available locally.
+		if task.Shell != "" && isLocal(task) {
+			shell := strings.Fields(task.Shell)[0]
+			if _, err := exec.LookPath(shell); err != nil {
+				report(task, OrbitError.NewOrbitErrorf("shell %s from task %s is not available", shell, task.Use))
+			}
+		}
+
+		for _, err := range r.checkPaths(task) {
+			report(task, err)
+		}
+
+		if task.Retry != nil {
+			if task.Retry.Attempts < 1 {
+				report(task, OrbitError.NewOrbitErrorf("task %s has a retry policy with %d attempts, expected at least 1", task.Use, task.Retry.Attempts))
+			}
+
+			switch task.Retry.Backoff {
+			case "", constantBackoff, exponentialBackoff:
+			default:
+				report(task, OrbitError.NewOrbitErrorf("task %s has an unknown retry backoff %s, expected %s or %s", task.Use, task.Retry.Backoff, constantBackoff, exponentialBackoff))
+			}
+		}
+
+		for _, name := range task.Args {
+			if !argNameRegexp.MatchString(name) {
+				report(task, OrbitError.NewOrbitErrorf("task %s has an invalid argument name %s", task.Use, name))
+			}
+		}
+
+		if task.When != nil {
+			if _, err := evaluateWhen(*task.When); err != nil {
+				report(task, OrbitError.NewOrbitErrorf("task %s has an invalid condition. Details:\n%s", task.Use, err))
+			}
+		}
+
+		for _, name := range task.Deps {
+			if r.getTask(name) == nil {
+				report(task, OrbitError.NewOrbitErrorf("task %s depends on task %s which does not exist", task.Use, name))
+			}
+		}
+
+		for _, stack := range task.stacks() {
+			for _, entry := range stack {
+				if entry.When != nil {
+					if _, err := evaluateWhen(*entry.When); err != nil {
+						report(task, OrbitError.NewOrbitErrorf("task %s has command %s with an invalid condition. Details:\n%s", task.Use, entry, err))
+					}
+				}
+
+				// the sub-commands of a parallel group are executed as shell commands.
+				for _, cmd := range entry.Parallel {
+					if _, ok := r.interpretNotification(cmd); ok || r.interpret(cmd) != nil {
+						report(task, OrbitError.NewOrbitErrorf("task %s has parallel command %s which calls others tasks or sends a notification", task.Use, cmd))
+					}
+				}
+
+				cmd := entry.Command
+
+				if _, ok := r.interpretNotification(cmd); ok && (r.config.Notify == nil || r.config.Notify.URL == "") {
+					report(task, OrbitError.NewOrbitErrorf("task %s sends a notification but no notify url is configured", task.Use))
+				}
+
+				call := r.interpret(cmd)
+				if call == nil {
+					continue
+				}
+
+				if call.when != "" {
+					if _, err := evaluateWhen(call.when); err != nil {
+						report(task, OrbitError.NewOrbitErrorf("task %s calls tasks %s with an invalid condition. Details:\n%s", task.Use, call.tasks, err))
+					}
+				}
+
+				for _, name := range call.tasks {
+					if r.getTask(name) == nil {
+						report(task, OrbitError.NewOrbitErrorf("task %s calls task %s which does not exist", task.Use, name))
+					}
+				}
+			}
+		}
+	}
+
+	if r.config.Default != nil {
+		for _, branch := range r.config.Default.Branches {
+			if branch.When != nil {
+				if _, err := evaluateWhen(*branch.When); err != nil {
+					report(nil, OrbitError.NewOrbitErrorf("default task %s has an invalid condition. Details:\n%s", branch.Task, err))
+				}
+			}
+
+			if r.getTask(branch.Task) == nil {
+				report(nil, OrbitError.NewOrbitErrorf("default task %s does not exist", branch.Task))
+			}
+		}
+	}
+
+	for name, group := range r.config.Groups {
+		for _, task := range group {
+			if r.getTask(task) == nil {
+				report(nil, OrbitError.NewOrbitErrorf("group %s references task %s which does not exist", name, task))
+			}
+		}
+	}
+}
+
+/*
+checkPaths returns the problems with the paths of the given task, relative to the configuration file:
+the env files and the input file which do not exist, the working directory which does not exist
+and is not created by mkdir, and the directories to create which are existing files.
+
+The input file and the working directory overridden by the runner are not checked.
+*/
+func (r *OrbitRunner) checkPaths(task *orbitTask) []error {
+	var problems []error
+
+	for _, path := range task.EnvFiles {
+		if info, err := os.Stat(r.resolvePath(path)); err != nil || info.IsDir() {
+			problems = append(problems, OrbitError.NewOrbitErrorf("task %s has an env file %s which does not exist", task.Use, path))
+		}
+	}
+
+	if task.Stdin != "" && r.StdinFile == "" {
+		if info, err := os.Stat(r.resolvePath(task.Stdin)); err != nil || info.IsDir() {
+			problems = append(problems, OrbitError.NewOrbitErrorf("task %s has an input file %s which does not exist", task.Use, task.Stdin))
+		}
+	}
+
+	created := false
+	for _, path := range task.Mkdir {
+		if info, err := os.Stat(r.resolvePath(path)); err == nil && !info.IsDir() {
+			problems = append(problems, OrbitError.NewOrbitErrorf("task %s has a directory to create %s which is a file", task.Use, path))
+		}
+
+		if rel, err := filepath.Rel(r.resolvePath(path), r.resolvePath(task.Dir)); err == nil && !strings.HasPrefix(rel, "..") {
+			created = true
+		}
+	}
+
+	if task.Dir != "" && r.Dir == "" && !created {
+		if info, err := os.Stat(r.resolvePath(task.Dir)); err != nil || !info.IsDir() {
+			problems = append(problems, OrbitError.NewOrbitErrorf("task %s has a working directory %s which does not exist", task.Use, task.Dir))
+		}
+	}
+
+	return problems
+}
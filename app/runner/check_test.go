@@ -0,0 +1,53 @@
+package runner
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/gulien/orbit/app/context"
+)
+
+// Tests if checking a configuration file reports its problems.
+func TestCheck(t *testing.T) {
+	// case 1: uses a configuration file with a non existing shell, a non existing task and an invalid condition.
+	templateFilePath, _ := filepath.Abs("../../_tests/orbit.yml")
+	ctx, _ := context.NewOrbitContext(templateFilePath, "", "")
+	r, _ := NewOrbitRunner(ctx)
+	if problems := r.Check(); len(problems) != 3 {
+		t.Errorf("Check should have reported 3 problems, got %d!", len(problems))
+	}
+
+	// case 2: uses a correct configuration file.
+	templateFilePath, _ = filepath.Abs("../../_tests/orbit-check.yml")
+	ctx, _ = context.NewOrbitContext(templateFilePath, "", "")
+	r, _ = NewOrbitRunner(ctx)
+	if problems := r.Check(); len(problems) != 0 {
+		t.Errorf("Check should not have reported problems, got %v!", problems)
+	}
+
+	// case 3: uses tasks with existing and missing paths.
+	templateFilePath, _ = filepath.Abs("../../_tests/orbit-check-paths.yml")
+	ctx, _ = context.NewOrbitContext(templateFilePath, "", "")
+	r, _ = NewOrbitRunner(ctx)
+
+	problems := r.Check()
+	if len(problems) != 4 {
+		t.Errorf("Check should have reported 4 problems, got %v!", problems)
+	}
+
+	for _, problem := range problems {
+		if !strings.Contains(problem.Error(), "task sputnik ") {
+			t.Errorf("Problem should have concerned task sputnik, got %s!", problem)
+		}
+	}
+	// case 4: uses a parallel group of commands calling a task, which is not built from a configuration file.
+	r = &OrbitRunner{config: &orbitRunnerConfig{Tasks: []*orbitTask{
+		{Use: "explorer", Run: orbitCommands{{Parallel: []string{"echo 1", "run@explorer"}}}},
+	}}, context: ctx}
+
+	problems = r.Check()
+	if len(problems) != 1 || !strings.Contains(problems[0].Error(), "parallel command run@explorer") {
+		t.Errorf("Check should have reported the parallel call, got %v!", problems)
+	}
+}
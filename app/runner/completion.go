@@ -0,0 +1,70 @@
+package runner
+
+import (
+	"github.com/gulien/orbit/app/context"
+	"github.com/gulien/orbit/app/generator"
+	"github.com/gulien/orbit/app/helpers"
+
+	"gopkg.in/yaml.v2"
+)
+
+// CompletionTask is a task offered by the shell completion.
+type CompletionTask struct {
+	// Use is the name of the task.
+	Use string `yaml:"use"`
+
+	// Short is the short description of the task.
+	Short string `yaml:"short"`
+
+	// Private is true if the task is private.
+	Private bool `yaml:"private"`
+}
+
+/*
+CompletionTasks returns the tasks of the given configuration file which are not private, in their order of declaration.
+
+As the shell completion should never fail, the configuration file is read as much as possible: if it cannot be
+fully loaded (e.g. a data file is missing), it's executed again without payload and with the missing keys empty,
+and only its tasks are parsed. Returns nil if the configuration file cannot be read at all.
+*/
+func CompletionTasks(templateFilePath string) []CompletionTask {
+	if !helpers.FileExists(templateFilePath) {
+		return nil
+	}
+
+	ctx, err := context.NewOrbitContext(templateFilePath, "", "")
+	if err == nil {
+		if r, err := NewOrbitRunner(ctx); err == nil {
+			tasks, _ := r.listTasks()
+
+			completions := make([]CompletionTask, len(tasks))
+			for index, task := range tasks {
+				completions[index] = CompletionTask{Use: task.Use, Short: task.Short}
+			}
+
+			return completions
+		}
+	}
+
+	data, err := generator.NewOrbitGenerator(&context.OrbitContext{TemplateFilePath: templateFilePath}).ExecuteLenient()
+	if err != nil {
+		return nil
+	}
+
+	var config struct {
+		Tasks []CompletionTask `yaml:"tasks"`
+	}
+
+	if yaml.Unmarshal(data.Bytes(), &config) != nil {
+		return nil
+	}
+
+	var completions []CompletionTask
+	for _, task := range config.Tasks {
+		if !task.Private && task.Use != "" {
+			completions = append(completions, CompletionTask{Use: task.Use, Short: task.Short})
+		}
+	}
+
+	return completions
+}
@@ -0,0 +1,27 @@
+package runner
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+// Tests if the tasks offered by the shell completion are the ones which are not private.
+func TestCompletionTasks(t *testing.T) {
+	// case 1: uses a non existing configuration file.
+	if tasks := CompletionTasks("non_existing_file"); tasks != nil {
+		t.Error("Non existing configuration file should not have offered tasks!")
+	}
+
+	// case 2: uses a configuration file which can be fully loaded.
+	templateFilePath, _ := filepath.Abs("../../_tests/orbit-groups.yml")
+	if tasks := CompletionTasks(templateFilePath); len(tasks) == 0 {
+		t.Error("Configuration file should have offered tasks!")
+	}
+
+	// case 3: uses a configuration file with missing data.
+	templateFilePath, _ = filepath.Abs("../../_tests/orbit-completion.yml")
+	tasks := CompletionTasks(templateFilePath)
+	if len(tasks) != 1 || tasks[0].Use != "explorer" {
+		t.Errorf("Only the tasks which are not private should have been offered, got %v!", tasks)
+	}
+}
@@ -0,0 +1,90 @@
+package runner
+
+import (
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+
+	OrbitError "github.com/gulien/orbit/app/error"
+	"github.com/gulien/orbit/app/logger"
+)
+
+// compileDestructivePatterns compiles the patterns of the destructive commands from the configuration file.
+// Returns the valid patterns and a problem for each invalid one.
+func compileDestructivePatterns(patterns []string) ([]*regexp.Regexp, []error) {
+	var compiled []*regexp.Regexp
+	var problems []error
+	for _, pattern := range patterns {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			problems = append(problems, OrbitError.NewOrbitErrorf("destructive pattern %s is not a valid regular expression. Details:\n%s", pattern, err))
+			continue
+		}
+
+		compiled = append(compiled, re)
+	}
+
+	return compiled, problems
+}
+
+/*
+confirm asks the user to confirm the execution of the given command from the given task
+if it matches one of the destructive patterns.
+
+Returns an error if the user has not confirmed. If Yes is true, the user is not asked.
+*/
+func (r *OrbitRunner) confirm(cmd string, task *orbitTask) error {
+	if r.Yes {
+		return nil
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	for _, re := range r.destructivePatterns {
+		if !re.MatchString(cmd) {
+			continue
+		}
+
+		fmt.Fprintf(r.Stderr, "command %s from task %s matches the destructive pattern %s, continue? [y/N] ", cmd, task.Use, re)
+
+		answer, err := readLine(r.Stdin)
+		if err != nil && err != io.EOF {
+			return OrbitError.NewOrbitErrorf("unable to read the confirmation of command %s from task %s. Details:\n%s", cmd, task.Use, err)
+		}
+
+		answer = strings.ToLower(strings.TrimSpace(answer))
+		if answer != "y" && answer != "yes" {
+			return OrbitError.NewOrbitErrorf("command %s from task %s has not been confirmed", cmd, task.Use)
+		}
+
+		logger.Infof("command %s from task %s has been confirmed", cmd, task.Use)
+
+		return nil
+	}
+
+	return nil
+}
+
+// readLine reads a line from the given reader, byte by byte, in order to
+// leave the remaining input to the commands.
+func readLine(reader io.Reader) (string, error) {
+	var line []byte
+	b := make([]byte, 1)
+
+	for {
+		n, err := reader.Read(b)
+		if n > 0 {
+			if b[0] == '\n' {
+				return string(line), nil
+			}
+
+			line = append(line, b[0])
+		}
+
+		if err != nil {
+			return string(line), err
+		}
+	}
+}
@@ -0,0 +1,48 @@
+package runner
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/gulien/orbit/app/context"
+)
+
+// Tests if the destructive commands require a confirmation.
+func TestConfirm(t *testing.T) {
+	// case 1: uses a broken destructive pattern.
+	brokenTemplateFilePath, _ := filepath.Abs("../../_tests/broken-orbit-confirm.yml")
+	ctx, _ := context.NewOrbitContext(brokenTemplateFilePath, "", "")
+	if _, err := NewOrbitRunner(ctx); err == nil {
+		t.Error("OrbitRunner should not have been instantiated with a broken destructive pattern!")
+	}
+
+	templateFilePath, _ := filepath.Abs("../../_tests/orbit-confirm.yml")
+	ctx, _ = context.NewOrbitContext(templateFilePath, "", "")
+	r, _ := NewOrbitRunner(ctx)
+
+	// case 2: uses a command which is not destructive.
+	r.Stdin = strings.NewReader("")
+	if err := r.Run("explorer"); err != nil {
+		t.Error("Command which is not destructive should have been run!")
+	}
+
+	// case 3: uses a destructive command without confirmation.
+	r.Stdin = strings.NewReader("n\n")
+	if err := r.Run("columbia"); err == nil {
+		t.Error("Destructive command should not have been run without confirmation!")
+	}
+
+	// case 4: uses a destructive command with confirmation.
+	r.Stdin = strings.NewReader("y\n")
+	if err := r.Run("columbia"); err != nil {
+		t.Error("Destructive command should have been run with confirmation!")
+	}
+
+	// case 5: uses a destructive command with the yes option.
+	r.Stdin = strings.NewReader("")
+	r.Yes = true
+	if err := r.Run("columbia"); err != nil {
+		t.Error("Destructive command should have been run with the yes option!")
+	}
+}
@@ -0,0 +1,66 @@
+package runner
+
+import (
+	OrbitError "github.com/gulien/orbit/app/error"
+	"github.com/gulien/orbit/app/logger"
+)
+
+type (
+	// orbitDefault represents the default task as defined in the configuration file:
+	// either the name of a task or a list of conditional branches.
+	orbitDefault struct {
+		// Branches contains the conditional branches, in their order of declaration.
+		Branches []*orbitDefaultBranch
+	}
+
+	// orbitDefaultBranch represents a conditional branch of the default task.
+	orbitDefaultBranch struct {
+		// When is the condition which has to be true to select the task.
+		// If nil, the branch is a fallback which is always selected.
+		When *string `yaml:"when,omitempty"`
+
+		// Task is the name of the selected task.
+		Task string `yaml:"task"`
+	}
+)
+
+// UnmarshalYAML populates the default task from either a task name or a list of conditional branches.
+func (d *orbitDefault) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var name string
+	if err := unmarshal(&name); err == nil {
+		d.Branches = []*orbitDefaultBranch{{Task: name}}
+		return nil
+	}
+
+	return unmarshal(&d.Branches)
+}
+
+/*
+DefaultTask returns the name of the default task and true if a default task is defined in the configuration file.
+
+The branches are evaluated in their order of declaration: the first one whose condition is true,
+or which has no condition, is selected. If no branch is selected, returns an error.
+*/
+func (r *OrbitRunner) DefaultTask() (string, bool, error) {
+	if r.config.Default == nil || len(r.config.Default.Branches) == 0 {
+		return "", false, nil
+	}
+
+	for _, branch := range r.config.Default.Branches {
+		if branch.When == nil {
+			return branch.Task, true, nil
+		}
+
+		ok, err := evaluateWhen(*branch.When)
+		if err != nil {
+			return "", true, OrbitError.NewOrbitErrorf("unable to select the default task %s. Details:\n%s", branch.Task, err)
+		}
+
+		if ok {
+			logger.Infof("default task %s has been selected as condition %s is true", branch.Task, *branch.When)
+			return branch.Task, true, nil
+		}
+	}
+
+	return "", true, OrbitError.NewOrbitErrorf("no default task matches the current environment in configuration file %s", r.context.TemplateFilePath)
+}
@@ -0,0 +1,49 @@
+package runner
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/gulien/orbit/app/context"
+)
+
+// Tests if the default task is selected according to its conditions.
+func TestDefaultTask(t *testing.T) {
+	templateFilePath, _ := filepath.Abs("../../_tests/orbit-default.yml")
+	nameTemplateFilePath, _ := filepath.Abs("../../_tests/orbit-default-name.yml")
+
+	// case 1: uses a task name.
+	ctx, _ := context.NewOrbitContext(nameTemplateFilePath, "", "")
+	r, _ := NewOrbitRunner(ctx)
+	if name, ok, err := r.DefaultTask(); err != nil || !ok || name != "dev" {
+		t.Errorf("Default task should have been dev, got %s!", name)
+	}
+
+	// case 2: uses a matching condition.
+	ctx, _ = context.NewOrbitContext(templateFilePath, "env,ci", "")
+	r, _ = NewOrbitRunner(ctx)
+	if name, ok, err := r.DefaultTask(); err != nil || !ok || name != "ci" {
+		t.Errorf("Default task should have been ci, got %s!", name)
+	}
+
+	// case 3: uses the fallback.
+	ctx, _ = context.NewOrbitContext(templateFilePath, "env,local", "")
+	r, _ = NewOrbitRunner(ctx)
+	if name, ok, err := r.DefaultTask(); err != nil || !ok || name != "dev" {
+		t.Errorf("Default task should have been the fallback dev, got %s!", name)
+	}
+
+	// case 4: uses no matching condition without fallback.
+	r.config.Default.Branches = r.config.Default.Branches[:2]
+	if _, ok, err := r.DefaultTask(); err == nil || !ok {
+		t.Error("Default task should not have been selected without fallback!")
+	}
+
+	// case 5: uses no default task.
+	templateFilePath, _ = filepath.Abs("../../_tests/orbit.yml")
+	ctx, _ = context.NewOrbitContext(templateFilePath, "", "")
+	r, _ = NewOrbitRunner(ctx)
+	if _, ok, err := r.DefaultTask(); err != nil || ok {
+		t.Error("Default task should not have been defined!")
+	}
+}
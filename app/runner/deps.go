@@ -0,0 +1,160 @@
+package runner
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	OrbitError "github.com/gulien/orbit/app/error"
+	"github.com/gulien/orbit/app/logger"
+)
+
+// calledTasks returns the names of the dependencies of the given task
+// and of the tasks called by its commands, including its hooks.
+func (r *OrbitRunner) calledTasks(task *orbitTask) []string {
+	names := append([]string{}, task.Deps...)
+	for _, stack := range task.stacks() {
+		for _, entry := range stack {
+			if call := r.interpret(entry.Command); call != nil {
+				names = append(names, call.tasks...)
+			}
+		}
+	}
+
+	return names
+}
+
+/*
+checkDepsCycles verifies that the dependencies of the tasks form a directed acyclic graph,
+so that a dependency loop is reported before running any task.
+
+The dependencies which do not exist are ignored: they are reported by Check or when running the tasks.
+Returns all the cycles, each of them being reported once.
+*/
+func checkDepsCycles(config *orbitRunnerConfig) []error {
+	tasks := make(map[string]*orbitTask)
+	for _, task := range config.Tasks {
+		tasks[task.Use] = task
+	}
+
+	var problems []error
+	visited := make(map[string]bool)
+	for _, task := range config.Tasks {
+		if err := checkTaskDepsCycles(task, tasks, visited, nil); err != nil {
+			problems = append(problems, err)
+		}
+	}
+
+	return problems
+}
+
+// checkTaskDepsCycles verifies that the dependencies of the given task do not depend on it.
+// The chain argument contains the names of the tasks being visited.
+func checkTaskDepsCycles(task *orbitTask, tasks map[string]*orbitTask, visited map[string]bool, chain []string) error {
+	chain = append(chain, task.Use)
+	for _, name := range chain[:len(chain)-1] {
+		if name == task.Use {
+			// the tasks of the cycle are not visited again.
+			for _, name := range chain {
+				visited[name] = true
+			}
+
+			return OrbitError.NewOrbitErrorf("cyclic dependency detected: %s", strings.Join(chain, " -> "))
+		}
+	}
+
+	if visited[task.Use] {
+		return nil
+	}
+
+	for _, name := range task.Deps {
+		if dep, ok := tasks[name]; ok {
+			if err := checkTaskDepsCycles(dep, tasks, visited, chain); err != nil {
+				return err
+			}
+		}
+	}
+
+	visited[task.Use] = true
+
+	return nil
+}
+
+/*
+runDeps runs the dependencies of the given task which have not been run successfully
+since the last call to Run.
+*/
+func (r *OrbitRunner) runDeps(task *orbitTask) error {
+	for _, name := range task.Deps {
+		if r.completed[name] {
+			logger.Infof("skipping dependency %s of task %s as it has already been run", name, task.Use)
+			if err := r.printDryRunSkip(task, "dependency "+name, "it has already been run"); err != nil {
+				return err
+			}
+
+			continue
+		}
+
+		if err := r.runWithArgs(nil, name); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+/*
+PrintPrivateDeps prints to Stdout, for each task which is not private, the tree of the tasks
+it calls, the private ones being marked as such.
+
+The tasks which do not exist and the cyclic calls are reported instead of being followed.
+*/
+func (r *OrbitRunner) PrintPrivateDeps() error {
+	return r.printPrivateDeps(r.Stdout)
+}
+
+// printPrivateDeps prints the trees of the called tasks to the given writer.
+func (r *OrbitRunner) printPrivateDeps(w io.Writer) error {
+	tasks, err := r.listTasks()
+	if err != nil {
+		return err
+	}
+
+	for _, task := range tasks {
+		fmt.Fprintln(w, task.Use)
+		for _, name := range r.calledTasks(task) {
+			r.printDepsTask(w, name, 1, []string{task.Use})
+		}
+	}
+
+	return nil
+}
+
+// printDepsTask prints the given called task, then the tasks it calls.
+// The chain argument contains the names of the tasks calling it.
+func (r *OrbitRunner) printDepsTask(w io.Writer, name string, level int, chain []string) {
+	indent := strings.Repeat("  ", level)
+
+	for _, previous := range chain {
+		if previous == name {
+			fmt.Fprintf(w, "%s%s (cyclic call: %s -> %s)\n", indent, name, strings.Join(chain, " -> "), name)
+			return
+		}
+	}
+
+	task := r.getTask(name)
+	if task == nil {
+		fmt.Fprintf(w, "%s%s (does not exist)\n", indent, name)
+		return
+	}
+
+	if task.Private {
+		fmt.Fprintf(w, "%s%s (private)\n", indent, name)
+	} else {
+		fmt.Fprintf(w, "%s%s\n", indent, name)
+	}
+
+	for _, subtask := range r.calledTasks(task) {
+		r.printDepsTask(w, subtask, level+1, append(chain, name))
+	}
+}
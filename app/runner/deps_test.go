@@ -0,0 +1,78 @@
+package runner
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/gulien/orbit/app/context"
+)
+
+// Tests if the trees of the called tasks are printed with the private tasks, the missing tasks and the cyclic calls.
+func TestPrintPrivateDeps(t *testing.T) {
+	templateFilePath, _ := filepath.Abs("../../_tests/orbit-deps.yml")
+	ctx, _ := context.NewOrbitContext(templateFilePath, "", "")
+	r, _ := NewOrbitRunner(ctx)
+
+	var buf bytes.Buffer
+	if err := r.printPrivateDeps(&buf); err != nil {
+		t.Error("Trees of the called tasks should have been printed!")
+	}
+
+	expected := "explorer\n" +
+		"  sputnik (private)\n" +
+		"    gemini (private)\n" +
+		"      sputnik (cyclic call: explorer -> sputnik -> gemini -> sputnik)\n" +
+		"    apollo (does not exist)\n" +
+		"  vostok\n" +
+		"vostok\n"
+
+	if buf.String() != expected {
+		t.Errorf("Trees of the called tasks should have been %q, got %q!", expected, buf.String())
+	}
+}
+
+// Tests if the dependencies of the tasks are run before them, at most once per call to Run.
+func TestRunDeps(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("skipping test on Windows")
+	}
+
+	templateFilePath, _ := filepath.Abs("../../_tests/orbit-deps-run.yml")
+	ctx, _ := context.NewOrbitContext(templateFilePath, "", "")
+	r, _ := NewOrbitRunner(ctx)
+	os.Remove("deps.log")
+	defer os.Remove("deps.log")
+
+	// case 1: uses a diamond dependency graph.
+	if err := r.Run("package"); err != nil {
+		t.Errorf("Task with dependencies should have been run, got %s!", err)
+	}
+
+	data, _ := ioutil.ReadFile("deps.log")
+	if string(data) != "build\nlint\ntest\npackage\n" {
+		t.Errorf("Shared dependency should have been run exactly once, got %q!", string(data))
+	}
+
+	// case 2: uses a new call to Run.
+	os.Remove("deps.log")
+	if err := r.Run("test"); err != nil {
+		t.Errorf("Task with dependencies should have been run again, got %s!", err)
+	}
+
+	data, _ = ioutil.ReadFile("deps.log")
+	if string(data) != "build\ntest\n" {
+		t.Errorf("Dependencies should have been run again on a new call to Run, got %q!", string(data))
+	}
+
+	// case 3: uses a dependency loop.
+	templateFilePath, _ = filepath.Abs("../../_tests/orbit-deps-cycle.yml")
+	ctx, _ = context.NewOrbitContext(templateFilePath, "", "")
+	if _, err := NewOrbitRunner(ctx); err == nil || !strings.Contains(err.Error(), "cyclic dependency detected: build -> install -> setup -> install") {
+		t.Errorf("Dependency loop should have been reported when reading the configuration file, got %v!", err)
+	}
+}
@@ -0,0 +1,154 @@
+package runner
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/gulien/orbit/app/logger"
+)
+
+// outputsDirName is the directory, inside the cache directory, which contains the outputs of the last runs.
+const outputsDirName = "outputs"
+
+// maxDiffLines is the number of changed lines printed before truncating the diff.
+const maxDiffLines = 50
+
+// maxDiffSize is the product of the numbers of lines of the outputs above which they are not diffed line by line.
+const maxDiffSize = 4000000
+
+const (
+	// ANSI color of the removed lines.
+	removedLineColor = "\x1b[31m"
+
+	// ANSI color of the added lines.
+	addedLineColor = "\x1b[32m"
+
+	// ANSI code resetting the color.
+	resetColor = "\x1b[0m"
+)
+
+// previousOutputFilePath returns the path of the file containing the output of the last run of the given task.
+func (r *OrbitRunner) previousOutputFilePath(task *orbitTask) string {
+	// task names may contain characters which are not allowed in a file name.
+	name := base64.RawURLEncoding.EncodeToString([]byte(task.Use)) + ".log"
+
+	return filepath.Join(r.context.BaseDir(), cacheDirName, outputsDirName, name)
+}
+
+/*
+diffPrevious prints to Stderr the differences between the standard output of the last run
+of the given task and the given output, then stores the given output for the next run.
+
+As the diff is only a regression aid, failing to read or store the outputs is not an error.
+*/
+func (r *OrbitRunner) diffPrevious(task *orbitTask, output []byte) {
+	filePath := r.previousOutputFilePath(task)
+
+	previous, err := ioutil.ReadFile(filePath)
+	if err == nil {
+		printDiff(r.Stderr, task, splitLines(string(previous)), splitLines(string(output)), logger.UseColor())
+	} else if !os.IsNotExist(err) {
+		logger.Warnf("unable to read the previous output of task %s: %s", task.Use, err)
+	}
+
+	if err = os.MkdirAll(filepath.Dir(filePath), 0755); err == nil {
+		err = ioutil.WriteFile(filePath, output, 0644)
+	}
+
+	if err != nil {
+		logger.Warnf("unable to store the output of task %s: %s", task.Use, err)
+	}
+}
+
+// splitLines returns the lines of the given text, without the trailing empty line.
+func splitLines(text string) []string {
+	if text == "" {
+		return nil
+	}
+
+	return strings.Split(strings.TrimSuffix(text, "\n"), "\n")
+}
+
+// printDiff prints the changed lines between the given previous and current lines of the given task.
+func printDiff(w io.Writer, task *orbitTask, previous []string, current []string, color bool) {
+	if len(previous)*len(current) > maxDiffSize {
+		fmt.Fprintf(w, "output of task %s has changed since its last run (too large to be diffed)\n", task.Use)
+		return
+	}
+
+	lines := diffLines(previous, current)
+	if len(lines) == 0 {
+		return
+	}
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "output of task %s has changed since its last run:\n", task.Use)
+
+	for index, line := range lines {
+		if index == maxDiffLines {
+			fmt.Fprintf(&buf, "... %d more changed lines\n", len(lines)-maxDiffLines)
+			break
+		}
+
+		if color && strings.HasPrefix(line, "-") {
+			line = removedLineColor + line + resetColor
+		} else if color {
+			line = addedLineColor + line + resetColor
+		}
+
+		fmt.Fprintln(&buf, line)
+	}
+
+	w.Write(buf.Bytes())
+}
+
+/*
+diffLines returns the removed lines, prefixed by "- ", and the added lines, prefixed by "+ ",
+between the given previous and current lines.
+
+It relies on the longest common subsequence of the lines.
+*/
+func diffLines(previous []string, current []string) []string {
+	// lcs[i][j] is the length of the longest common subsequence of previous[i:] and current[j:].
+	lcs := make([][]int, len(previous)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(current)+1)
+	}
+
+	for i := len(previous) - 1; i >= 0; i-- {
+		for j := len(current) - 1; j >= 0; j-- {
+			if previous[i] == current[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else if lcs[i+1][j] >= lcs[i][j+1] {
+				lcs[i][j] = lcs[i+1][j]
+			} else {
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+
+	var lines []string
+	i, j := 0, 0
+
+	for i < len(previous) || j < len(current) {
+		switch {
+		case i < len(previous) && j < len(current) && previous[i] == current[j]:
+			i++
+			j++
+		case j == len(current) || (i < len(previous) && lcs[i+1][j] >= lcs[i][j+1]):
+			lines = append(lines, "- "+previous[i])
+			i++
+		default:
+			lines = append(lines, "+ "+current[j])
+			j++
+		}
+	}
+
+	return lines
+}
@@ -0,0 +1,78 @@
+package runner
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/gulien/orbit/app/context"
+)
+
+// Tests if the output of a task is stored for the next run.
+func TestDiffPrevious(t *testing.T) {
+	templateFilePath, _ := filepath.Abs("../../_tests/orbit-diff-previous.yml")
+	ctx, _ := context.NewOrbitContext(templateFilePath, "", "")
+	r, _ := NewOrbitRunner(ctx)
+
+	filePath := r.previousOutputFilePath(r.getTask("explorer"))
+	defer os.RemoveAll(filepath.Dir(filepath.Dir(filePath)))
+
+	if err := r.Run("explorer"); err != nil {
+		t.Error("Task with diff_previous should have been run!")
+	}
+
+	if out, _ := ioutil.ReadFile(filePath); string(out) != "I am explorer task\n" {
+		t.Errorf("Output of the task should have been stored, got %q!", out)
+	}
+}
+
+// Tests if the outputs of commands executed in parallel are stored without data race (see go test -race).
+func TestDiffPreviousParallel(t *testing.T) {
+	templateFilePath, _ := filepath.Abs("../../_tests/orbit-diff-previous.yml")
+	ctx, _ := context.NewOrbitContext(templateFilePath, "", "")
+	r, _ := NewOrbitRunner(ctx)
+
+	filePath := r.previousOutputFilePath(r.getTask("sputnik"))
+	defer os.RemoveAll(filepath.Dir(filepath.Dir(filePath)))
+
+	if err := r.Run("sputnik"); err != nil {
+		t.Errorf("Task with diff_previous and parallel commands should have been run, got %s!", err)
+	}
+
+	out, _ := ioutil.ReadFile(filePath)
+	for _, ordinal := range []string{"first", "second", "third"} {
+		if !strings.Contains(string(out), "I am the "+ordinal+" parallel command\n") {
+			t.Errorf("Output of the %s parallel command should have been stored, got %q!", ordinal, out)
+		}
+	}
+}
+
+// Tests if the changed lines between two outputs are found.
+func TestDiffLines(t *testing.T) {
+	// case 1: uses identical outputs.
+	if lines := diffLines([]string{"a", "b"}, []string{"a", "b"}); len(lines) != 0 {
+		t.Errorf("Identical outputs should not have any changed line, got %v!", lines)
+	}
+
+	// case 2: uses changed outputs.
+	lines := diffLines([]string{"a", "b", "c"}, []string{"a", "c", "d"})
+	if !reflect.DeepEqual(lines, []string{"- b", "+ d"}) {
+		t.Errorf("Changed lines should have been found, got %v!", lines)
+	}
+
+	// case 3: uses a truncated diff.
+	var previous, current []string
+	for index := 0; index < maxDiffLines+10; index++ {
+		current = append(current, "line")
+	}
+
+	var buf bytes.Buffer
+	printDiff(&buf, &orbitTask{Use: "explorer"}, previous, current, false)
+	if !strings.Contains(buf.String(), "... 10 more changed lines") {
+		t.Errorf("Diff should have been truncated, got %q!", buf.String())
+	}
+}
@@ -0,0 +1,61 @@
+package runner
+
+import (
+	"bytes"
+	"path/filepath"
+
+	"github.com/gulien/orbit/app/context"
+	"github.com/gulien/orbit/app/generator"
+
+	"gopkg.in/yaml.v2"
+)
+
+/*
+executeConfig executes the given configuration file and returns the resulting bytes.
+
+If the configuration file has a dotenv attribute, its files are loaded into the context and the configuration file
+is executed again, so that their variables are available through {{ .Env }}. As it may reference these variables,
+the dotenv attribute is read from an execution where the missing keys are empty if the first execution fails.
+The variables already in the context (e.g. from the flag --env-file) win over the ones from the dotenv attribute.
+*/
+func executeConfig(ctx *context.OrbitContext) (bytes.Buffer, error) {
+	g := generator.NewOrbitGenerator(ctx)
+	data, err := g.Execute()
+
+	preview := data
+	if err != nil {
+		var lenientErr error
+		if preview, lenientErr = g.ExecuteLenient(); lenientErr != nil {
+			return data, err
+		}
+	}
+
+	var config struct {
+		Dotenv []string `yaml:"dotenv"`
+	}
+
+	if yaml.Unmarshal(preview.Bytes(), &config) != nil || len(config.Dotenv) == 0 {
+		return data, err
+	}
+
+	filesPaths := make([]string, len(config.Dotenv))
+	for index, path := range config.Dotenv {
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(ctx.BaseDir(), path)
+		}
+
+		filesPaths[index] = path
+	}
+
+	overrides := ctx.Env
+	ctx.Env = nil
+	if err := ctx.LoadDotenv(filesPaths...); err != nil {
+		return data, err
+	}
+
+	for key, value := range overrides {
+		ctx.Env[key] = value
+	}
+
+	return g.Execute()
+}
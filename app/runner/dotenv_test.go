@@ -0,0 +1,49 @@
+package runner
+
+import (
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/gulien/orbit/app/context"
+)
+
+// Tests if the variables of the dotenv files are accessible in the configuration file and given to the commands.
+func TestDotenv(t *testing.T) {
+	templateFilePath, _ := filepath.Abs("../../_tests/orbit-dotenv.yml")
+
+	// case 1: uses the dotenv attribute of the configuration file.
+	ctx, _ := context.NewOrbitContext(templateFilePath, "", "")
+	r, err := NewOrbitRunner(ctx)
+	if err != nil {
+		t.Fatalf("Configuration file referencing the variables of its dotenv files should have been read, got %s!", err)
+	}
+
+	if short := r.getTask("explorer").Short; short != "apollo" {
+		t.Errorf("Variables of the dotenv files should have been accessible in the configuration file, got %s!", short)
+	}
+
+	if runtime.GOOS != "windows" {
+		if err := r.Run("explorer"); err != nil {
+			t.Errorf("Variables of the dotenv files should have been given to the commands, got %s!", err)
+		}
+	}
+
+	// case 2: uses a dotenv file loaded in the context, which wins over the dotenv attribute.
+	ctx, _ = context.NewOrbitContext(templateFilePath, "", "")
+	overrideFilePath, _ := filepath.Abs("../../_tests/mission-override.env")
+	if err := ctx.LoadDotenv(overrideFilePath); err != nil {
+		t.Fatalf("Dotenv file should have been loaded, got %s!", err)
+	}
+
+	r, _ = NewOrbitRunner(ctx)
+	if short := r.getTask("explorer").Short; short != "artemis" {
+		t.Errorf("Variables of the dotenv file loaded in the context should have won, got %s!", short)
+	}
+
+	if runtime.GOOS != "windows" {
+		if err := r.Run("explorer"); err != nil {
+			t.Errorf("Overridden variables should have been given to the commands, got %s!", err)
+		}
+	}
+}
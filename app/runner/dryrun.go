@@ -0,0 +1,184 @@
+package runner
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// orbitDryRunStep represents a step of a dry run: either a command, a notification or, if Explain is true,
+// a skipped step. The fields after ContinueOnError are only set if Explain is true.
+type orbitDryRunStep struct {
+	// Task is the name of the task executing the step.
+	Task string `json:"task"`
+
+	// Command contains the arguments of the command as it would be executed.
+	Command []string `json:"command,omitempty"`
+
+	// Notify is the message of the notification.
+	Notify string `json:"notify,omitempty"`
+
+	// Timeout is the maximum duration of the command, empty if it has none.
+	Timeout string `json:"timeout,omitempty"`
+
+	// Retries is the maximum number of additional attempts if the command fails.
+	Retries int `json:"retries,omitempty"`
+
+	// ContinueOnError is true if the next commands are executed when the command fails.
+	ContinueOnError bool `json:"continue_on_error,omitempty"`
+
+	// Text is the command before being given to the shell of the task, or the skipped step.
+	Text string `json:"text,omitempty"`
+
+	// Dir is the working directory of the command, empty for the current directory.
+	Dir string `json:"dir,omitempty"`
+
+	// Env contains the variables given to the command in addition to the environment of Orbit:
+	// the ones from the env files and the env attribute of the task, and the ones injected by Orbit.
+	Env []string `json:"env,omitempty"`
+
+	// RetryDelay is the duration to wait before the second attempt of the command.
+	RetryDelay string `json:"retry_delay,omitempty"`
+
+	// RetryBackoff is how the delay evolves between the attempts.
+	RetryBackoff string `json:"retry_backoff,omitempty"`
+
+	// Skipped is the reason why the step is skipped, empty if it's executed.
+	Skipped string `json:"skipped,omitempty"`
+}
+
+/*
+printDryRun prints the given command from the given running task as it would be executed:
+the arguments of the exec.Cmd instance built like in a real run, quoted if needed, prefixed by the name of the task.
+The command is annotated with the policies of the task which apply to it: its timeout, its retries
+and whether the task continues on error.
+
+If Explain is true, the command is also detailed: its text, its working directory, the variables
+it receives in addition to the environment of Orbit (so that the output does not depend on it)
+and the delays of its retries.
+*/
+func (r *OrbitRunner) printDryRun(cmd string, state *orbitTaskState, environ []string) error {
+	e := r.buildCommand(cmd, state, environ)
+
+	task := state.task
+	step := &orbitDryRunStep{
+		Task:            task.Use,
+		Command:         e.Args,
+		ContinueOnError: isEnabled(task.ContinueOnError),
+	}
+
+	if task.Timeout > 0 {
+		step.Timeout = task.Timeout.String()
+	}
+
+	if task.Retry != nil && task.Retry.Attempts > 1 {
+		step.Retries = task.Retry.Attempts - 1
+	}
+
+	if r.Explain {
+		step.Text = cmd
+		step.Dir = state.dir
+		step.Env = append(append([]string{}, state.env...), r.buildEnv(state)...)
+
+		if step.Retries > 0 {
+			step.RetryDelay = task.Retry.Delay.String()
+			step.RetryBackoff = task.Retry.Backoff
+			if step.RetryBackoff == "" {
+				step.RetryBackoff = constantBackoff
+			}
+		}
+	}
+
+	return r.printDryRunStep(step)
+}
+
+// printDryRunSkip prints, if Explain is true, the given step of the given task (e.g. a command or the task itself
+// if empty) which is skipped for the given reason.
+func (r *OrbitRunner) printDryRunSkip(task *orbitTask, text string, reason string) error {
+	if !r.DryRun || !r.Explain {
+		return nil
+	}
+
+	return r.printDryRunStep(&orbitDryRunStep{Task: task.Use, Text: text, Skipped: reason})
+}
+
+// printDryRunNotification prints the given notification from the given running task instead of sending it.
+func (r *OrbitRunner) printDryRunNotification(message string, state *orbitTaskState) error {
+	return r.printDryRunStep(&orbitDryRunStep{Task: state.task.Use, Notify: message})
+}
+
+// printDryRunStep prints the given step to Stdout, as a JSON object per line with the JSON format.
+func (r *OrbitRunner) printDryRunStep(step *orbitDryRunStep) error {
+	if r.Format == JSONFormat {
+		data, err := json.Marshal(step)
+		if err != nil {
+			return err
+		}
+
+		_, err = fmt.Fprintf(r.Stdout, "%s\n", data)
+
+		return err
+	}
+
+	if step.Skipped != "" {
+		if step.Text == "" {
+			_, err := fmt.Fprintf(r.Stdout, "%s: skipped (%s)\n", step.Task, step.Skipped)
+			return err
+		}
+
+		_, err := fmt.Fprintf(r.Stdout, "%s: skips %s (%s)\n", step.Task, step.Text, step.Skipped)
+
+		return err
+	}
+
+	line := "notifies " + strconv.Quote(step.Notify)
+	if step.Command != nil {
+		quoted := make([]string, len(step.Command))
+		for index, arg := range step.Command {
+			quoted[index] = quoteArg(arg)
+		}
+
+		line = strings.Join(quoted, " ")
+	}
+
+	var policies []string
+	if step.Timeout != "" {
+		policies = append(policies, "timeout "+step.Timeout)
+	}
+
+	if step.Retries > 0 {
+		policies = append(policies, fmt.Sprintf("retries %d", step.Retries))
+	}
+
+	if step.ContinueOnError {
+		policies = append(policies, "continue on error")
+	}
+
+	if len(policies) > 0 {
+		line += " (" + strings.Join(policies, ", ") + ")"
+	}
+
+	if step.Text != "" {
+		line += "\n  command: " + step.Text
+
+		dir := step.Dir
+		if dir == "" {
+			dir = "current directory"
+		}
+
+		line += "\n  dir: " + dir
+
+		for _, variable := range step.Env {
+			line += "\n  env: " + variable
+		}
+
+		if step.Retries > 0 {
+			line += fmt.Sprintf("\n  retry: delay %s, %s backoff", step.RetryDelay, step.RetryBackoff)
+		}
+	}
+
+	_, err := fmt.Fprintf(r.Stdout, "%s: %s\n", step.Task, line)
+
+	return err
+}
@@ -0,0 +1,94 @@
+package runner
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/gulien/orbit/app/context"
+)
+
+// Tests if a dry run prints the commands instead of executing them.
+func TestDryRun(t *testing.T) {
+	templateFilePath, _ := filepath.Abs("../../_tests/orbit-dry-run.yml")
+	ctx, _ := context.NewOrbitContext(templateFilePath, "", "")
+	r, _ := NewOrbitRunner(ctx)
+
+	var buf bytes.Buffer
+	r.DryRun = true
+	r.Stdout = &buf
+
+	// case 1: uses a task calling another task and sending a notification.
+	if err := r.Run("explorer"); err != nil {
+		t.Errorf("Dry run should have succeeded, got %s!", err)
+	}
+
+	expected := "explorer: bash -c \"touch explorer.txt\"\n" +
+		"sputnik: sh -c \"echo sputnik > sputnik.txt\"\n" +
+		"explorer: notifies \"explorer has been run\"\n"
+	if buf.String() != expected {
+		t.Errorf("Dry run should have printed the commands, got %q!", buf.String())
+	}
+
+	for _, path := range []string{"explorer.txt", "sputnik.txt", "../../_tests/dry-run"} {
+		if _, err := os.Stat(path); err == nil {
+			os.RemoveAll(path)
+			t.Errorf("Dry run should not have created %s!", path)
+		}
+	}
+
+	// case 2: uses a non existing task.
+	if err := r.Run("apollo"); err == nil {
+		t.Error("Dry run of a non existing task should have failed!")
+	}
+
+	// case 3: uses tasks calling each other.
+	if err := r.Run("vostok"); err == nil || !strings.Contains(err.Error(), "cyclic task reference detected") {
+		t.Errorf("Dry run of tasks calling each other should have failed, got %v!", err)
+	}
+
+	// case 4: uses a task with a timeout, retries and continue_on_error.
+	buf.Reset()
+	if err := r.Run("gemini"); err != nil || buf.String() != "gemini: sh -c \"echo gemini\" (timeout 30s, retries 2, continue on error)\n" {
+		t.Errorf("Dry run should have annotated the command with its policies, got %q (%v)!", buf.String(), err)
+	}
+
+	// case 5: uses the JSON format.
+	buf.Reset()
+	r.Format = JSONFormat
+	if err := r.Run("gemini"); err != nil || buf.String() != `{"task":"gemini","command":["sh","-c","echo gemini"],"timeout":"30s","retries":2,"continue_on_error":true}`+"\n" {
+		t.Errorf("Dry run should have printed the command as JSON, got %q (%v)!", buf.String(), err)
+	}
+
+	// case 6: uses the explanation of the steps.
+	buf.Reset()
+	r.Format = ""
+	r.Explain = true
+	if err := r.Run("mercury", "skylab"); err != nil {
+		t.Errorf("Dry run should have succeeded, got %s!", err)
+	}
+
+	dir := filepath.Join(filepath.Dir(templateFilePath), "dry-run")
+	expected = "mercury: sh -c \"echo mercury\" (retries 1)\n" +
+		"  command: echo mercury\n" +
+		"  dir: " + dir + "\n" +
+		"  env: MISSION=mercury\n" +
+		"  env: ORBIT_TASK=mercury\n" +
+		"  env: ORBIT_ARGS=\n" +
+		"  retry: delay 1s, constant backoff\n" +
+		"mercury: skips echo never (its condition is false)\n" +
+		"mercury: skips the call to tasks sputnik (its condition is false)\n" +
+		"skylab: skipped (its condition is false)\n"
+	if buf.String() != expected {
+		t.Errorf("Dry run should have explained the steps, got %q!", buf.String())
+	}
+
+	// case 7: uses the explanation of the steps with the JSON format.
+	buf.Reset()
+	r.Format = JSONFormat
+	if err := r.Run("skylab"); err != nil || buf.String() != `{"task":"skylab","skipped":"its condition is false"}`+"\n" {
+		t.Errorf("Dry run should have explained the skipped task as JSON, got %q (%v)!", buf.String(), err)
+	}
+}
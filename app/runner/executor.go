@@ -0,0 +1,248 @@
+package runner
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+
+	OrbitError "github.com/gulien/orbit/app/error"
+)
+
+const (
+	// LocalExecutor is the executor which runs the commands through a local shell.
+	LocalExecutor = "local"
+
+	// DockerExecutor is the executor which runs the commands inside a Docker container.
+	DockerExecutor = "docker"
+
+	// SSHExecutor is the executor which runs the commands on a remote host over SSH.
+	SSHExecutor = "ssh"
+)
+
+// defaultRemoteShell is the shell running the commands inside a container or on a remote host.
+const defaultRemoteShell = "sh -c"
+
+type (
+	// orbitExecutorConfig represents the executor attribute of a task.
+	orbitExecutorConfig struct {
+		// Type is the type of executor, either LocalExecutor (default), DockerExecutor or SSHExecutor.
+		Type string `yaml:"type"`
+
+		// Image is the Docker image of the container.
+		Image string `yaml:"image,omitempty"`
+
+		// Volumes contains the volumes mounted in the container (e.g. ./src:/app/src).
+		// The relative host paths are relative to the configuration file.
+		Volumes []string `yaml:"volumes,omitempty"`
+
+		// Host is the remote host, optionally with the user (e.g. deploy@example.com).
+		Host string `yaml:"host,omitempty"`
+
+		// Port is the SSH port of the remote host. If zero, it's the default port of the SSH client.
+		Port int `yaml:"port,omitempty"`
+
+		// Identity is the path of the private key used to connect to the remote host.
+		Identity string `yaml:"identity,omitempty"`
+
+		// Workdir is the working directory of the commands inside the container or on the remote host.
+		Workdir string `yaml:"workdir,omitempty"`
+
+		// Options contains additional arguments of the docker run or ssh command.
+		Options []string `yaml:"options,omitempty"`
+	}
+
+	// orbitExecutor builds the exec.Cmd instances executing the commands of a task.
+	orbitExecutor interface {
+		// command returns an exec.Cmd instance executing the given command from the given task, which receives
+		// the given arguments as positional parameters if its shell is a POSIX shell.
+		// The given variables are forwarded to the command if it does not inherit the environment of the exec.Cmd instance.
+		command(cmd string, task *orbitTask, args []string, env []string) *exec.Cmd
+	}
+
+	// orbitLocalExecutor is the implementation of orbitExecutor which runs the commands through a local shell.
+	orbitLocalExecutor struct{}
+
+	// orbitDockerExecutor is the implementation of orbitExecutor which runs each command in a new Docker container.
+	orbitDockerExecutor struct {
+		// config is the executor attribute of the task.
+		config *orbitExecutorConfig
+
+		// volumes contains the volumes of the container, with the host paths resolved.
+		volumes []string
+	}
+
+	// orbitSSHExecutor is the implementation of orbitExecutor which runs the commands on a remote host over SSH.
+	orbitSSHExecutor struct {
+		// config is the executor attribute of the task.
+		config *orbitExecutorConfig
+	}
+)
+
+// getExecutor returns the implementation of orbitExecutor according to the executor attribute of the given task.
+func (r *OrbitRunner) getExecutor(task *orbitTask) (orbitExecutor, error) {
+	if task.Executor == nil {
+		return &orbitLocalExecutor{}, nil
+	}
+
+	switch task.Executor.Type {
+	case "", LocalExecutor:
+		return &orbitLocalExecutor{}, nil
+	case DockerExecutor:
+		if task.Executor.Image == "" {
+			return nil, OrbitError.NewOrbitErrorf("task %s has a %s executor without image", task.Use, DockerExecutor)
+		}
+
+		volumes := make([]string, len(task.Executor.Volumes))
+		for index, volume := range task.Executor.Volumes {
+			// a host path which does not start with "." or "/" is the name of a volume.
+			if parts := strings.SplitN(volume, ":", 2); len(parts) == 2 && strings.HasPrefix(parts[0], ".") {
+				volume = r.resolvePath(parts[0]) + ":" + parts[1]
+			}
+
+			volumes[index] = volume
+		}
+
+		return &orbitDockerExecutor{config: task.Executor, volumes: volumes}, nil
+	case SSHExecutor:
+		if task.Executor.Host == "" {
+			return nil, OrbitError.NewOrbitErrorf("task %s has a %s executor without host", task.Use, SSHExecutor)
+		}
+
+		return &orbitSSHExecutor{config: task.Executor}, nil
+	default:
+		return nil, OrbitError.NewOrbitErrorf("task %s has an unknown executor %s, expected %s, %s or %s", task.Use, task.Executor.Type, LocalExecutor, DockerExecutor, SSHExecutor)
+	}
+}
+
+// isLocal returns true if the commands of the given task are run through a local shell.
+func isLocal(task *orbitTask) bool {
+	return task.Executor == nil || task.Executor.Type == "" || task.Executor.Type == LocalExecutor
+}
+
+// command from orbitLocalExecutor calls the given command through the shell of the task or, if empty, the shell of the user.
+// If it's a POSIX shell, the command receives the given arguments as positional parameters.
+// As the command inherits the environment of the exec.Cmd instance, the given variables are ignored.
+func (executor *orbitLocalExecutor) command(cmd string, task *orbitTask, args []string, env []string) *exec.Cmd {
+	if task.Shell != "" {
+		// the user has specified a custom binary to use.
+		shellAndParams := strings.Fields(task.Shell)
+		shell := shellAndParams[0]
+		parameters := append(shellAndParams[1:], cmd)
+		if posixShells[filepath.Base(shell)] {
+			parameters = append(parameters, positionalArgs(task, args)...)
+		}
+
+		return exec.Command(shell, parameters...)
+	}
+
+	// if no custom binary specified, detects the current shell of the user.
+	if runtime.GOOS == "windows" {
+		return exec.Command(os.Getenv(defaultWindowsShellEnvVariable), "/c", cmd)
+	}
+
+	shell := os.Getenv(defaultPosixShellEnvVariable)
+	parameters := []string{"-c", cmd}
+	if posixShells[filepath.Base(shell)] {
+		parameters = append(parameters, positionalArgs(task, args)...)
+	}
+
+	return exec.Command(shell, parameters...)
+}
+
+/*
+command from orbitDockerExecutor calls the given command through the shell of the task (by default "sh -c")
+inside a new container, removed once the command has ended. The container receives the standard input, the given
+arguments as positional parameters and the given variables, whose values are read by docker from the environment of the exec.Cmd instance.
+*/
+func (executor *orbitDockerExecutor) command(cmd string, task *orbitTask, args []string, env []string) *exec.Cmd {
+	dockerArgs := []string{"run", "--rm", "-i"}
+	for _, volume := range executor.volumes {
+		dockerArgs = append(dockerArgs, "-v", volume)
+	}
+
+	if executor.config.Workdir != "" {
+		dockerArgs = append(dockerArgs, "-w", executor.config.Workdir)
+	}
+
+	for _, name := range variablesNames(env) {
+		dockerArgs = append(dockerArgs, "-e", name)
+	}
+
+	dockerArgs = append(dockerArgs, executor.config.Options...)
+	dockerArgs = append(dockerArgs, executor.config.Image)
+	dockerArgs = append(dockerArgs, remoteShell(task)...)
+	dockerArgs = append(dockerArgs, cmd)
+	dockerArgs = append(dockerArgs, positionalArgs(task, args)...)
+
+	return exec.Command("docker", dockerArgs...)
+}
+
+/*
+command from orbitSSHExecutor calls the given command through the shell of the task (by default "sh -c") on the remote host,
+after exporting the given variables and moving to the working directory, with the given arguments as positional parameters.
+The remote host must provide a POSIX shell.
+*/
+func (executor *orbitSSHExecutor) command(cmd string, task *orbitTask, args []string, env []string) *exec.Cmd {
+	var sshArgs []string
+	if executor.config.Port != 0 {
+		sshArgs = append(sshArgs, "-p", strconv.Itoa(executor.config.Port))
+	}
+
+	if executor.config.Identity != "" {
+		sshArgs = append(sshArgs, "-i", executor.config.Identity)
+	}
+
+	sshArgs = append(sshArgs, executor.config.Options...)
+	sshArgs = append(sshArgs, executor.config.Host)
+
+	// as the SSH client joins its arguments into the command of the remote shell, each part is quoted.
+	var remote []string
+	for _, variable := range env {
+		if parts := strings.SplitN(variable, "=", 2); len(parts) == 2 {
+			remote = append(remote, "export "+parts[0]+"="+quotePosix(parts[1])+";")
+		}
+	}
+
+	if executor.config.Workdir != "" {
+		remote = append(remote, "cd", quotePosix(executor.config.Workdir), "&&")
+	}
+
+	parts := append(remoteShell(task), cmd)
+	for _, part := range append(parts, positionalArgs(task, args)...) {
+		remote = append(remote, quotePosix(part))
+	}
+
+	return exec.Command("ssh", append(sshArgs, strings.Join(remote, " "))...)
+}
+
+// remoteShell returns the shell and its parameters running the commands of the given task inside a container or on a remote host.
+func remoteShell(task *orbitTask) []string {
+	if task.Shell != "" {
+		return strings.Fields(task.Shell)
+	}
+
+	return strings.Fields(defaultRemoteShell)
+}
+
+// variablesNames returns the names of the given variables, without duplicates.
+func variablesNames(env []string) []string {
+	var names []string
+	seen := make(map[string]bool)
+	for _, variable := range env {
+		name := strings.SplitN(variable, "=", 2)[0]
+		if !seen[name] {
+			names = append(names, name)
+			seen[name] = true
+		}
+	}
+
+	return names
+}
+
+// quotePosix quotes the given value for a POSIX shell.
+func quotePosix(value string) string {
+	return "'" + strings.Replace(value, "'", `'\''`, -1) + "'"
+}
@@ -0,0 +1,75 @@
+package runner
+
+import (
+	"bytes"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/gulien/orbit/app/context"
+)
+
+// Tests if the commands of the tasks are built by their executors.
+func TestExecutor(t *testing.T) {
+	templateFilePath, _ := filepath.Abs("../../_tests/orbit-executor.yml")
+	ctx, _ := context.NewOrbitContext(templateFilePath, "", "")
+	r, _ := NewOrbitRunner(ctx)
+
+	var buf bytes.Buffer
+	r.Stdout = &buf
+	r.DryRun = true
+
+	// case 1: uses a Docker executor with its volumes and variables.
+	volume := filepath.Join(filepath.Dir(templateFilePath), "src") + ":/app/src"
+	if err := r.Run("node"); err != nil {
+		t.Fatalf("Task with a Docker executor should have been run, got %s!", err)
+	}
+
+	expected := "node: docker run --rm -i -v " + quoteArg(volume) + " -v cache:/root/.npm -w /app -e CI -e ORBIT_TASK -e ORBIT_ARGS node:20 sh -c \"npm test\"\n"
+	if buf.String() != expected {
+		t.Errorf("Command should have been run in a container, got %q!", buf.String())
+	}
+
+	// case 2: uses a task inheriting the executor with a custom shell.
+	buf.Reset()
+	if err := r.Run("lint"); err != nil || !strings.HasSuffix(buf.String(), " node:20 bash -c \"npm run lint\"\n") {
+		t.Errorf("Command should have been run in a container with the shell of the task, got %v and %q!", err, buf.String())
+	}
+
+	// case 3: uses an SSH executor.
+	buf.Reset()
+	if err := r.Run("deploy"); err != nil {
+		t.Fatalf("Task with an SSH executor should have been run, got %s!", err)
+	}
+
+	remote := `export RELEASE='it'\''s 1.0'; export ORBIT_TASK='deploy'; export ORBIT_ARGS=''; cd '/srv/app' && 'sh' '-c' './deploy.sh'`
+	if expected := "deploy: ssh -p 2222 -o BatchMode=yes deploy@example.com " + quoteArg(remote) + "\n"; buf.String() != expected {
+		t.Errorf("Command should have been run on the remote host, got %q instead of %q!", buf.String(), expected)
+	}
+
+	// case 4: uses a Docker executor with arguments.
+	buf.Reset()
+	if err := r.RunWithArgs([]string{"fast"}, "lint"); err != nil || !strings.HasSuffix(buf.String(), " node:20 bash -c \"npm run lint\" lint fast\n") {
+		t.Errorf("Command should have received the arguments as positional parameters, got %v and %q!", err, buf.String())
+	}
+
+	// case 5: uses an unknown executor.
+	if err := r.Run("k8s"); err == nil {
+		t.Error("Task with an unknown executor should not have been run!")
+	}
+
+	// case 6: uses a Docker executor without image.
+	if err := r.Run("imageless"); err == nil {
+		t.Error("Task with a Docker executor without image should not have been run!")
+	}
+
+	// case 7: uses the validation of the executors.
+	var problems []string
+	for _, problem := range r.Check() {
+		problems = append(problems, problem.Error())
+	}
+
+	if all := strings.Join(problems, "\n"); !strings.Contains(all, "task k8s has an unknown executor kubernetes") || !strings.Contains(all, "task imageless has a docker executor without image") {
+		t.Errorf("Invalid executors should have been reported, got %v!", problems)
+	}
+}
@@ -0,0 +1,235 @@
+package runner
+
+import (
+	"strings"
+
+	OrbitError "github.com/gulien/orbit/app/error"
+)
+
+const (
+	// replaceMergeMode replaces the lists of commands of the base task
+	// by the lists of the extending task, if not empty.
+	replaceMergeMode = "replace"
+
+	// appendMergeMode appends the lists of commands of the extending task
+	// to the lists of the base task.
+	appendMergeMode = "append"
+)
+
+/*
+resolveExtends populates each task extending another task with the attributes
+of its base task.
+
+The scalar attributes of the base task are inherited unless they are overridden
+(a boolean attribute defined to false overrides a true one), except the private attribute which is never inherited. The lists of commands are either
+replaced or appended according to the merge attribute of the extending task.
+
+Returns the problems of all the tasks: a cycle is reported once.
+*/
+func resolveExtends(config *orbitRunnerConfig) []error {
+	tasks := make(map[string]*orbitTask)
+	for _, task := range config.Tasks {
+		tasks[task.Use] = task
+	}
+
+	var problems []error
+	resolved := make(map[*orbitTask]bool)
+	for _, task := range config.Tasks {
+		if err := resolveTaskExtends(task, tasks, resolved, nil); err != nil {
+			problems = append(problems, err)
+		}
+	}
+
+	return problems
+}
+
+// resolveTaskExtends populates the given task with the attributes of its base task.
+// The chain argument contains the names of the tasks being resolved.
+func resolveTaskExtends(task *orbitTask, tasks map[string]*orbitTask, resolved map[*orbitTask]bool, chain []string) error {
+	if task.Extends == "" || resolved[task] {
+		return nil
+	}
+
+	chain = append(chain, task.Use)
+	for _, name := range chain[:len(chain)-1] {
+		if name == task.Use {
+			// the tasks of the cycle are not resolved again.
+			for _, name := range chain {
+				resolved[tasks[name]] = true
+			}
+
+			return OrbitError.NewOrbitErrorf("cyclic extends detected: %s", strings.Join(chain, " -> "))
+		}
+	}
+
+	base, ok := tasks[task.Extends]
+	if !ok {
+		resolved[task] = true
+		return OrbitError.NewOrbitErrorf("task %s extends task %s which does not exist", task.Use, task.Extends)
+	}
+
+	if err := resolveTaskExtends(base, tasks, resolved, chain); err != nil {
+		return err
+	}
+
+	resolved[task] = true
+
+	return mergeTask(task, base)
+}
+
+// mergeTask populates the given task with the attributes of the given base task.
+func mergeTask(task *orbitTask, base *orbitTask) error {
+	if task.Shell == "" {
+		task.Shell = base.Shell
+	}
+
+	if task.Short == "" {
+		task.Short = base.Short
+	}
+
+	if task.Executor == nil {
+		task.Executor = base.Executor
+	}
+
+	if task.Stdout == "" {
+		task.Stdout = base.Stdout
+	}
+
+	if task.Stderr == "" {
+		task.Stderr = base.Stderr
+	}
+
+	if task.Stdin == "" {
+		task.Stdin = base.Stdin
+	}
+
+	if task.Echo == nil {
+		task.Echo = base.Echo
+	}
+
+	for key, value := range base.Env {
+		if _, ok := task.Env[key]; ok {
+			continue
+		}
+
+		if task.Env == nil {
+			task.Env = make(map[string]string)
+		}
+
+		task.Env[key] = value
+	}
+
+	if task.Dir == "" {
+		task.Dir = base.Dir
+	}
+
+	if len(task.Args) == 0 {
+		task.Args = base.Args
+	}
+
+	if task.Sentinel == nil {
+		task.Sentinel = base.Sentinel
+	}
+
+	if task.Watch == nil {
+		task.Watch = base.Watch
+	}
+
+	if task.Timeout == 0 {
+		task.Timeout = base.Timeout
+	}
+
+	if task.TaskTimeout == 0 {
+		task.TaskTimeout = base.TaskTimeout
+	}
+
+	if task.IgnoreErrors == nil {
+		task.IgnoreErrors = base.IgnoreErrors
+	}
+
+	if task.ContinueOnError == nil {
+		task.ContinueOnError = base.ContinueOnError
+	}
+
+	if task.Retry == nil {
+		task.Retry = base.Retry
+	}
+
+	if task.When == nil {
+		task.When = base.When
+	}
+
+	if task.Parallel == nil {
+		task.Parallel = base.Parallel
+	}
+
+	if task.DiffPrevious == nil {
+		task.DiffPrevious = base.DiffPrevious
+	}
+
+	var merge func(base []string, override []string) []string
+	var mergeStack func(base orbitCommands, override orbitCommands) orbitCommands
+	switch task.Merge {
+	case "", replaceMergeMode:
+		merge, mergeStack = replaceCommands, replaceStack
+	case appendMergeMode:
+		merge, mergeStack = appendCommands, appendStack
+	default:
+		return OrbitError.NewOrbitErrorf("task %s has an unknown merge mode %s, expected %s or %s", task.Use, task.Merge, replaceMergeMode, appendMergeMode)
+	}
+
+	task.Deps = merge(base.Deps, task.Deps)
+	task.EnvFiles = merge(base.EnvFiles, task.EnvFiles)
+	task.Sources = merge(base.Sources, task.Sources)
+	task.Generates = merge(base.Generates, task.Generates)
+	task.RequiresEnv = merge(base.RequiresEnv, task.RequiresEnv)
+	task.Mkdir = merge(base.Mkdir, task.Mkdir)
+	task.RunIfChanged = merge(base.RunIfChanged, task.RunIfChanged)
+	task.Run = mergeStack(base.Run, task.Run)
+	task.AfterSuccess = merge(base.AfterSuccess, task.AfterSuccess)
+	task.AfterFailure = merge(base.AfterFailure, task.AfterFailure)
+	task.Before = merge(base.Before, task.Before)
+	task.After = merge(base.After, task.After)
+	task.OnFailure = merge(base.OnFailure, task.OnFailure)
+
+	return nil
+}
+
+// isEnabled returns true if the given boolean attribute of a task is defined to true.
+func isEnabled(attribute *bool) bool {
+	return attribute != nil && *attribute
+}
+
+// replaceCommands returns the overriding commands if any, otherwise the base commands.
+func replaceCommands(base []string, override []string) []string {
+	if len(override) > 0 {
+		return override
+	}
+
+	return base
+}
+
+// appendCommands returns the base commands followed by the overriding commands.
+func appendCommands(base []string, override []string) []string {
+	result := make([]string, 0, len(base)+len(override))
+	result = append(result, base...)
+
+	return append(result, override...)
+}
+
+// replaceStack returns the overriding stack of commands if not empty, otherwise the base one.
+func replaceStack(base orbitCommands, override orbitCommands) orbitCommands {
+	if len(override) > 0 {
+		return override
+	}
+
+	return base
+}
+
+// appendStack returns the base stack of commands followed by the overriding one.
+func appendStack(base orbitCommands, override orbitCommands) orbitCommands {
+	result := make(orbitCommands, 0, len(base)+len(override))
+	result = append(result, base...)
+
+	return append(result, override...)
+}
@@ -0,0 +1,59 @@
+package runner
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/gulien/orbit/app/context"
+)
+
+// Tests if the tasks extending others tasks inherit their attributes.
+func TestResolveExtends(t *testing.T) {
+	// case 1: uses a configuration file with cyclic extends.
+	brokenTemplateFilePath, _ := filepath.Abs("../../_tests/broken-orbit-extends.yml")
+	ctx, _ := context.NewOrbitContext(brokenTemplateFilePath, "", "")
+	if _, err := NewOrbitRunner(ctx); err == nil {
+		t.Error("OrbitRunner should not have been instantiated with cyclic extends!")
+	}
+
+	// case 2: uses a configuration file extending a non existing task.
+	brokenTemplateFilePath, _ = filepath.Abs("../../_tests/broken-orbit-extends-missing-task.yml")
+	ctx, _ = context.NewOrbitContext(brokenTemplateFilePath, "", "")
+	if _, err := NewOrbitRunner(ctx); err == nil {
+		t.Error("OrbitRunner should not have been instantiated with a non existing extended task!")
+	}
+
+	// case 3: uses a correct configuration file.
+	templateFilePath, _ := filepath.Abs("../../_tests/orbit-extends.yml")
+	ctx, _ = context.NewOrbitContext(templateFilePath, "", "")
+	r, err := NewOrbitRunner(ctx)
+	if err != nil {
+		t.Fatal("OrbitRunner should have been instantiated!")
+	}
+
+	falcon9 := r.getTask("falcon 9")
+	if falcon9.Private || falcon9.Short != "a launcher" || falcon9.Shell != "bash -c" || !reflect.DeepEqual(falcon9.Run, plainCommands([]string{`echo "I am launcher task"`})) {
+		t.Errorf("Task falcon 9 should have inherited the attributes of task launcher, got %+v!", falcon9)
+	}
+
+	falconHeavy := r.getTask("falcon heavy")
+	if !reflect.DeepEqual(falconHeavy.Run, plainCommands([]string{`echo "I am launcher task"`, `echo "I am falcon heavy task"`})) {
+		t.Errorf("Task falcon heavy should have appended its commands, got %v!", falconHeavy.Run)
+	}
+
+	starship := r.getTask("starship")
+	if starship.Shell != "sh -c" || !reflect.DeepEqual(starship.Run, plainCommands([]string{`echo "I am starship task"`})) || len(starship.AfterFailure) != 1 {
+		t.Errorf("Task starship should have overridden the attributes of task launcher, got %+v!", starship)
+	}
+
+	if !isEnabled(falcon9.Echo) || !isEnabled(falcon9.IgnoreErrors) {
+		t.Error("Task falcon 9 should have inherited the boolean attributes of task launcher!")
+	}
+
+	// case 4: uses a task setting the boolean attributes of its base task back to false.
+	soyuz := r.getTask("soyuz")
+	if isEnabled(soyuz.Echo) || isEnabled(soyuz.IgnoreErrors) || soyuz.Short != "a launcher" {
+		t.Errorf("Task soyuz should have overridden the boolean attributes of task launcher with false, got %+v!", soyuz)
+	}
+}
@@ -0,0 +1,190 @@
+package runner
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+
+	OrbitError "github.com/gulien/orbit/app/error"
+	"github.com/gulien/orbit/app/logger"
+)
+
+// fingerprints file name, inside the cache directory.
+const fingerprintsFileName = "fingerprints.json"
+
+type (
+	// orbitFingerprint contains the checksums of the files of a task after its last successful run.
+	orbitFingerprint struct {
+		// Sources is the checksum of the files matching the sources attribute of the task.
+		Sources string `json:"sources"`
+
+		// Generates is the checksum of the files matching the generates attribute of the task.
+		Generates string `json:"generates"`
+	}
+
+	// orbitFingerprints contains the fingerprints of the tasks.
+	orbitFingerprints struct {
+		// Tasks contains the fingerprint of each task.
+		Tasks map[string]*orbitFingerprint `json:"tasks"`
+	}
+)
+
+// fingerprintsFilePath returns the path of the fingerprints file.
+func (r *OrbitRunner) fingerprintsFilePath() string {
+	return filepath.Join(r.context.BaseDir(), cacheDirName, fingerprintsFileName)
+}
+
+// loadFingerprints reads the fingerprints file. If it does not exist, returns empty fingerprints.
+func (r *OrbitRunner) loadFingerprints() (*orbitFingerprints, error) {
+	fingerprints := &orbitFingerprints{Tasks: make(map[string]*orbitFingerprint)}
+
+	filePath := r.fingerprintsFilePath()
+	data, err := ioutil.ReadFile(filePath)
+	if os.IsNotExist(err) {
+		return fingerprints, nil
+	}
+
+	if err != nil {
+		return nil, OrbitError.NewOrbitErrorf("unable to read the fingerprints file %s. Details:\n%s", filePath, err)
+	}
+
+	if err := json.Unmarshal(data, fingerprints); err != nil {
+		return nil, OrbitError.NewOrbitErrorf("fingerprints file %s is not a valid JSON file. Details:\n%s", filePath, err)
+	}
+
+	if fingerprints.Tasks == nil {
+		fingerprints.Tasks = make(map[string]*orbitFingerprint)
+	}
+
+	return fingerprints, nil
+}
+
+/*
+checksum returns the checksum of the paths and the contents of the files matching the given glob patterns,
+relative to the configuration file, and whether each pattern matches at least one file.
+*/
+func (r *OrbitRunner) checksum(patterns []string) (string, bool, error) {
+	root := r.context.BaseDir()
+
+	var files []string
+	err := r.walkSources(patterns, func(rel string, info os.FileInfo) error {
+		files = append(files, rel)
+		return nil
+	})
+
+	if err != nil {
+		return "", false, OrbitError.NewOrbitErrorf("unable to find the files matching %s. Details:\n%s", patterns, err)
+	}
+
+	complete := true
+	for _, pattern := range patterns {
+		if !matchSources([]string{pattern}, files) {
+			complete = false
+		}
+	}
+
+	sort.Strings(files)
+
+	hash := sha256.New()
+	for _, rel := range files {
+		file, err := os.Open(filepath.Join(root, filepath.FromSlash(rel)))
+		if err != nil {
+			return "", false, OrbitError.NewOrbitErrorf("unable to read file %s. Details:\n%s", rel, err)
+		}
+
+		io.WriteString(hash, rel+"\x00")
+		_, err = io.Copy(hash, file)
+		file.Close()
+
+		if err != nil {
+			return "", false, OrbitError.NewOrbitErrorf("unable to read file %s. Details:\n%s", rel, err)
+		}
+
+		hash.Write([]byte{0})
+	}
+
+	return hex.EncodeToString(hash.Sum(nil)), complete, nil
+}
+
+/*
+isUpToDate returns true if the given task may be skipped: if it has a generates attribute, if Force is false,
+and if neither its sources nor its generated files have changed since its last successful run.
+A generated file which is missing makes the task out of date.
+
+Also returns the checksum of the sources of the task, to record once it has succeeded.
+*/
+func (r *OrbitRunner) isUpToDate(task *orbitTask) (bool, string, error) {
+	if len(task.Generates) == 0 {
+		return false, "", nil
+	}
+
+	sources, _, err := r.checksum(task.Sources)
+	if err != nil {
+		return false, "", err
+	}
+
+	if r.Force {
+		return false, sources, nil
+	}
+
+	fingerprints, err := r.loadFingerprints()
+	if err != nil {
+		return false, "", err
+	}
+
+	fingerprint, ok := fingerprints.Tasks[task.Use]
+	if !ok || fingerprint.Sources != sources {
+		return false, sources, nil
+	}
+
+	generates, complete, err := r.checksum(task.Generates)
+	if err != nil {
+		return false, "", err
+	}
+
+	return complete && fingerprint.Generates == generates, sources, nil
+}
+
+/*
+recordFingerprint writes the given checksum of the sources of the given task and the checksum of its generated files
+into the fingerprints file, if the task has a generates attribute.
+
+The checksum of the sources is computed before running the task, so that a file modified while the task was running
+is considered as changed.
+*/
+func (r *OrbitRunner) recordFingerprint(task *orbitTask, sources string) {
+	if len(task.Generates) == 0 {
+		return
+	}
+
+	generates, _, err := r.checksum(task.Generates)
+	if err != nil {
+		logger.Warnf("unable to record the fingerprint of task %s: %s", task.Use, err)
+		return
+	}
+
+	fingerprints, err := r.loadFingerprints()
+	if err != nil {
+		logger.Warnf("unable to record the fingerprint of task %s: %s", task.Use, err)
+		return
+	}
+
+	fingerprints.Tasks[task.Use] = &orbitFingerprint{Sources: sources, Generates: generates}
+
+	data, err := json.MarshalIndent(fingerprints, "", "  ")
+	if err == nil {
+		filePath := r.fingerprintsFilePath()
+		if err = os.MkdirAll(filepath.Dir(filePath), 0755); err == nil {
+			err = ioutil.WriteFile(filePath, data, 0644)
+		}
+	}
+
+	if err != nil {
+		logger.Warnf("unable to record the fingerprint of task %s: %s", task.Use, err)
+	}
+}
@@ -0,0 +1,63 @@
+package runner
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/gulien/orbit/app/context"
+)
+
+// Tests if a task having a generates attribute is skipped as long as its files remain unchanged.
+func TestFingerprint(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("skipping test on Windows")
+	}
+
+	dirPath, _ := filepath.Abs("../../_tests/fingerprint")
+	os.MkdirAll(filepath.Join(dirPath, "src"), 0755)
+	ioutil.WriteFile(filepath.Join(dirPath, "src", "falcon.txt"), []byte("Falcon 9\n"), 0644)
+	defer os.RemoveAll(dirPath)
+
+	templateFilePath, _ := filepath.Abs("../../_tests/orbit-fingerprint.yml")
+	ctx, _ := context.NewOrbitContext(templateFilePath, "", "")
+	r, _ := NewOrbitRunner(ctx)
+	defer os.RemoveAll(filepath.Dir(r.fingerprintsFilePath()))
+
+	runs := func() int {
+		data, _ := ioutil.ReadFile(filepath.Join(dirPath, "runs.txt"))
+		return strings.Count(string(data), "build")
+	}
+
+	// case 1: uses a task which has never succeeded, then runs it again.
+	for i := 0; i < 2; i++ {
+		if err := r.Run("build"); err != nil {
+			t.Errorf("Task should have been run, got %s!", err)
+		}
+	}
+
+	if runs() != 1 {
+		t.Errorf("Task whose files have not changed should have been skipped, got %d runs!", runs())
+	}
+
+	// case 2: uses a task whose sources have changed.
+	ioutil.WriteFile(filepath.Join(dirPath, "src", "electron.txt"), []byte("Electron\n"), 0644)
+	if err := r.Run("build"); err != nil || runs() != 2 {
+		t.Errorf("Task whose sources have changed should have been run, got %d runs!", runs())
+	}
+
+	// case 3: uses a task whose generated file has been deleted.
+	os.Remove(filepath.Join(dirPath, "launchers.txt"))
+	if err := r.Run("build"); err != nil || runs() != 3 {
+		t.Errorf("Task whose generated file is missing should have been run, got %d runs!", runs())
+	}
+
+	// case 4: uses force.
+	r.Force = true
+	if err := r.Run("build"); err != nil || runs() != 4 {
+		t.Errorf("Task should have been forced, got %d runs!", runs())
+	}
+}
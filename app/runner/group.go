@@ -0,0 +1,35 @@
+package runner
+
+import (
+	OrbitError "github.com/gulien/orbit/app/error"
+)
+
+/*
+GroupTasks returns the names of the tasks from the given group, in their order of declaration.
+
+A task appearing many times in the group is only returned once.
+If the group does not exist or references a task which does not exist, returns an error:
+this way, no task from the group is run.
+*/
+func (r *OrbitRunner) GroupTasks(name string) ([]string, error) {
+	group, ok := r.config.Groups[name]
+	if !ok {
+		return nil, OrbitError.NewOrbitErrorf("group %s does not exist in configuration file %s", name, r.context.TemplateFilePath)
+	}
+
+	var tasks []string
+	seen := make(map[string]bool)
+
+	for _, task := range group {
+		if r.getTask(task) == nil {
+			return nil, OrbitError.NewOrbitErrorf("group %s references task %s which does not exist in configuration file %s", name, task, r.context.TemplateFilePath)
+		}
+
+		if !seen[task] {
+			seen[task] = true
+			tasks = append(tasks, task)
+		}
+	}
+
+	return tasks, nil
+}
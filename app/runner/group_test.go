@@ -0,0 +1,32 @@
+package runner
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/gulien/orbit/app/context"
+)
+
+// Tests if the tasks of a group are returned in their order of declaration.
+func TestRunnerGroupTasks(t *testing.T) {
+	templateFilePath, _ := filepath.Abs("../../_tests/orbit-groups.yml")
+	ctx, _ := context.NewOrbitContext(templateFilePath, "", "")
+	r, _ := NewOrbitRunner(ctx)
+
+	// case 1: uses a non existing group.
+	if _, err := r.GroupTasks("agencies"); err == nil {
+		t.Error("Non existing group should not have been found!")
+	}
+
+	// case 2: uses a group referencing a non existing task.
+	if _, err := r.GroupTasks("rockets"); err == nil {
+		t.Error("Group referencing a non existing task should not have been resolved!")
+	}
+
+	// case 3: uses a correct group.
+	tasks, err := r.GroupTasks("launchers")
+	if err != nil || !reflect.DeepEqual(tasks, []string{"falcon 9", "falcon heavy"}) {
+		t.Errorf("Group should have been resolved without duplicates, got %v!", tasks)
+	}
+}
@@ -0,0 +1,165 @@
+package runner
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	OrbitError "github.com/gulien/orbit/app/error"
+	"github.com/gulien/orbit/app/logger"
+)
+
+// Orbit cache directory, relative to the configuration file.
+const cacheDirName = ".orbit"
+
+// history file name, inside the cache directory.
+const historyFileName = "history.json"
+
+// orbitHistory contains the durations of the last runs of the tasks.
+type orbitHistory struct {
+	// Durations contains the duration of the last run of each task, in seconds.
+	Durations map[string]float64 `json:"durations"`
+}
+
+// historyFilePath returns the path of the history file.
+func (r *OrbitRunner) historyFilePath() string {
+	return filepath.Join(r.context.BaseDir(), cacheDirName, historyFileName)
+}
+
+// loadHistory reads the history file. If it does not exist, returns an empty history.
+func (r *OrbitRunner) loadHistory() (*orbitHistory, error) {
+	history := &orbitHistory{Durations: make(map[string]float64)}
+
+	filePath := r.historyFilePath()
+	data, err := ioutil.ReadFile(filePath)
+	if os.IsNotExist(err) {
+		return history, nil
+	}
+
+	if err != nil {
+		return nil, OrbitError.NewOrbitErrorf("unable to read the history file %s. Details:\n%s", filePath, err)
+	}
+
+	if err := json.Unmarshal(data, history); err != nil {
+		return nil, OrbitError.NewOrbitErrorf("history file %s is not a valid JSON file. Details:\n%s", filePath, err)
+	}
+
+	if history.Durations == nil {
+		history.Durations = make(map[string]float64)
+	}
+
+	return history, nil
+}
+
+/*
+recordDuration writes the duration of the last run of the given task into the history file,
+if the history is enabled in the configuration file.
+
+As the history is only an estimation aid, failing to write it is not an error.
+*/
+func (r *OrbitRunner) recordDuration(task *orbitTask, elapsed time.Duration) {
+	if !r.config.History {
+		return
+	}
+
+	history, err := r.loadHistory()
+	if err != nil {
+		logger.Warnf("unable to record the duration of task %s: %s", task.Use, err)
+		return
+	}
+
+	history.Durations[task.Use] = elapsed.Seconds()
+
+	data, err := json.MarshalIndent(history, "", "  ")
+	if err == nil {
+		filePath := r.historyFilePath()
+		if err = os.MkdirAll(filepath.Dir(filePath), 0755); err == nil {
+			err = ioutil.WriteFile(filePath, data, 0644)
+		}
+	}
+
+	if err != nil {
+		logger.Warnf("unable to record the duration of task %s: %s", task.Use, err)
+	}
+}
+
+/*
+PrintPlan prints to Stdout the given tasks and the tasks they call, with the duration
+of their last run if any.
+
+The estimated duration is the sum of the durations of the given tasks, as the duration
+of a task includes the durations of the tasks it calls.
+*/
+func (r *OrbitRunner) PrintPlan(names ...string) error {
+	history, err := r.loadHistory()
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(r.Stdout, 0, 0, 1, ' ', tabwriter.TabIndent)
+	fmt.Fprint(w, "Plan:")
+
+	var total float64
+	complete := true
+
+	for _, name := range names {
+		if err := r.printPlanTask(w, history, name, 1, nil); err != nil {
+			return err
+		}
+
+		duration, ok := history.Durations[name]
+		total += duration
+		complete = complete && ok
+	}
+
+	estimation := (time.Duration(total * float64(time.Second))).String()
+	if !complete {
+		estimation = "at least " + estimation
+	}
+
+	fmt.Fprintf(w, "\n\nEstimated duration: %s\n", estimation)
+
+	return w.Flush()
+}
+
+// printPlanTask prints the given task with its duration, then the tasks it calls.
+// The chain argument contains the names of the tasks being printed.
+func (r *OrbitRunner) printPlanTask(w *tabwriter.Writer, history *orbitHistory, name string, level int, chain []string) error {
+	task := r.getTask(name)
+	if task == nil {
+		return OrbitError.NewOrbitErrorf("task %s does not exist in configuration file %s", name, r.context.TemplateFilePath)
+	}
+
+	for _, previous := range chain {
+		if previous == name {
+			return OrbitError.NewOrbitErrorf("unable to print the plan of task %s as it calls itself", name)
+		}
+	}
+
+	duration := "unknown"
+	if seconds, ok := history.Durations[name]; ok {
+		duration = (time.Duration(seconds * float64(time.Second))).String()
+	}
+
+	fmt.Fprintf(w, "\n%s%s\t%s", strings.Repeat("  ", level), name, duration)
+
+	for _, entry := range task.Run {
+		call := r.interpret(entry.Command)
+		if call == nil {
+			continue
+		}
+
+		for _, subtask := range call.tasks {
+			if err := r.printPlanTask(w, history, subtask, level+1, append(chain, name)); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,53 @@
+package runner
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gulien/orbit/app/context"
+)
+
+// Tests if the durations of the tasks are recorded and printed in the plan.
+func TestPrintPlan(t *testing.T) {
+	templateFilePath, _ := filepath.Abs("../../_tests/orbit-history.yml")
+	ctx, _ := context.NewOrbitContext(templateFilePath, "", "")
+	r, _ := NewOrbitRunner(ctx)
+	defer os.RemoveAll(filepath.Dir(r.historyFilePath()))
+
+	// case 1: uses tasks without history.
+	if err := r.PrintPlan("new shepard"); err != nil {
+		t.Error("Plan should have been printed without history!")
+	}
+
+	// case 2: uses tasks with history.
+	r.Run("explorer")
+	history, _ := r.loadHistory()
+	if _, ok := history.Durations["explorer"]; !ok {
+		t.Error("Duration of task explorer should have been recorded!")
+	}
+
+	if err := r.PrintPlan("new shepard", "explorer"); err != nil {
+		t.Error("Plan should have been printed with history!")
+	}
+
+	// case 3: uses a non existing task.
+	if err := r.PrintPlan("discovery"); err == nil {
+		t.Error("Plan should not have been printed with a non existing task!")
+	}
+
+	// case 4: uses a task calling itself.
+	if err := r.PrintPlan("vulcan"); err == nil {
+		t.Error("Plan should not have been printed with a task calling itself!")
+	}
+
+	// case 5: uses a configuration file without history.
+	os.RemoveAll(filepath.Dir(r.historyFilePath()))
+	templateFilePath, _ = filepath.Abs("../../_tests/orbit.yml")
+	ctx, _ = context.NewOrbitContext(templateFilePath, "", "")
+	r, _ = NewOrbitRunner(ctx)
+	r.Run("explorer")
+	if _, err := os.Stat(r.historyFilePath()); err == nil {
+		t.Error("Duration of task explorer should not have been recorded!")
+	}
+}
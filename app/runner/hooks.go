@@ -0,0 +1,63 @@
+package runner
+
+import (
+	gocontext "context"
+
+	OrbitError "github.com/gulien/orbit/app/error"
+	"github.com/gulien/orbit/app/logger"
+)
+
+// isTopLevel returns true if the running task has been given to Run, and not called by another task.
+func (r *OrbitRunner) isTopLevel() bool {
+	return len(r.callStack) == 1
+}
+
+// beforeStack returns the before commands of the given task, preceded by the global ones if it's a top level task.
+func (r *OrbitRunner) beforeStack(task *orbitTask) []string {
+	if !r.isTopLevel() {
+		return task.Before
+	}
+
+	return append(append([]string{}, r.config.Before...), task.Before...)
+}
+
+// failureStack returns the after_failure and on_failure commands of the given task,
+// followed by the global on_failure ones if it's a top level task.
+func (r *OrbitRunner) failureStack(task *orbitTask) []string {
+	stack := append(append([]string{}, task.AfterFailure...), task.OnFailure...)
+	if !r.isTopLevel() {
+		return stack
+	}
+
+	return append(stack, r.config.OnFailure...)
+}
+
+// afterStack returns the after commands of the given task, followed by the global ones if it's a top level task.
+func (r *OrbitRunner) afterStack(task *orbitTask) []string {
+	if !r.isTopLevel() {
+		return task.After
+	}
+
+	return append(append([]string{}, task.After...), r.config.After...)
+}
+
+/*
+runAfter executes the after commands of the given running task, whatever its result.
+
+As they usually clean up what the task has set up, they are not cancelled with the task (timeout of the task,
+sentinel, interruption): only their own timeout per command applies. Their failure is logged.
+*/
+func (r *OrbitRunner) runAfter(state *orbitTaskState) {
+	stack := r.afterStack(state.task)
+	if len(stack) == 0 {
+		return
+	}
+
+	logger.Infof("running after commands from task %s", state.task.Use)
+
+	afterState := *state
+	afterState.ctx = gocontext.Background()
+	if err := r.runStack(&afterState, plainCommands(stack)); err != nil {
+		logger.Error(OrbitError.NewOrbitErrorf("after commands from task %s have failed. Details:\n%s", state.task.Use, err))
+	}
+}
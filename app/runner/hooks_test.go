@@ -0,0 +1,71 @@
+package runner
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/gulien/orbit/app/context"
+)
+
+// readHooksLog returns the content of the file written by the hooks fixture, and removes it.
+func readHooksLog() string {
+	data, _ := ioutil.ReadFile("hooks.log")
+	os.Remove("hooks.log")
+
+	return string(data)
+}
+
+// Tests if the before, after and on_failure commands are executed around the commands of the tasks.
+func TestHooks(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("skipping test on Windows")
+	}
+
+	templateFilePath, _ := filepath.Abs("../../_tests/orbit-hooks.yml")
+	ctx, _ := context.NewOrbitContext(templateFilePath, "", "")
+	r, _ := NewOrbitRunner(ctx)
+	defer os.Remove("hooks.log")
+
+	// case 1: uses a succeeding task calling another task.
+	if err := r.Run("explorer"); err != nil {
+		t.Errorf("Task should have been run with its hooks, got %s!", err)
+	}
+
+	expected := "global before\nbefore\nrun\nsputnik before\nsputnik run\nsputnik after\nafter\nglobal after\n"
+	if log := readHooksLog(); log != expected {
+		t.Errorf("Hooks should have been executed around the commands, got %q!", log)
+	}
+
+	// case 2: uses a failing task.
+	if err := r.Run("vostok"); err == nil {
+		t.Error("Failing task should have thrown an error!")
+	}
+
+	expected = "global before\non_failure\nglobal on_failure\nafter\nglobal after\n"
+	if log := readHooksLog(); log != expected {
+		t.Errorf("Failure hooks and after commands should have been executed, got %q!", log)
+	}
+
+	// case 3: uses a task whose before commands fail.
+	if err := r.Run("soyuz"); err == nil {
+		t.Error("Task whose before commands fail should have thrown an error!")
+	}
+
+	expected = "global before\nglobal on_failure\nafter\nglobal after\n"
+	if log := readHooksLog(); log != expected {
+		t.Errorf("Commands should not have been executed after the failure of the before commands, got %q!", log)
+	}
+
+	// case 4: uses a task exceeding its timeout, which cancels its failure hooks but not its after commands.
+	if err := r.Run("gemini"); err == nil {
+		t.Error("Task exceeding its timeout should have thrown an error!")
+	}
+
+	expected = "global before\nafter\nglobal after\n"
+	if log := readHooksLog(); log != expected {
+		t.Errorf("After commands should have been executed once the timeout has been exceeded, got %q!", log)
+	}
+}
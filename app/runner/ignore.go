@@ -0,0 +1,74 @@
+package runner
+
+import (
+	"strings"
+
+	OrbitError "github.com/gulien/orbit/app/error"
+	"github.com/gulien/orbit/app/logger"
+)
+
+// ignoreFailurePrefix is the prefix of a command whose failure does not stop its task, like in a Makefile.
+// The space is required, so that a command starting with a dash (e.g. the argument "-l" of a custom shell) is not affected.
+const ignoreFailurePrefix = "- "
+
+// ignoredFailure returns the given command without its prefix and true if its failure should not stop the given task:
+// either the command has the ignore failure prefix, or the task has the ignore_errors attribute.
+func ignoredFailure(cmd string, task *orbitTask) (string, bool) {
+	if strings.HasPrefix(cmd, ignoreFailurePrefix) {
+		return strings.TrimLeft(strings.TrimPrefix(cmd, ignoreFailurePrefix), " "), true
+	}
+
+	return cmd, isEnabled(task.IgnoreErrors)
+}
+
+/*
+executeCommand executes the given command from the given running task like execute does,
+retrying it according to the retry policy of the task.
+
+If the failure of the command should be ignored, it's logged as a warning and the given environment
+is returned instead of an error. A command cancelled with its task (timeout of the task, sentinel) is never ignored.
+*/
+func (r *OrbitRunner) executeCommand(cmd string, state *orbitTaskState, environ []string) ([]string, error) {
+	cmd, ignore := ignoredFailure(cmd, state.task)
+
+	state.failure = nil
+	result, err := r.executeWithRetry(cmd, state, environ)
+	if err == nil {
+		return result, nil
+	}
+
+	if !ignore || state.ctx.Err() != nil {
+		r.recordFailure(state)
+		return result, err
+	}
+
+	logger.Warnf("ignoring the failure of command %s from task %s. Details:\n%s", cmd, state.task.Use, err)
+
+	return environ, nil
+}
+
+// continueOnError returns true if the given failure should not stop the given running task: the task
+// has the continue_on_error attribute and has not been cancelled (timeout of the task, sentinel).
+func continueOnError(state *orbitTaskState, err error) bool {
+	if !isEnabled(state.task.ContinueOnError) || state.ctx.Err() != nil {
+		return false
+	}
+
+	logger.Warnf("continuing task %s despite a failure. Details:\n%s", state.task.Use, err)
+
+	return true
+}
+
+// failuresError returns the error aggregating the given failures from the given task, or nil if there is none.
+func failuresError(task *orbitTask, errs []error) error {
+	if len(errs) == 0 {
+		return nil
+	}
+
+	details := make([]string, len(errs))
+	for index, err := range errs {
+		details[index] = err.Error()
+	}
+
+	return OrbitError.NewOrbitErrorf("%d command(s) from task %s have failed. Details:\n%s", len(errs), task.Use, strings.Join(details, "\n"))
+}
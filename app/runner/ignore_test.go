@@ -0,0 +1,72 @@
+package runner
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/gulien/orbit/app/context"
+)
+
+// Tests if the failures of the ignorable commands do not stop their tasks.
+func TestIgnoredFailure(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("sleep is not available on Windows")
+	}
+
+	templateFilePath, _ := filepath.Abs("../../_tests/orbit-ignore.yml")
+	ctx, _ := context.NewOrbitContext(templateFilePath, "", "")
+	r, _ := NewOrbitRunner(ctx)
+	defer os.Remove("sputnik.txt")
+	defer os.Remove("soyuz.txt")
+
+	// case 1: uses ignorable commands, including the last one.
+	if err := r.Run("explorer", "vostok"); err != nil {
+		t.Errorf("Failures of ignorable commands should have been ignored, got %s!", err)
+	}
+
+	if len(r.failures) != 0 {
+		t.Errorf("Ignored failures should not have been recorded, got %d!", len(r.failures))
+	}
+
+	// case 2: uses an ignorable command followed by a fatal command.
+	if err := r.Run("sputnik"); err == nil {
+		t.Error("Failure of a fatal command should have stopped the task!")
+	}
+
+	if _, err := os.Stat("sputnik.txt"); err == nil {
+		t.Error("Commands after a fatal command should not have been executed!")
+	}
+
+	// case 3: uses a task with ignore_errors exceeding its timeout.
+	if err := r.Run("soyuz"); err == nil {
+		t.Error("Timeout of a task should not have been ignored!")
+	}
+
+	if _, err := os.Stat("soyuz.txt"); err == nil {
+		t.Error("Commands after the timeout of a task should not have been executed!")
+	}
+
+	// case 4: uses a command starting with a dash without space.
+	if err := r.Run("gemini"); err == nil {
+		t.Error("Failure of a command starting with a dash without space should not have been ignored!")
+	}
+}
+
+// Tests if only the commands with the ignore failure prefix are unwrapped.
+func TestIgnoreFailurePrefix(t *testing.T) {
+	task := &orbitTask{Use: "ariane"}
+
+	// case 1: uses a command with the prefix.
+	if cmd, ignored := ignoredFailure("-  exit 1", task); cmd != "exit 1" || !ignored {
+		t.Errorf("Command should have been unwrapped and ignored, got %q!", cmd)
+	}
+
+	// case 2: uses arguments starting with a dash, e.g. for a custom shell.
+	for _, arg := range []string{"-l", "--all", "-"} {
+		if cmd, ignored := ignoredFailure(arg, task); cmd != arg || ignored {
+			t.Errorf("Argument %s should have been given untouched, got %q!", arg, cmd)
+		}
+	}
+}
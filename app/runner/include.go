@@ -0,0 +1,276 @@
+package runner
+
+import (
+	"path/filepath"
+	"strings"
+
+	"github.com/gulien/orbit/app/context"
+	OrbitError "github.com/gulien/orbit/app/error"
+	"github.com/gulien/orbit/app/generator"
+	"github.com/gulien/orbit/app/logger"
+
+	"gopkg.in/yaml.v2"
+)
+
+// orbitInclude represents an included configuration file as defined in the configuration file:
+// either a path or a path with a namespace.
+type orbitInclude struct {
+	// Path is the path, relative to the configuration file, of the included files.
+	// It may be a glob pattern (e.g. tools/*.yml).
+	Path string `yaml:"path"`
+
+	// Namespace is prepended to the names of the included tasks (e.g. deploy:publish).
+	Namespace string `yaml:"namespace,omitempty"`
+}
+
+// UnmarshalYAML populates the included configuration file from either a path or a path with a namespace.
+func (i *orbitInclude) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var path string
+	if err := unmarshal(&path); err == nil {
+		i.Path = path
+		return nil
+	}
+
+	type plain orbitInclude
+
+	return unmarshal((*plain)(i))
+}
+
+/*
+resolveIncludes reads the configuration files included by the given configuration and adds their tasks.
+
+Each included file is a data-driven template executed with the same payload and additional templates.
+Its relative paths, including the ones of its own included files, are relative to the main configuration file.
+Only its tasks and its included files are read: an included file defining others attributes throws an error.
+Inside a namespaced file, the references (deps, extends and calls) to its own tasks are prefixed by the namespace,
+while the others references use full names. A task having the same name as an already defined task throws an error.
+*/
+func resolveIncludes(config *orbitRunnerConfig, ctx *context.OrbitContext) error {
+	names := make(map[string]bool)
+	for _, task := range config.Tasks {
+		names[task.Use] = true
+	}
+
+	return includeFiles(config, config.Includes, "", ctx, names, map[string]bool{ctx.TemplateFilePath: true})
+}
+
+// includeFiles adds to the given configuration the tasks of the given included files, prefixed by the given namespace.
+// The visited argument contains the paths of the files being included, in order to detect cycles.
+func includeFiles(config *orbitRunnerConfig, includes []*orbitInclude, namespace string, ctx *context.OrbitContext, names map[string]bool, visited map[string]bool) error {
+	for _, include := range includes {
+		pattern := include.Path
+		if !filepath.IsAbs(pattern) {
+			pattern = filepath.Join(ctx.BaseDir(), pattern)
+		}
+
+		filesPaths, err := filepath.Glob(pattern)
+		if err != nil {
+			return OrbitError.NewOrbitErrorf("included file %s is not a valid pattern. Details:\n%s", include.Path, err)
+		}
+
+		if len(filesPaths) == 0 && !hasGlobMeta(include.Path) {
+			return OrbitError.NewOrbitErrorf("included file %s does not exist", pattern)
+		}
+
+		for _, filePath := range filesPaths {
+			if visited[filePath] {
+				return OrbitError.NewOrbitErrorf("configuration file %s is included more than once", filePath)
+			}
+
+			visited[filePath] = true
+
+			included, err := readIncludedFile(filePath, ctx)
+			if err != nil {
+				return err
+			}
+
+			if attributes := mainAttributes(included); len(attributes) > 0 {
+				return OrbitError.NewOrbitErrorf("included file %s defines %s, which are only allowed in the main configuration file", filePath, strings.Join(attributes, ", "))
+			}
+
+			includeNamespace := joinNamespace(namespace, include.Namespace)
+			first := len(config.Tasks)
+			for _, task := range included.Tasks {
+				task.Use = joinNamespace(includeNamespace, task.Use)
+				if names[task.Use] {
+					return OrbitError.NewOrbitErrorf("task %s from included file %s is already defined", task.Use, filePath)
+				}
+
+				names[task.Use] = true
+				config.Tasks = append(config.Tasks, task)
+			}
+
+			if err := includeFiles(config, included.Includes, includeNamespace, ctx, names, visited); err != nil {
+				return err
+			}
+
+			// the tasks of the nested included files are added after the tasks of the file.
+			if includeNamespace != "" {
+				namespaceReferences(config.Tasks[first:first+len(included.Tasks)], includeNamespace, config.Tasks[first:])
+			}
+		}
+	}
+
+	return nil
+}
+
+// mainAttributes returns the attributes defined by the given included configuration
+// which are only allowed in the main configuration file.
+func mainAttributes(included *orbitRunnerConfig) []string {
+	var attributes []string
+	if len(included.Dotenv) > 0 {
+		attributes = append(attributes, "dotenv")
+	}
+
+	if len(included.Env) > 0 {
+		attributes = append(attributes, "env")
+	}
+
+	if included.Default != nil {
+		attributes = append(attributes, "default")
+	}
+
+	if len(included.Before) > 0 {
+		attributes = append(attributes, "before")
+	}
+
+	if len(included.After) > 0 {
+		attributes = append(attributes, "after")
+	}
+
+	if len(included.OnFailure) > 0 {
+		attributes = append(attributes, "on_failure")
+	}
+
+	if len(included.Groups) > 0 {
+		attributes = append(attributes, "groups")
+	}
+
+	if included.Notify != nil {
+		attributes = append(attributes, "notify")
+	}
+
+	if included.History {
+		attributes = append(attributes, "history")
+	}
+
+	if len(included.ConfirmDestructive) > 0 {
+		attributes = append(attributes, "confirm_destructive")
+	}
+
+	return attributes
+}
+
+/*
+namespaceReferences prefixes by the given namespace the references (deps, extends and calls) of the given tasks
+which name a task of their included file, i.e. one of the given included tasks once namespaced.
+The others references are kept as-is, as they are full names.
+*/
+func namespaceReferences(tasks []*orbitTask, namespace string, included []*orbitTask) {
+	names := make(map[string]bool)
+	for _, task := range included {
+		names[task.Use] = true
+	}
+
+	resolve := func(name string) string {
+		if fullName := joinNamespace(namespace, name); names[fullName] {
+			return fullName
+		}
+
+		return name
+	}
+
+	for _, task := range tasks {
+		for index, dep := range task.Deps {
+			task.Deps[index] = resolve(dep)
+		}
+
+		if task.Extends != "" {
+			task.Extends = resolve(task.Extends)
+		}
+
+		for index, entry := range task.Run {
+			task.Run[index].Command = namespaceCall(entry.Command, resolve)
+		}
+
+		for _, stack := range [][]string{task.Before, task.After, task.OnFailure, task.AfterSuccess, task.AfterFailure} {
+			for index, cmd := range stack {
+				stack[index] = namespaceCall(cmd, resolve)
+			}
+		}
+	}
+}
+
+// namespaceCall returns the given command with the names of the tasks it calls resolved by the given function.
+// Like with interpret, if the whole string after @ names tasks, there are no arguments.
+func namespaceCall(cmd string, resolve func(string) string) string {
+	match := compiledRegexp.FindStringSubmatch(cmd)
+	if len(match) == 0 {
+		return cmd
+	}
+
+	prefix := strings.TrimSuffix(cmd, match[2])
+	tasks, args := match[2], ""
+	if fields := strings.Fields(match[2]); len(fields) > 1 && !resolvesAll(strings.Split(match[2], ","), resolve) {
+		tasks = fields[0]
+		args = match[2][strings.Index(match[2], fields[0])+len(fields[0]):]
+	}
+
+	names := strings.Split(tasks, ",")
+	for index, name := range names {
+		names[index] = resolve(name)
+	}
+
+	return prefix + strings.Join(names, ",") + args
+}
+
+// resolvesAll returns true if all the given names are resolved by the given function.
+func resolvesAll(names []string, resolve func(string) string) bool {
+	for _, name := range names {
+		if resolve(name) == name {
+			return false
+		}
+	}
+
+	return true
+}
+
+// hasGlobMeta returns true if the given path contains any of the special characters of a glob pattern.
+func hasGlobMeta(path string) bool {
+	return strings.ContainsAny(path, "*?[")
+}
+
+// readIncludedFile executes the given included file and returns its configuration.
+func readIncludedFile(filePath string, ctx *context.OrbitContext) (*orbitRunnerConfig, error) {
+	logger.Infof("including configuration file %s", filePath)
+
+	includedContext := *ctx
+	includedContext.TemplateFilePath = filePath
+
+	data, err := generator.NewOrbitGenerator(&includedContext).Execute()
+	if err != nil {
+		return nil, err
+	}
+
+	var included = &orbitRunnerConfig{}
+	if err := yaml.Unmarshal(data.Bytes(), &included); err != nil {
+		return nil, OrbitError.NewOrbitErrorf("included file %s is not a valid YAML file. Details:\n%s", filePath, err)
+	}
+
+	resolvePlatforms(included)
+
+	if problems := resolveRunFiles(included, ctx.BaseDir()); len(problems) > 0 {
+		return nil, problems[0]
+	}
+
+	return included, nil
+}
+
+// joinNamespace returns the given name prefixed by the given namespace, if any.
+func joinNamespace(namespace string, name string) string {
+	if namespace == "" {
+		return name
+	}
+
+	return namespace + namespaceSeparator + name
+}
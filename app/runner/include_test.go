@@ -0,0 +1,104 @@
+package runner
+
+import (
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/gulien/orbit/app/context"
+)
+
+// Tests if the tasks from the included configuration files are added to the configuration.
+func TestResolveIncludes(t *testing.T) {
+	// case 1: uses included files with and without namespace.
+	templateFilePath, _ := filepath.Abs("../../_tests/orbit-includes.yml")
+	ctx, _ := context.NewOrbitContext(templateFilePath, "", "")
+	r, err := NewOrbitRunner(ctx)
+	if err != nil {
+		t.Fatalf("Included files should have been read, got %s!", err)
+	}
+
+	if lint := r.getTask("lint"); lint == nil || lint.Short != "Lints the "+runtime.GOOS+" sources" {
+		t.Error("Task lint should have been included and its template executed!")
+	}
+
+	for _, name := range []string{"deploy:publish", "deploy:nested:check"} {
+		if r.getTask(name) == nil {
+			t.Errorf("Task %s should have been included with its namespace!", name)
+		}
+	}
+
+	ship := r.getTask("deploy:ship")
+	if ship == nil || !reflect.DeepEqual(ship.Deps, []string{"deploy:nested:check"}) || ship.Extends != "deploy:publish" {
+		t.Fatal("References of task deploy:ship to the tasks of its file should have been namespaced!")
+	}
+
+	if !reflect.DeepEqual(ship.Run, plainCommands([]string{"run@deploy:publish", "run@lint"})) {
+		t.Errorf("Calls of task deploy:ship should have been namespaced, except the ones to others files, got %v!", ship.Run)
+	}
+
+	if runtime.GOOS != "windows" {
+		if err := r.Run("release", "deploy:ship"); err != nil {
+			t.Errorf("Included tasks should have been run, got %s!", err)
+		}
+	}
+
+	// case 2: uses an included file defining an already defined task.
+	templateFilePath, _ = filepath.Abs("../../_tests/orbit-includes-duplicate.yml")
+	ctx, _ = context.NewOrbitContext(templateFilePath, "", "")
+	if _, err := NewOrbitRunner(ctx); err == nil {
+		t.Error("Included file defining an already defined task should have thrown an error!")
+	}
+
+	// case 3: uses a non existing included file.
+	config := &orbitRunnerConfig{Includes: []*orbitInclude{{Path: "includes/unknown.yml"}}}
+	if err := resolveIncludes(config, &context.OrbitContext{TemplateFilePath: templateFilePath}); err == nil {
+		t.Error("Non existing included file should have thrown an error!")
+	}
+
+	// case 4: uses a glob pattern matching no file.
+	config = &orbitRunnerConfig{Includes: []*orbitInclude{{Path: "includes/*.unknown"}}}
+	if err := resolveIncludes(config, &context.OrbitContext{TemplateFilePath: templateFilePath}); err != nil {
+		t.Errorf("Glob pattern matching no file should not have thrown an error, got %s!", err)
+	}
+
+	// case 5: uses an included file defining others attributes than its tasks.
+	config = &orbitRunnerConfig{Includes: []*orbitInclude{{Path: "includes/orbit-sections.yml"}}}
+	if err := resolveIncludes(config, &context.OrbitContext{TemplateFilePath: templateFilePath}); err == nil || !strings.Contains(err.Error(), "defines env") {
+		t.Errorf("Included file defining others attributes should have thrown an error, got %v!", err)
+	}
+}
+
+// Tests if the calls to others tasks are resolved.
+func TestNamespaceCall(t *testing.T) {
+	resolve := func(name string) string {
+		if name == "publish" || name == "falcon 9" {
+			return "deploy:" + name
+		}
+
+		return name
+	}
+
+	// case 1: uses commands calling tasks or not.
+	for cmd, expected := range map[string]string{
+		"echo publish":        "echo publish",
+		"run@publish,lint":    "run@deploy:publish,lint",
+		"run@publish prod eu": "run@deploy:publish prod eu",
+		"run@falcon 9":        "run@deploy:falcon 9",
+		"run?true@publish":    "run?true@deploy:publish",
+	} {
+		if resolved := namespaceCall(cmd, resolve); resolved != expected {
+			t.Errorf("Command %s should have been resolved to %s, got %s!", cmd, expected, resolved)
+		}
+	}
+
+	// case 2: uses a conditional call from an included task.
+	condition := "true"
+	tasks := []*orbitTask{{Use: "deploy:ship", Run: orbitCommands{{Command: "run@publish", When: &condition}}}}
+	namespaceReferences(tasks, "deploy", []*orbitTask{{Use: "deploy:publish"}})
+	if tasks[0].Run[0].Command != "run@deploy:publish" || tasks[0].Run[0].When != &condition {
+		t.Errorf("Conditional call should have been resolved, got %v!", tasks[0].Run[0])
+	}
+}
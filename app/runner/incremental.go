@@ -0,0 +1,130 @@
+package runner
+
+import (
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	OrbitError "github.com/gulien/orbit/app/error"
+	"github.com/gulien/orbit/app/logger"
+)
+
+// last successful runs file name, inside the cache directory.
+const lastSuccessesFileName = "last_successes.json"
+
+// orbitLastSuccesses contains the times of the last successful runs of the tasks.
+type orbitLastSuccesses struct {
+	// Times contains the time of the last successful run of each task, in nanoseconds since the epoch.
+	Times map[string]int64 `json:"times"`
+}
+
+// lastSuccessesFilePath returns the path of the last successful runs file.
+func (r *OrbitRunner) lastSuccessesFilePath() string {
+	return filepath.Join(r.context.BaseDir(), cacheDirName, lastSuccessesFileName)
+}
+
+// loadLastSuccesses reads the last successful runs file. If it does not exist, returns empty last successful runs.
+func (r *OrbitRunner) loadLastSuccesses() (*orbitLastSuccesses, error) {
+	successes := &orbitLastSuccesses{Times: make(map[string]int64)}
+
+	filePath := r.lastSuccessesFilePath()
+	data, err := ioutil.ReadFile(filePath)
+	if os.IsNotExist(err) {
+		return successes, nil
+	}
+
+	if err != nil {
+		return nil, OrbitError.NewOrbitErrorf("unable to read the last successful runs file %s. Details:\n%s", filePath, err)
+	}
+
+	if err := json.Unmarshal(data, successes); err != nil {
+		return nil, OrbitError.NewOrbitErrorf("last successful runs file %s is not a valid JSON file. Details:\n%s", filePath, err)
+	}
+
+	if successes.Times == nil {
+		successes.Times = make(map[string]int64)
+	}
+
+	return successes, nil
+}
+
+/*
+hasChanged returns true if the given task should be run: if it has no run_if_changed attribute,
+if Force is true, if it has never succeeded or if one of its files has been modified
+since its last successful run.
+
+The files are matched against the glob patterns of the run_if_changed attribute,
+relative to the configuration file.
+*/
+func (r *OrbitRunner) hasChanged(task *orbitTask) (bool, error) {
+	if len(task.RunIfChanged) == 0 || r.Force {
+		return true, nil
+	}
+
+	successes, err := r.loadLastSuccesses()
+	if err != nil {
+		return false, err
+	}
+
+	last, ok := successes.Times[task.Use]
+	if !ok {
+		return true, nil
+	}
+
+	since := time.Unix(0, last)
+	changed := false
+
+	err = r.walkSources(task.RunIfChanged, func(rel string, info os.FileInfo) error {
+		if info.ModTime().After(since) {
+			logger.Debugf("file %s of task %s has changed since its last successful run", rel, task.Use)
+			changed = true
+			return errFound
+		}
+
+		return nil
+	})
+
+	if err != nil && err != errFound {
+		return false, OrbitError.NewOrbitErrorf("unable to find the changed files of task %s. Details:\n%s", task.Use, err)
+	}
+
+	return changed, nil
+}
+
+// errFound stops the walk through the files once a changed file has been found.
+var errFound = errors.New("found")
+
+/*
+recordSuccess writes the given start time of the last successful run of the given task
+into the last successful runs file, if the task has a run_if_changed attribute.
+
+The start time is recorded, so that a file modified while the task was running is considered as changed.
+*/
+func (r *OrbitRunner) recordSuccess(task *orbitTask, start time.Time) {
+	if len(task.RunIfChanged) == 0 {
+		return
+	}
+
+	successes, err := r.loadLastSuccesses()
+	if err != nil {
+		logger.Warnf("unable to record the successful run of task %s: %s", task.Use, err)
+		return
+	}
+
+	successes.Times[task.Use] = start.UnixNano()
+
+	data, err := json.MarshalIndent(successes, "", "  ")
+	if err == nil {
+		filePath := r.lastSuccessesFilePath()
+		if err = os.MkdirAll(filepath.Dir(filePath), 0755); err == nil {
+			err = ioutil.WriteFile(filePath, data, 0644)
+		}
+	}
+
+	if err != nil {
+		logger.Warnf("unable to record the successful run of task %s: %s", task.Use, err)
+	}
+}
@@ -0,0 +1,55 @@
+package runner
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/gulien/orbit/app/context"
+)
+
+// Tests if a task having a run_if_changed attribute is only run when its files have changed.
+func TestHasChanged(t *testing.T) {
+	templateFilePath, _ := filepath.Abs("../../_tests/orbit-run-if-changed.yml")
+	ctx, _ := context.NewOrbitContext(templateFilePath, "", "")
+	r, _ := NewOrbitRunner(ctx)
+	task := r.getTask("explorer")
+
+	dirPath, _ := filepath.Abs("../../_tests/run-if-changed/launchers")
+	os.MkdirAll(dirPath, 0755)
+	filePath := filepath.Join(dirPath, "falcon.txt")
+	ioutil.WriteFile(filePath, []byte("Falcon 9"), 0644)
+
+	defer os.RemoveAll(filepath.Dir(dirPath))
+	defer os.RemoveAll(filepath.Dir(r.lastSuccessesFilePath()))
+
+	// case 1: uses a task which has never succeeded.
+	if changed, err := r.hasChanged(task); err != nil || !changed {
+		t.Error("Task which has never succeeded should have been run!")
+	}
+
+	// case 2: uses a task whose files have not changed since its last successful run.
+	if err := r.Run("explorer"); err != nil {
+		t.Error("Task should have been run!")
+	}
+
+	if changed, err := r.hasChanged(task); err != nil || changed {
+		t.Error("Task whose files have not changed should not have been run!")
+	}
+
+	// case 3: uses force.
+	r.Force = true
+	if changed, _ := r.hasChanged(task); !changed {
+		t.Error("Task should have been forced!")
+	}
+
+	// case 4: uses a task whose files have changed since its last successful run.
+	r.Force = false
+	future := time.Now().Add(time.Hour)
+	os.Chtimes(filePath, future, future)
+	if changed, err := r.hasChanged(task); err != nil || !changed {
+		t.Error("Task whose files have changed should have been run!")
+	}
+}
@@ -0,0 +1,38 @@
+package runner
+
+import (
+	"regexp"
+	"strings"
+)
+
+// interpolationRegexp matches the escaped delimiter @@{ and the references @{NAME} to the runtime variables.
+var interpolationRegexp = regexp.MustCompile(`@@\{|@\{([A-Za-z_][A-Za-z0-9_]*)\}`)
+
+/*
+interpolate replaces in the given command each reference @{NAME} by the value of the variable NAME
+from the given environment, and each @@{ by @{.
+
+Unlike the template functions, which are executed when the configuration file is read,
+the references are replaced just before executing the command, whatever the shell.
+A reference to a variable which is not defined is kept as-is (e.g. git rev-parse @{u}).
+*/
+func interpolate(cmd string, environ []string) string {
+	if !strings.Contains(cmd, "@{") {
+		return cmd
+	}
+
+	return interpolationRegexp.ReplaceAllStringFunc(cmd, func(match string) string {
+		if match == "@@{" {
+			return "@{"
+		}
+
+		name := match[2 : len(match)-1]
+		for index := len(environ) - 1; index >= 0; index-- {
+			if strings.HasPrefix(environ[index], name+"=") {
+				return strings.TrimPrefix(environ[index], name+"=")
+			}
+		}
+
+		return match
+	})
+}
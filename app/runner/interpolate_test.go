@@ -0,0 +1,23 @@
+package runner
+
+import "testing"
+
+// Tests if the references to the runtime variables are replaced.
+func TestInterpolate(t *testing.T) {
+	environ := []string{"LAUNCHER=Soyuz", "ORBIT_TASK=vostok", "LAUNCHER=Falcon 9"}
+
+	// case 1: uses references to defined variables.
+	if cmd := interpolate("echo @{LAUNCHER} from @{ORBIT_TASK}", environ); cmd != "echo Falcon 9 from vostok" {
+		t.Errorf("References should have been replaced, got %s!", cmd)
+	}
+
+	// case 2: uses an escaped reference.
+	if cmd := interpolate("echo @@{LAUNCHER}", environ); cmd != "echo @{LAUNCHER}" {
+		t.Errorf("Escaped reference should have been kept, got %s!", cmd)
+	}
+
+	// case 3: uses a reference to a variable which is not defined.
+	if cmd := interpolate("git rev-parse @{u}", environ); cmd != "git rev-parse @{u}" {
+		t.Errorf("Reference to a variable which is not defined should have been kept, got %s!", cmd)
+	}
+}
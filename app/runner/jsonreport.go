@@ -0,0 +1,171 @@
+package runner
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os/exec"
+	"sync"
+	"time"
+
+	OrbitError "github.com/gulien/orbit/app/error"
+	"github.com/gulien/orbit/app/logger"
+)
+
+// JSONReport is the JSON report format.
+const JSONReport = "json"
+
+// stderrTailSize is the maximum number of bytes of the standard error of a command kept in a JSON report.
+const stderrTailSize = 4096
+
+type (
+	// orbitCommandResult represents a command which has been executed, as written in a JSON report.
+	orbitCommandResult struct {
+		// Task is the name of the task running the command.
+		Task string `json:"task"`
+
+		// Command is the command as defined in the configuration file.
+		Command string `json:"command"`
+
+		// Start is the time at which the command has started.
+		Start time.Time `json:"start"`
+
+		// End is the time at which the command has ended.
+		End time.Time `json:"end"`
+
+		// ExitCode is the exit code of the command, or -1 if it has not been started.
+		ExitCode int `json:"exit_code"`
+
+		// StderrTail contains the last bytes of the standard error of the command.
+		StderrTail string `json:"stderr_tail"`
+	}
+
+	// orbitTaskReport represents a task which has been run, as written in a JSON report.
+	orbitTaskReport struct {
+		// Use is the name of the task.
+		Use string `json:"use"`
+
+		// Start is the time at which the task has started.
+		Start time.Time `json:"start"`
+
+		// End is the time at which the task has ended, including the tasks it calls.
+		End time.Time `json:"end"`
+
+		// Error is the error of the task, empty if it has succeeded.
+		Error string `json:"error,omitempty"`
+	}
+
+	// orbitJSONReport is the root object of a JSON report.
+	orbitJSONReport struct {
+		// Tasks contains the tasks which have been run, in the order they have ended.
+		Tasks []*orbitTaskReport `json:"tasks"`
+
+		// Commands contains the commands which have been executed, in the order they have ended.
+		Commands []*orbitCommandResult `json:"commands"`
+	}
+
+	// tailWriter keeps the last bytes written to it.
+	tailWriter struct {
+		// size is the maximum number of kept bytes.
+		size int
+
+		// data contains the kept bytes.
+		data []byte
+
+		// mutex protects data from the concurrent writes of a command.
+		mutex sync.Mutex
+	}
+)
+
+// Write keeps the last bytes of the given data.
+func (w *tailWriter) Write(p []byte) (int, error) {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	w.data = append(w.data, p...)
+	if len(w.data) > w.size {
+		w.data = w.data[len(w.data)-w.size:]
+	}
+
+	return len(p), nil
+}
+
+// String returns the kept bytes.
+func (w *tailWriter) String() string {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	return string(w.data)
+}
+
+// exitCode returns the exit code of a command from the error of its execution: 0 if it has succeeded,
+// -1 if it has not been started or if it has been killed by a signal.
+func exitCode(err error) int {
+	if err == nil {
+		return 0
+	}
+
+	if exitErr, ok := err.(*exec.ExitError); ok {
+		return exitStatus(exitErr.ProcessState)
+	}
+
+	return -1
+}
+
+// recordCommand keeps track of a command which has been executed, if RecordCommands is true.
+func (r *OrbitRunner) recordCommand(task *orbitTask, cmd string, start time.Time, elapsed time.Duration, err error, stderr *tailWriter) {
+	if !r.RecordCommands {
+		return
+	}
+
+	result := &orbitCommandResult{
+		Task:     task.Use,
+		Command:  cmd,
+		Start:    start,
+		End:      start.Add(elapsed),
+		ExitCode: exitCode(err),
+	}
+
+	if stderr != nil {
+		result.StderrTail = stderr.String()
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	r.commands = append(r.commands, result)
+}
+
+/*
+WriteJSONReport writes the tasks which have been run and their commands into the given file, as a JSON report.
+
+The commands are only listed if RecordCommands was true when running the tasks: each of them
+comes with its start and end times, its exit code and the tail of its standard error.
+*/
+func (r *OrbitRunner) WriteJSONReport(filePath string) error {
+	report := orbitJSONReport{Tasks: []*orbitTaskReport{}, Commands: r.commands}
+	if report.Commands == nil {
+		report.Commands = []*orbitCommandResult{}
+	}
+
+	for _, result := range r.results {
+		task := &orbitTaskReport{Use: result.task.Use, Start: result.start, End: result.start.Add(result.duration)}
+		if result.err != nil {
+			task.Error = result.err.Error()
+		}
+
+		report.Tasks = append(report.Tasks, task)
+	}
+
+	data, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return OrbitError.NewOrbitErrorf("unable to encode the JSON report. Details:\n%s", err)
+	}
+
+	if err := ioutil.WriteFile(filePath, append(data, '\n'), 0644); err != nil {
+		return OrbitError.NewOrbitErrorf("unable to write the JSON report file %s. Details:\n%s", filePath, err)
+	}
+
+	logger.Infof("JSON report file %s has been created", filePath)
+
+	return nil
+}
@@ -0,0 +1,99 @@
+package runner
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/gulien/orbit/app/context"
+)
+
+// Tests if the JSON report contains the tasks which have been run and their commands.
+func TestWriteJSONReport(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("skipping test on Windows")
+	}
+
+	templateFilePath, _ := filepath.Abs("../../_tests/orbit.yml")
+	ctx, _ := context.NewOrbitContext(templateFilePath, "", "")
+	r, _ := NewOrbitRunner(ctx)
+	r.RecordCommands = true
+
+	r.Run("explorer")
+	r.Run("challenger")
+
+	filePath := filepath.Join(os.TempDir(), "orbit-report.json")
+	defer os.Remove(filePath)
+
+	if err := r.WriteJSONReport(filePath); err != nil {
+		t.Error("JSON report should have been written!")
+	}
+
+	data, _ := ioutil.ReadFile(filePath)
+
+	var report orbitJSONReport
+	if err := json.Unmarshal(data, &report); err != nil {
+		t.Fatalf("JSON report should have been a valid JSON file, got %s!", data)
+	}
+
+	// case 1: uses the tasks which have been run.
+	if len(report.Tasks) != 2 || report.Tasks[0].Error != "" || report.Tasks[1].Error == "" {
+		t.Errorf("JSON report should have contained a succeeding and a failed task, got %s!", data)
+	}
+
+	// case 2: uses the commands which have been executed.
+	if len(report.Commands) != 2 || report.Commands[0].ExitCode != 0 || report.Commands[1].ExitCode == 0 {
+		t.Fatalf("JSON report should have contained a succeeding and a failed command, got %s!", data)
+	}
+
+	if !strings.Contains(report.Commands[1].StderrTail, "failecho") {
+		t.Errorf("JSON report should have contained the tail of the standard error of the failed command, got %q!", report.Commands[1].StderrTail)
+	}
+
+	if report.Commands[0].End.Before(report.Commands[0].Start) {
+		t.Error("JSON report should have contained the start and end times of the commands!")
+	}
+}
+
+// Tests if the tail writer keeps the last bytes written to it.
+func TestTailWriter(t *testing.T) {
+	w := &tailWriter{size: 4}
+	w.Write([]byte("I am "))
+	w.Write([]byte("explorer"))
+
+	if w.String() != "orer" {
+		t.Errorf("Tail writer should have kept the last bytes, got %q!", w.String())
+	}
+}
+
+// Tests if the exit codes of the commands are retrieved from the errors of their executions.
+func TestExitCode(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("skipping test on Windows")
+	}
+
+	// case 1: uses a command which has succeeded.
+	if code := exitCode(exec.Command("sh", "-c", "exit 0").Run()); code != 0 {
+		t.Errorf("Exit code should have been 0, got %d!", code)
+	}
+
+	// case 2: uses a command which has failed.
+	if code := exitCode(exec.Command("sh", "-c", "exit 3").Run()); code != 3 {
+		t.Errorf("Exit code should have been 3, got %d!", code)
+	}
+
+	// case 3: uses a command killed by a signal.
+	if code := exitCode(exec.Command("sh", "-c", "kill -9 $$").Run()); code != -1 {
+		t.Errorf("Exit code should have been -1, got %d!", code)
+	}
+
+	// case 4: uses a command which has not been started.
+	if code := exitCode(exec.Command("nosuchcommand").Run()); code != -1 {
+		t.Errorf("Exit code should have been -1, got %d!", code)
+	}
+}
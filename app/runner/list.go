@@ -0,0 +1,183 @@
+package runner
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"text/tabwriter"
+
+	OrbitError "github.com/gulien/orbit/app/error"
+
+	"gopkg.in/yaml.v2"
+)
+
+const (
+	// TableFormat prints the tasks in an aligned table with the configuration file.
+	TableFormat = "table"
+
+	// PlainFormat prints one task per line, with its short description after a tab.
+	PlainFormat = "plain"
+
+	// CSVFormat prints the tasks as CSV with the columns use, short and private.
+	CSVFormat = "csv"
+
+	// JSONFormat prints the tasks as a JSON array of objects with the fields use, short, private, shell and args.
+	JSONFormat = "json"
+
+	// YAMLFormat prints the tasks as a YAML list with the same fields as JSONFormat.
+	YAMLFormat = "yaml"
+)
+
+// orbitJSONTask represents a task printed with the JSON or the YAML format.
+type orbitJSONTask struct {
+	// Use is the name of the task.
+	Use string `json:"use" yaml:"use"`
+
+	// Short is the short description of the task.
+	Short string `json:"short" yaml:"short"`
+
+	// Private is true if the task is private.
+	Private bool `json:"private" yaml:"private"`
+
+	// Shell is the binary called to run the commands, empty for the default shell.
+	Shell string `json:"shell,omitempty" yaml:"shell,omitempty"`
+
+	// Args contains the names of the arguments declared by the task.
+	Args []string `json:"args,omitempty" yaml:"args,omitempty"`
+}
+
+// printTasks prints the available tasks to the given writer, according to Format.
+func (r *OrbitRunner) printTasks(out io.Writer) error {
+	tasks, err := r.listTasks()
+	if err != nil {
+		return err
+	}
+
+	// the JSON and YAML formats are for tooling: the tasks are never collapsed into their namespaces.
+	switch r.Format {
+	case JSONFormat:
+		return printJSON(out, tasks)
+	case YAMLFormat:
+		return printYAML(out, tasks)
+	}
+
+	entries := groupTasks(tasks, r.Depth)
+
+	switch r.Format {
+	case "", TableFormat:
+		return r.printTable(out, entries)
+	case PlainFormat:
+		return r.printPlain(out, entries)
+	case CSVFormat:
+		return printCSV(out, entries)
+	default:
+		return OrbitError.NewOrbitErrorf("unknown format %s, expected %s, %s, %s, %s or %s", r.Format, TableFormat, PlainFormat, CSVFormat, JSONFormat, YAMLFormat)
+	}
+}
+
+// printTable prints the given entries in an aligned table with the configuration file.
+// If ShowCommands is true, the commands of each task follow it, indented.
+func (r *OrbitRunner) printTable(out io.Writer, entries []*orbitListEntry) error {
+	w := tabwriter.NewWriter(out, 0, 0, 1, ' ', tabwriter.TabIndent)
+
+	fmt.Fprint(w, "Configuration file:")
+	fmt.Fprintf(w, "\n  %s\t\n", r.context.TemplateFilePath)
+	fmt.Fprint(w, "\nAvailable tasks:")
+
+	for _, entry := range entries {
+		fmt.Fprintf(w, "\n  %s\t%s", entry.name, entry.description())
+
+		if r.ShowCommands {
+			for _, cmd := range entry.commands {
+				fmt.Fprintf(w, "\n      %s", cmd)
+			}
+		}
+	}
+
+	// clears the writer as it may contain some weird characters.
+	fmt.Fprintln(w, "")
+
+	return w.Flush()
+}
+
+// printPlain prints the given entries, one per line, with their descriptions after a tab.
+// If ShowCommands is true, the commands of each task follow it, indented.
+func (r *OrbitRunner) printPlain(out io.Writer, entries []*orbitListEntry) error {
+	for _, entry := range entries {
+		if _, err := fmt.Fprintf(out, "%s\t%s\n", entry.name, entry.description()); err != nil {
+			return err
+		}
+
+		if !r.ShowCommands {
+			continue
+		}
+
+		for _, cmd := range entry.commands {
+			if _, err := fmt.Fprintf(out, "    %s\n", cmd); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// printCSV prints the given entries as CSV with a header.
+func printCSV(out io.Writer, entries []*orbitListEntry) error {
+	w := csv.NewWriter(out)
+	w.Write([]string{"use", "short", "private"})
+
+	for _, entry := range entries {
+		// only the tasks which are not private are listed.
+		w.Write([]string{entry.name, entry.description(), strconv.FormatBool(false)})
+	}
+
+	w.Flush()
+
+	return w.Error()
+}
+
+// printJSON prints the given tasks as an indented JSON array, which is empty if there are no tasks.
+func printJSON(out io.Writer, tasks []*orbitTask) error {
+	data, err := json.MarshalIndent(toJSONTasks(tasks), "", "  ")
+	if err != nil {
+		return OrbitError.NewOrbitErrorf("unable to encode the tasks to JSON. Details:\n%s", err)
+	}
+
+	_, err = fmt.Fprintf(out, "%s\n", data)
+
+	return err
+}
+
+// printYAML prints the given tasks as a YAML list, which is empty if there are no tasks.
+func printYAML(out io.Writer, tasks []*orbitTask) error {
+	data, err := yaml.Marshal(toJSONTasks(tasks))
+	if err != nil {
+		return OrbitError.NewOrbitErrorf("unable to encode the tasks to YAML. Details:\n%s", err)
+	}
+
+	_, err = out.Write(data)
+
+	return err
+}
+
+// toJSONTasks returns the given tasks as printed with the JSON or the YAML format.
+func toJSONTasks(tasks []*orbitTask) []*orbitJSONTask {
+	list := make([]*orbitJSONTask, len(tasks))
+	for index, task := range tasks {
+		list[index] = &orbitJSONTask{Use: task.Use, Short: task.Short, Private: task.Private, Shell: task.Shell, Args: task.Args}
+	}
+
+	return list
+}
+
+// description returns the short description of a task or the number of tasks of a namespace.
+func (entry *orbitListEntry) description() string {
+	if entry.count == 0 {
+		return entry.short
+	}
+
+	return fmt.Sprintf("(%d tasks)", entry.count)
+}
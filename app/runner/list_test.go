@@ -0,0 +1,79 @@
+package runner
+
+import (
+	"bytes"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/gulien/orbit/app/context"
+)
+
+// Tests if the available tasks are printed according to the format.
+func TestPrintTasks(t *testing.T) {
+	templateFilePath, _ := filepath.Abs("../../_tests/orbit.yml")
+	ctx, _ := context.NewOrbitContext(templateFilePath, "", "")
+	r, _ := NewOrbitRunner(ctx)
+
+	// case 1: uses the plain format.
+	var buf bytes.Buffer
+	r.Format = PlainFormat
+	if err := r.printTasks(&buf); err != nil || !strings.HasPrefix(buf.String(), "explorer\ta short description\nchallenger\t\n") {
+		t.Errorf("Tasks should have been printed in plain format, got %q!", buf.String())
+	}
+
+	// case 2: uses the CSV format.
+	buf.Reset()
+	r.Format = CSVFormat
+	if err := r.printTasks(&buf); err != nil || !strings.HasPrefix(buf.String(), "use,short,private\nexplorer,a short description,false\n") {
+		t.Errorf("Tasks should have been printed in CSV format, got %q!", buf.String())
+	}
+
+	// case 3: uses the CSV format with a comma in a field.
+	buf.Reset()
+	printCSV(&buf, []*orbitListEntry{{name: "explorer", short: "a short, description"}})
+	if !strings.Contains(buf.String(), `explorer,"a short, description",false`) {
+		t.Errorf("Field with a comma should have been quoted, got %q!", buf.String())
+	}
+
+	// case 4: uses the plain format with the commands.
+	buf.Reset()
+	r.Format = PlainFormat
+	r.ShowCommands = true
+	if err := r.printTasks(&buf); err != nil || !strings.HasPrefix(buf.String(), "explorer\ta short description\n    echo \"I am explorer task\"\nchallenger\t\n") {
+		t.Errorf("Tasks should have been printed with their commands, got %q!", buf.String())
+	}
+
+	// case 5: uses the table format with the commands.
+	buf.Reset()
+	r.Format = TableFormat
+	if err := r.printTasks(&buf); err != nil || !strings.Contains(buf.String(), "\n      echo \"I am explorer task\"\n") {
+		t.Errorf("Tasks should have been printed in a table with their commands, got %q!", buf.String())
+	}
+
+	// case 6: uses the JSON format.
+	buf.Reset()
+	r.Format = JSONFormat
+	if err := r.printTasks(&buf); err != nil || !strings.HasPrefix(buf.String(), "[\n  {\n    \"use\": \"explorer\",\n    \"short\": \"a short description\",\n    \"private\": false\n  },") {
+		t.Errorf("Tasks should have been printed in JSON format, got %q!", buf.String())
+	}
+
+	// case 7: uses the JSON format without tasks.
+	buf.Reset()
+	if err := printJSON(&buf, nil); err != nil || buf.String() != "[]\n" {
+		t.Errorf("Empty JSON array should have been printed, got %q!", buf.String())
+	}
+
+	// case 8: uses the YAML format.
+	buf.Reset()
+	r.Format = YAMLFormat
+	if err := r.printTasks(&buf); err != nil || !strings.HasPrefix(buf.String(), "- use: explorer\n  short: a short description\n  private: false\n- use: ") {
+		t.Errorf("Tasks should have been printed in YAML format, got %q!", buf.String())
+	}
+
+	// case 9: uses an unknown format.
+	r.Format = "xml"
+	if err := r.printTasks(&buf); err == nil {
+		t.Error("Tasks should not have been printed with an unknown format!")
+	}
+}
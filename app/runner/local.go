@@ -0,0 +1,129 @@
+package runner
+
+import (
+	"path/filepath"
+	"strings"
+
+	"github.com/gulien/orbit/app/context"
+	OrbitError "github.com/gulien/orbit/app/error"
+	"github.com/gulien/orbit/app/generator"
+	"github.com/gulien/orbit/app/helpers"
+	"github.com/gulien/orbit/app/logger"
+
+	"gopkg.in/yaml.v2"
+)
+
+// localConfigSuffix is inserted before the extension of the configuration file to get the local configuration file.
+const localConfigSuffix = ".local"
+
+// localConfigFilePath returns the path of the local configuration file (e.g. orbit.local.yml for orbit.yml),
+// next to the configuration file of the given context or in the current directory if it's a remote file.
+func localConfigFilePath(ctx *context.OrbitContext) string {
+	name := filepath.Base(ctx.TemplateFilePath)
+	ext := filepath.Ext(name)
+
+	return filepath.Join(ctx.BaseDir(), strings.TrimSuffix(name, ext)+localConfigSuffix+ext)
+}
+
+/*
+loadLocalConfig reads the local configuration file next to the configuration file, if it exists,
+and merges it on top of the given configuration.
+
+The local configuration file is a data-driven template executed with the same payload
+and additional templates. Its tasks override the tasks having the same name, as if they were
+extending them, and the others tasks are added.
+*/
+func loadLocalConfig(config *orbitRunnerConfig, ctx *context.OrbitContext) error {
+	filePath := localConfigFilePath(ctx)
+	if ctx.Settings.SkipLocalConfig || !helpers.FileExists(filePath) {
+		return nil
+	}
+
+	logger.Infof("merging local configuration file %s", filePath)
+
+	localContext := *ctx
+	localContext.TemplateFilePath = filePath
+	localContext.TemplateURL = ""
+
+	data, err := generator.NewOrbitGenerator(&localContext).Execute()
+	if err != nil {
+		return err
+	}
+
+	var local = &orbitRunnerConfig{}
+	if err := yaml.Unmarshal(data.Bytes(), &local); err != nil {
+		return OrbitError.NewOrbitErrorf("local configuration file %s is not a valid YAML file. Details:\n%s", filePath, err)
+	}
+
+	resolvePlatforms(local)
+
+	if problems := resolveRunFiles(local, localContext.BaseDir()); len(problems) > 0 {
+		return problems[0]
+	}
+
+	if err := mergeConfig(config, local); err != nil {
+		return OrbitError.NewOrbitErrorf("local configuration file %s has invalid tasks. Details:\n%s", filePath, err)
+	}
+
+	return nil
+}
+
+// mergeConfig merges the given local configuration on top of the given configuration.
+func mergeConfig(config *orbitRunnerConfig, local *orbitRunnerConfig) error {
+	for _, task := range local.Tasks {
+		index := -1
+		for i, base := range config.Tasks {
+			if base.Use == task.Use {
+				index = i
+				break
+			}
+		}
+
+		if index == -1 {
+			config.Tasks = append(config.Tasks, task)
+			continue
+		}
+
+		base := config.Tasks[index]
+		if err := mergeTask(task, base); err != nil {
+			return err
+		}
+
+		// unlike an extending task, an overriding task keeps the visibility and the base of the overridden task.
+		task.Private = task.Private || base.Private
+		if task.Extends == "" {
+			task.Extends = base.Extends
+		}
+
+		config.Tasks[index] = task
+	}
+
+	for name, group := range local.Groups {
+		if config.Groups == nil {
+			config.Groups = make(map[string][]string)
+		}
+
+		config.Groups[name] = group
+	}
+
+	for key, value := range local.Env {
+		if config.Env == nil {
+			config.Env = make(map[string]string)
+		}
+
+		config.Env[key] = value
+	}
+
+	if local.Default != nil {
+		config.Default = local.Default
+	}
+
+	if local.Notify != nil {
+		config.Notify = local.Notify
+	}
+
+	config.History = config.History || local.History
+	config.ConfirmDestructive = append(config.ConfirmDestructive, local.ConfirmDestructive...)
+
+	return nil
+}
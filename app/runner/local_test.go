@@ -0,0 +1,67 @@
+package runner
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/gulien/orbit/app/context"
+)
+
+// Tests if the local configuration file is merged on top of the configuration file.
+func TestLoadLocalConfig(t *testing.T) {
+	templateFilePath, _ := filepath.Abs("../../_tests/orbit-local.yml")
+	ctx, _ := context.NewOrbitContext(templateFilePath, "", "")
+
+	// case 1: uses a local configuration file.
+	r, err := NewOrbitRunner(ctx)
+	if err != nil {
+		t.Error("Local configuration file should have been merged!")
+	}
+
+	explorer := r.getTask("explorer")
+	if explorer.Shell != "bash -c" || explorer.Short != "a short description" {
+		t.Error("Task explorer should have kept its attributes!")
+	}
+
+	if !reflect.DeepEqual(explorer.Run, plainCommands([]string{`echo "I am explorer task"`, `echo "I am a local explorer command"`})) {
+		t.Errorf("Task explorer should have appended the local commands, got %v!", explorer.Run)
+	}
+
+	sputnik := r.getTask("sputnik")
+	if !sputnik.Private || !reflect.DeepEqual(sputnik.Run, plainCommands([]string{`echo "I am a local sputnik command"`})) {
+		t.Errorf("Task sputnik should have been overridden while staying private, got %v!", sputnik.Run)
+	}
+
+	if isEnabled(sputnik.ContinueOnError) {
+		t.Error("Task sputnik should have overridden its boolean attribute with false!")
+	}
+
+	if r.getTask("vostok") == nil {
+		t.Error("Task vostok should have been added!")
+	}
+
+	// case 2: skips the local configuration file.
+	ctx.Settings.SkipLocalConfig = true
+
+	r, _ = NewOrbitRunner(ctx)
+	if r.getTask("vostok") != nil || len(r.getTask("explorer").Run) != 1 {
+		t.Error("Local configuration file should have been skipped!")
+	}
+}
+
+// Tests if the local configuration file is next to the configuration file, or in the current directory if it's a remote file.
+func TestLocalConfigFilePath(t *testing.T) {
+	// case 1: uses a local configuration file.
+	ctx := &context.OrbitContext{TemplateFilePath: filepath.Join("project", "orbit.yml")}
+	if filePath := localConfigFilePath(ctx); filePath != filepath.Join("project", "orbit.local.yml") {
+		t.Errorf("Local configuration file should have been next to the configuration file, got %s!", filePath)
+	}
+
+	// case 2: uses a remote configuration file.
+	ctx = &context.OrbitContext{TemplateFilePath: filepath.Join("cache", "orbit.yml"), TemplateURL: "https://example.com/orbit.yml"}
+	if dir, _ := os.Getwd(); localConfigFilePath(ctx) != filepath.Join(dir, "orbit.local.yml") {
+		t.Errorf("Local configuration file of a remote configuration file should have been in the current directory, got %s!", localConfigFilePath(ctx))
+	}
+}
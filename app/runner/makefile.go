@@ -0,0 +1,79 @@
+package runner
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"strings"
+
+	OrbitError "github.com/gulien/orbit/app/error"
+)
+
+// makefileTargetRegexp matches the characters which are not allowed in a Makefile target.
+var makefileTargetRegexp = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+
+// makefileFlagRegexp matches a flag of the Orbit command, which does not have to be quoted.
+var makefileFlagRegexp = regexp.MustCompile(`^--?[A-Za-z][A-Za-z-]*$`)
+
+/*
+Makefile returns a Makefile with a target for each task which is not private.
+
+Each target runs its task with "orbit run" followed by the given options (e.g. "-f", "my-orbit.yml"),
+whose values are quoted. The short descriptions of the tasks become comments.
+If several tasks have the same target once their names are sanitized, returns an error.
+*/
+func (r *OrbitRunner) Makefile(options []string) (bytes.Buffer, error) {
+	var data bytes.Buffer
+
+	tasks, err := r.listTasks()
+	if err != nil {
+		return data, err
+	}
+
+	targets := make([]string, len(tasks))
+	names := make(map[string]string, len(tasks))
+	for index, task := range tasks {
+		targets[index] = makefileTarget(task.Use)
+		if name, ok := names[targets[index]]; ok {
+			return data, OrbitError.NewOrbitErrorf("unable to generate the Makefile: tasks %s and %s have the same target %s", name, task.Use, targets[index])
+		}
+
+		names[targets[index]] = task.Use
+	}
+
+	command := "orbit run"
+	for _, option := range options {
+		if !makefileFlagRegexp.MatchString(option) {
+			option = makefileQuote(option)
+		}
+
+		command += " " + option
+	}
+
+	fmt.Fprintf(&data, "# Generated by Orbit from %s.\n\n", r.context.TemplateFilePath)
+	fmt.Fprintf(&data, ".PHONY: %s\n", strings.Join(targets, " "))
+
+	for index, task := range tasks {
+		data.WriteString("\n")
+		if task.Short != "" {
+			fmt.Fprintf(&data, "# %s\n", task.Short)
+		}
+
+		fmt.Fprintf(&data, "%s:\n\t%s %s\n", targets[index], command, makefileQuote(task.Use))
+	}
+
+	return data, nil
+}
+
+// makefileTarget returns the given task name with the characters not allowed in a Makefile target replaced by dashes.
+func makefileTarget(name string) string {
+	return makefileTargetRegexp.ReplaceAllString(name, "-")
+}
+
+// makefileQuote returns the given argument quoted for the shell of a Makefile recipe.
+func makefileQuote(arg string) string {
+	arg = strings.Replace(arg, "'", `'\''`, -1)
+	arg = strings.Replace(arg, "$", "$$", -1)
+
+	return "'" + arg + "'"
+}
@@ -0,0 +1,53 @@
+package runner
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/gulien/orbit/app/context"
+)
+
+// Tests if the Makefile contains a target for each task which is not private.
+func TestMakefile(t *testing.T) {
+	templateFilePath, _ := filepath.Abs("../../_tests/orbit.yml")
+	ctx, _ := context.NewOrbitContext(templateFilePath, "", "")
+	r, _ := NewOrbitRunner(ctx)
+
+	data, err := r.Makefile(nil)
+	if err != nil {
+		t.Error("Makefile should have been generated!")
+	}
+
+	makefile := data.String()
+
+	// case 1: uses a task with a short description.
+	if !strings.Contains(makefile, "\n# a short description\nexplorer:\n\torbit run 'explorer'\n") {
+		t.Errorf("Makefile should have contained the target explorer, got %s!", makefile)
+	}
+
+	// case 2: uses a task with a space.
+	if !strings.Contains(makefile, "\nnew-shepard:\n\torbit run 'new shepard'\n") {
+		t.Errorf("Makefile should have contained the target new-shepard, got %s!", makefile)
+	}
+
+	// case 3: uses a private task.
+	if strings.Contains(makefile, "sputnik") {
+		t.Error("Makefile should not have contained the private task sputnik!")
+	}
+
+	// case 4: uses options with special characters.
+	data, _ = r.Makefile([]string{"-f", "my dir/orbit $HOME.yml", "-p", "key,it's.yml"})
+	if !strings.Contains(data.String(), "\torbit run -f 'my dir/orbit $$HOME.yml' -p 'key,it'\\''s.yml' 'explorer'\n") {
+		t.Errorf("Makefile should have contained the quoted options, got %s!", data.String())
+	}
+
+	// case 5: uses tasks having the same target.
+	templateFilePath, _ = filepath.Abs("../../_tests/orbit-makefile.yml")
+	ctx, _ = context.NewOrbitContext(templateFilePath, "", "")
+	r, _ = NewOrbitRunner(ctx)
+
+	if _, err := r.Makefile(nil); err == nil || !strings.Contains(err.Error(), "same target db-migrate") {
+		t.Errorf("Makefile with tasks having the same target should not have been generated, got %v!", err)
+	}
+}
@@ -0,0 +1,79 @@
+package runner
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	OrbitError "github.com/gulien/orbit/app/error"
+	"github.com/gulien/orbit/app/logger"
+)
+
+// PushgatewayURLEnvVariable is the environment variable which may contain the URL of a Prometheus pushgateway.
+const PushgatewayURLEnvVariable = "ORBIT_PUSHGATEWAY_URL"
+
+// pushgatewayJob is the job name of the metrics pushed to the Prometheus pushgateway.
+const pushgatewayJob = "orbit"
+
+// pushgatewayClient is the HTTP client used to push the metrics.
+var pushgatewayClient = &http.Client{Timeout: 10 * time.Second}
+
+/*
+pushMetrics pushes the duration and the outcome of the given task to the Prometheus pushgateway,
+if a pushgateway URL is configured.
+
+The metrics are grouped by task, so that each task only replaces its own metrics.
+As the metrics are only a monitoring aid, failing to push them is not an error.
+*/
+func (r *OrbitRunner) pushMetrics(task *orbitTask, elapsed time.Duration, taskErr error) {
+	if r.PushgatewayURL == "" {
+		return
+	}
+
+	if err := pushTaskMetrics(r.PushgatewayURL, task.Use, elapsed, taskErr == nil); err != nil {
+		logger.Warnf("unable to push the metrics of task %s: %s", task.Use, err)
+	}
+}
+
+// pushTaskMetrics replaces the metrics of the given task in the given Prometheus pushgateway.
+func pushTaskMetrics(gatewayURL string, name string, elapsed time.Duration, success bool) error {
+	outcome, value := "failure", 0
+	if success {
+		outcome, value = "success", 1
+	}
+
+	var body bytes.Buffer
+	fmt.Fprintln(&body, "# TYPE orbit_task_duration_seconds gauge")
+	fmt.Fprintf(&body, "orbit_task_duration_seconds{outcome=%q} %f\n", outcome, elapsed.Seconds())
+	fmt.Fprintln(&body, "# TYPE orbit_task_success gauge")
+	fmt.Fprintf(&body, "orbit_task_success{outcome=%q} %d\n", outcome, value)
+	fmt.Fprintln(&body, "# TYPE orbit_task_last_run_timestamp_seconds gauge")
+	fmt.Fprintf(&body, "orbit_task_last_run_timestamp_seconds{outcome=%q} %d\n", outcome, time.Now().Unix())
+
+	// task names may contain characters which are not allowed in an URL path,
+	// so the task label is base64 encoded as supported by the pushgateway.
+	url := fmt.Sprintf("%s/metrics/job/%s/task@base64/%s", strings.TrimRight(gatewayURL, "/"), pushgatewayJob, base64.RawURLEncoding.EncodeToString([]byte(name)))
+
+	req, err := http.NewRequest(http.MethodPut, url, &body)
+	if err != nil {
+		return OrbitError.NewOrbitErrorf("unable to create the request to %s. Details:\n%s", url, err)
+	}
+
+	req.Header.Set("Content-Type", "text/plain; version=0.0.4")
+
+	resp, err := pushgatewayClient.Do(req)
+	if err != nil {
+		return OrbitError.NewOrbitErrorf("unable to push the metrics to %s. Details:\n%s", url, err)
+	}
+
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return OrbitError.NewOrbitErrorf("unable to push the metrics to %s: pushgateway responded with status %s", url, resp.Status)
+	}
+
+	return nil
+}
@@ -0,0 +1,54 @@
+package runner
+
+import (
+	"encoding/base64"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/gulien/orbit/app/context"
+)
+
+// Tests if the metrics of a task are pushed to the configured pushgateway.
+func TestPushMetrics(t *testing.T) {
+	pushes := make(map[string]string)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		body, _ := ioutil.ReadAll(req.Body)
+		pushes[req.Method+" "+req.URL.Path] = string(body)
+	}))
+	defer server.Close()
+
+	templateFilePath, _ := filepath.Abs("../../_tests/orbit.yml")
+	ctx, _ := context.NewOrbitContext(templateFilePath, "", "")
+	r, _ := NewOrbitRunner(ctx)
+
+	// case 1: uses no pushgateway.
+	r.Run("explorer")
+	if len(pushes) != 0 {
+		t.Error("Metrics should not have been pushed!")
+	}
+
+	// case 2: uses a succeeding task.
+	r.PushgatewayURL = server.URL
+	r.Run("explorer")
+	body := pushes["PUT /metrics/job/orbit/task@base64/"+base64.RawURLEncoding.EncodeToString([]byte("explorer"))]
+	if !strings.Contains(body, `orbit_task_success{outcome="success"} 1`) || !strings.Contains(body, "orbit_task_duration_seconds") {
+		t.Errorf("Metrics of a succeeding task should have been pushed, got %v!", pushes)
+	}
+
+	// case 3: uses a failing task.
+	r.Run("challenger")
+	body = pushes["PUT /metrics/job/orbit/task@base64/"+base64.RawURLEncoding.EncodeToString([]byte("challenger"))]
+	if !strings.Contains(body, `orbit_task_success{outcome="failure"} 0`) {
+		t.Errorf("Metrics of a failing task should have been pushed, got %v!", pushes)
+	}
+
+	// case 4: uses an unreachable pushgateway.
+	r.PushgatewayURL = "http://127.0.0.1:0"
+	if err := r.Run("explorer"); err != nil {
+		t.Error("Unreachable pushgateway should not have failed the task!")
+	}
+}
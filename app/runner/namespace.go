@@ -0,0 +1,67 @@
+package runner
+
+import "strings"
+
+// namespaceSeparator separates the namespaces of a task name (e.g. db:migrate).
+const namespaceSeparator = ":"
+
+// orbitListEntry represents either a task or a namespace when printing the tasks.
+type orbitListEntry struct {
+	// name is the name of the task or the namespace.
+	name string
+
+	// short is the short description of the task.
+	short string
+
+	// count is the number of tasks in the namespace, zero for a task.
+	count int
+
+	// commands is the stack of commands of the task.
+	commands []string
+}
+
+/*
+groupTasks collapses the given tasks into their namespaces at the given depth.
+
+A task whose name has no more namespace levels than the depth is kept as-is.
+Each namespace appears at the position of its first task. If depth is zero,
+all the tasks are kept as-is.
+*/
+func groupTasks(tasks []*orbitTask, depth int) []*orbitListEntry {
+	var entries []*orbitListEntry
+	namespaces := make(map[string]*orbitListEntry)
+
+	for _, task := range tasks {
+		namespace, ok := getNamespace(task.Use, depth)
+		if !ok {
+			entries = append(entries, &orbitListEntry{name: task.Use, short: task.Short, commands: displayCommands(task.Run)})
+			continue
+		}
+
+		entry, ok := namespaces[namespace]
+		if !ok {
+			entry = &orbitListEntry{name: namespace}
+			namespaces[namespace] = entry
+			entries = append(entries, entry)
+		}
+
+		entry.count++
+	}
+
+	return entries
+}
+
+// getNamespace returns the namespace of the given task name at the given depth,
+// or false if the name has no more namespace levels than the depth.
+func getNamespace(name string, depth int) (string, bool) {
+	if depth <= 0 {
+		return "", false
+	}
+
+	parts := strings.Split(name, namespaceSeparator)
+	if len(parts) <= depth {
+		return "", false
+	}
+
+	return strings.Join(parts[:depth], namespaceSeparator), true
+}
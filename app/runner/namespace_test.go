@@ -0,0 +1,45 @@
+package runner
+
+import (
+	"reflect"
+	"testing"
+)
+
+// Tests if the tasks are collapsed into their namespaces.
+func TestGroupTasks(t *testing.T) {
+	tasks := []*orbitTask{
+		{Use: "build", Short: "Builds the application"},
+		{Use: "db:migrate"},
+		{Use: "db:schema:dump"},
+		{Use: "lint"},
+		{Use: "db:schema:load"},
+	}
+
+	// case 1: uses no depth.
+	if entries := groupTasks(tasks, 0); len(entries) != len(tasks) {
+		t.Errorf("All the tasks should have been kept, got %d entries!", len(entries))
+	}
+
+	// case 2: uses a depth of 1.
+	expected := []*orbitListEntry{
+		{name: "build", short: "Builds the application"},
+		{name: "db", count: 3},
+		{name: "lint"},
+	}
+
+	if entries := groupTasks(tasks, 1); !reflect.DeepEqual(entries, expected) {
+		t.Error("Tasks should have been collapsed into their namespaces at depth 1!")
+	}
+
+	// case 3: uses a depth of 2.
+	expected = []*orbitListEntry{
+		{name: "build", short: "Builds the application"},
+		{name: "db:migrate"},
+		{name: "db:schema", count: 2},
+		{name: "lint"},
+	}
+
+	if entries := groupTasks(tasks, 2); !reflect.DeepEqual(entries, expected) {
+		t.Error("Tasks should have been collapsed into their namespaces at depth 2!")
+	}
+}
@@ -0,0 +1,120 @@
+package runner
+
+import (
+	"bytes"
+	gocontext "context"
+	"encoding/json"
+	"net/http"
+	"regexp"
+	"time"
+
+	OrbitError "github.com/gulien/orbit/app/error"
+	"github.com/gulien/orbit/app/logger"
+)
+
+// orbitNotifyConfig represents the webhook configuration used by the notify commands.
+type orbitNotifyConfig struct {
+	// URL is the webhook URL which receives the messages.
+	URL string `yaml:"url"`
+
+	// Retries is the number of additional attempts
+	// if posting a message has failed.
+	Retries int `yaml:"retries,omitempty"`
+
+	// IgnoreFailure allows to report a failed notification
+	// without failing the task.
+	IgnoreFailure bool `yaml:"ignore_failure,omitempty"`
+}
+
+// notifyRetryDelay is the delay between two attempts of posting a message.
+var notifyRetryDelay = time.Second
+
+// notifyClient is the HTTP client used to post the messages.
+var notifyClient = &http.Client{Timeout: 10 * time.Second}
+
+// notifyRegexp is a simple regex pattern used to match a string created by
+// the template function notify.
+var notifyRegexp = regexp.MustCompile(`(?s)^notify@(.*)$`)
+
+// interpretNotification checks if the command is a notification and returns its message.
+func (r *OrbitRunner) interpretNotification(cmd string) (string, bool) {
+	match := notifyRegexp.FindStringSubmatch(cmd)
+	if len(match) == 0 {
+		return "", false
+	}
+
+	return match[1], true
+}
+
+/*
+notify posts the given message from the given task to the configured webhook.
+
+The message is sent as a JSON object with a "text" field, which is understood by Slack
+and most of the chat webhooks.
+*/
+func (r *OrbitRunner) notify(message string, state *orbitTaskState) error {
+	task := state.task
+	config := r.config.Notify
+	if config == nil || config.URL == "" {
+		return OrbitError.NewOrbitErrorf("task %s sends a notification but no notify url is configured in configuration file %s", task.Use, r.context.TemplateFilePath)
+	}
+
+	logger.Infof("sending notification %s from task %s", message, task.Use)
+
+	err := postNotification(state.ctx, config, message)
+	if err != nil && config.IgnoreFailure {
+		logger.Error(OrbitError.NewOrbitErrorf("notification from task %s has failed. Details:\n%s", task.Use, err))
+		return nil
+	}
+
+	return err
+}
+
+// postNotification posts the given message to the webhook, retrying if necessary
+// until the given context is done.
+func postNotification(ctx gocontext.Context, config *orbitNotifyConfig, message string) error {
+	body, err := json.Marshal(map[string]string{"text": message})
+	if err != nil {
+		return OrbitError.NewOrbitErrorf("unable to encode the notification %s. Details:\n%s", message, err)
+	}
+
+	for attempt := 0; ; attempt++ {
+		err = postNotificationOnce(ctx, config.URL, body)
+		if err == nil || attempt >= config.Retries {
+			return err
+		}
+
+		logger.Infof("notification has failed, retrying in %s: %s", notifyRetryDelay, err)
+
+		timer := time.NewTimer(notifyRetryDelay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return OrbitError.NewOrbitErrorf("notification has been cancelled. Details:\n%s", err)
+		case <-timer.C:
+		}
+	}
+}
+
+// postNotificationOnce posts the given JSON body to the given URL, unless the given context is done.
+func postNotificationOnce(ctx gocontext.Context, url string, body []byte) error {
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return OrbitError.NewOrbitErrorf("unable to post the notification to %s. Details:\n%s", url, err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := notifyClient.Do(req.WithContext(ctx))
+	if err != nil {
+		return OrbitError.NewOrbitErrorf("unable to post the notification to %s. Details:\n%s", url, err)
+	}
+
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return OrbitError.NewOrbitErrorf("unable to post the notification to %s: webhook responded with status %s", url, resp.Status)
+	}
+
+	return nil
+}
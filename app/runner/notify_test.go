@@ -0,0 +1,80 @@
+package runner
+
+import (
+	gocontext "context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/gulien/orbit/app/context"
+)
+
+// Tests if a notify command posts its message to the configured webhook.
+func TestNotify(t *testing.T) {
+	notifyRetryDelay = 0
+
+	var messages []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		var body map[string]string
+		json.NewDecoder(req.Body).Decode(&body)
+		messages = append(messages, body["text"])
+	}))
+	defer server.Close()
+
+	failingServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer failingServer.Close()
+
+	templateFilePath, _ := filepath.Abs("../../_tests/orbit-notify.yml")
+	ignoreFailureTemplateFilePath, _ := filepath.Abs("../../_tests/orbit-notify-ignore-failure.yml")
+
+	// case 1: uses a working webhook.
+	ctx, _ := context.NewOrbitContext(templateFilePath, "url,"+server.URL, "")
+	r, _ := NewOrbitRunner(ctx)
+	if err := r.Run("explorer"); err != nil {
+		t.Error("Notification should have been sent!")
+	}
+
+	if len(messages) != 1 || messages[0] != "explorer has been launched" {
+		t.Errorf("Webhook should have received the notification, got %v!", messages)
+	}
+
+	// case 2: uses a failing webhook.
+	ctx, _ = context.NewOrbitContext(templateFilePath, "url,"+failingServer.URL, "")
+	r, _ = NewOrbitRunner(ctx)
+	if err := r.Run("explorer"); err == nil {
+		t.Error("Notification should have failed!")
+	}
+
+	// case 3: uses a failing webhook with ignore_failure.
+	ctx, _ = context.NewOrbitContext(ignoreFailureTemplateFilePath, "url,"+failingServer.URL, "")
+	r, _ = NewOrbitRunner(ctx)
+	if err := r.Run("explorer"); err != nil {
+		t.Error("Failed notification should not have failed the task!")
+	}
+
+	// case 4: uses a notify command without webhook.
+	templateFilePath, _ = filepath.Abs("../../_tests/orbit.yml")
+	ctx, _ = context.NewOrbitContext(templateFilePath, "", "")
+	r, _ = NewOrbitRunner(ctx)
+	state := &orbitTaskState{ctx: gocontext.Background(), task: r.getTask("explorer")}
+	if err := r.notify("explorer has been launched", state); err == nil {
+		t.Error("Notification without webhook should have failed!")
+	}
+
+	// case 5: uses a failing webhook with retries and a cancelled task.
+	notifyRetryDelay = time.Minute
+	defer func() { notifyRetryDelay = time.Second }()
+
+	cancelled, cancel := gocontext.WithCancel(gocontext.Background())
+	cancel()
+
+	start := time.Now()
+	if err := postNotification(cancelled, &orbitNotifyConfig{URL: failingServer.URL, Retries: 3}, "explorer has been launched"); err == nil || time.Since(start) > 10*time.Second {
+		t.Errorf("Notification of a cancelled task should have failed at once, got %v!", err)
+	}
+}
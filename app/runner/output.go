@@ -0,0 +1,84 @@
+package runner
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	OrbitError "github.com/gulien/orbit/app/error"
+)
+
+const (
+	// DefaultOutput prints the output of the commands as-is.
+	DefaultOutput = "default"
+
+	// TeamCityOutput surrounds the output of each task with TeamCity service messages.
+	TeamCityOutput = "teamcity"
+)
+
+type (
+	// orbitOutput is an interface which is implemented for each output mode.
+	orbitOutput interface {
+		// taskStarted is called before running the commands of a task.
+		taskStarted(task *orbitTask)
+
+		// taskFinished is called once a task has been run, with its result.
+		taskFinished(task *orbitTask, err error)
+	}
+
+	// orbitDefaultOutput is the implementation of orbitOutput which prints nothing.
+	orbitDefaultOutput struct{}
+
+	// orbitTeamCityOutput is the implementation of orbitOutput which prints TeamCity service messages.
+	orbitTeamCityOutput struct {
+		// w is the writer of the service messages.
+		w io.Writer
+	}
+)
+
+// getOutput returns the implementation of orbitOutput according to the given output mode, writing to the given writer.
+func getOutput(output string, w io.Writer) (orbitOutput, error) {
+	switch output {
+	case "", DefaultOutput:
+		return &orbitDefaultOutput{}, nil
+	case TeamCityOutput:
+		return &orbitTeamCityOutput{w: w}, nil
+	default:
+		return nil, OrbitError.NewOrbitErrorf("unknown output %s, expected %s or %s", output, DefaultOutput, TeamCityOutput)
+	}
+}
+
+// taskStarted from orbitDefaultOutput does nothing.
+func (o *orbitDefaultOutput) taskStarted(task *orbitTask) {}
+
+// taskFinished from orbitDefaultOutput does nothing.
+func (o *orbitDefaultOutput) taskFinished(task *orbitTask, err error) {}
+
+// taskStarted from orbitTeamCityOutput opens a block named after the task.
+func (o *orbitTeamCityOutput) taskStarted(task *orbitTask) {
+	fmt.Fprintf(o.w, "##teamcity[blockOpened name='%s' description='%s']\n", escapeTeamCity(task.Use), escapeTeamCity(task.Short))
+}
+
+// taskFinished from orbitTeamCityOutput reports a build problem if the task has failed, then closes its block.
+func (o *orbitTeamCityOutput) taskFinished(task *orbitTask, err error) {
+	if err != nil {
+		fmt.Fprintf(o.w, "##teamcity[buildProblem description='%s' identity='%s']\n", escapeTeamCity(fmt.Sprintf("task %s has failed: %s", task.Use, err)), escapeTeamCity(task.Use))
+	}
+
+	fmt.Fprintf(o.w, "##teamcity[blockClosed name='%s']\n", escapeTeamCity(task.Use))
+}
+
+// teamCityReplacer escapes the special characters of a TeamCity service message value.
+var teamCityReplacer = strings.NewReplacer(
+	"|", "||",
+	"'", "|'",
+	"\n", "|n",
+	"\r", "|r",
+	"[", "|[",
+	"]", "|]",
+)
+
+// escapeTeamCity escapes the given value for a TeamCity service message.
+func escapeTeamCity(value string) string {
+	return teamCityReplacer.Replace(value)
+}
@@ -0,0 +1,55 @@
+package runner
+
+import (
+	"bytes"
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/gulien/orbit/app/context"
+)
+
+// Tests if the output modes are retrieved according to their names.
+func TestGetOutput(t *testing.T) {
+	// case 1: uses the default output.
+	if _, err := getOutput(DefaultOutput, &bytes.Buffer{}); err != nil {
+		t.Error("Default output should have been retrieved!")
+	}
+
+	// case 2: uses the TeamCity output.
+	if _, err := getOutput(TeamCityOutput, &bytes.Buffer{}); err != nil {
+		t.Error("TeamCity output should have been retrieved!")
+	}
+
+	// case 3: uses an unknown output.
+	if _, err := getOutput("jenkins", &bytes.Buffer{}); err == nil {
+		t.Error("Unknown output should not have been retrieved!")
+	}
+
+	// case 4: runs a task with an unknown output.
+	templateFilePath, _ := filepath.Abs("../../_tests/orbit.yml")
+	ctx, _ := context.NewOrbitContext(templateFilePath, "", "")
+	r, _ := NewOrbitRunner(ctx)
+	r.Output = "jenkins"
+	if err := r.Run("explorer"); err == nil {
+		t.Error("Task should not have been run with an unknown output!")
+	}
+}
+
+// Tests if the TeamCity output prints well-formed service messages.
+func TestTeamCityOutput(t *testing.T) {
+	var buf bytes.Buffer
+	o := &orbitTeamCityOutput{w: &buf}
+	task := &orbitTask{Use: "falcon [9]", Short: "Elon's launcher"}
+
+	o.taskStarted(task)
+	o.taskFinished(task, errors.New("exit status 1"))
+
+	expected := "##teamcity[blockOpened name='falcon |[9|]' description='Elon|'s launcher']\n" +
+		"##teamcity[buildProblem description='task falcon |[9|] has failed: exit status 1' identity='falcon |[9|]']\n" +
+		"##teamcity[blockClosed name='falcon |[9|]']\n"
+
+	if buf.String() != expected {
+		t.Errorf("TeamCity service messages are malformated, got:\n%s", buf.String())
+	}
+}
@@ -0,0 +1,275 @@
+package runner
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+
+	OrbitError "github.com/gulien/orbit/app/error"
+)
+
+type (
+	// syncWriter writes to its underlying writer while holding its lock.
+	syncWriter struct {
+		// out is the underlying writer.
+		out io.Writer
+
+		// mutex is shared by the writers of all the sub-commands of a parallel group.
+		mutex *sync.Mutex
+	}
+
+	// orbitCommands represents a stack of commands, in which a group
+	// of commands may be executed in parallel.
+	orbitCommands []orbitCommand
+
+	// orbitCommand represents an entry of a stack of commands:
+	// either a command or a parallel group of commands, which may be conditional.
+	orbitCommand struct {
+		// Command is the command to execute, empty for a parallel group of commands.
+		Command string
+
+		// Parallel contains the sub-commands of a parallel group of commands.
+		Parallel []string
+
+		// When is the condition which has to be true to execute the entry.
+		// If nil, the entry is always executed.
+		When *string
+	}
+
+	// orbitCommandEntry represents an entry of a stack of commands as defined in the configuration file
+	// with a mapping: its sub-commands are executed in parallel, or it's executed if its condition is true.
+	orbitCommandEntry struct {
+		// Parallel contains the sub-commands to execute in parallel.
+		Parallel []string `yaml:"parallel"`
+
+		// Run is the command to execute if the condition is true.
+		Run string `yaml:"run"`
+
+		// When is the condition which has to be true to execute the entry.
+		When *string `yaml:"when"`
+	}
+)
+
+/*
+UnmarshalYAML populates the entry from either a command or a mapping with a parallel or a run attribute,
+and an optional when attribute.
+
+The sub-commands of a parallel group cannot call others tasks nor send notifications,
+as they are executed as shell commands.
+*/
+func (c *orbitCommand) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	if err := unmarshal(&c.Command); err == nil {
+		return nil
+	}
+
+	var entry orbitCommandEntry
+	if err := unmarshal(&entry); err != nil {
+		return err
+	}
+
+	switch {
+	case entry.Parallel != nil && entry.Run != "":
+		return OrbitError.NewOrbitErrorf("an entry of a stack of commands should have either a parallel or a run attribute")
+	case entry.Parallel != nil && len(entry.Parallel) == 0:
+		return OrbitError.NewOrbitErrorf("a parallel group of commands should have at least one command")
+	case entry.Parallel != nil:
+		for _, cmd := range entry.Parallel {
+			if compiledRegexp.MatchString(cmd) || notifyRegexp.MatchString(cmd) {
+				return OrbitError.NewOrbitErrorf("parallel command %s should not call others tasks nor send notifications", cmd)
+			}
+		}
+
+		c.Parallel = entry.Parallel
+	case entry.Run != "":
+		c.Command = entry.Run
+	case entry.When != nil:
+		return OrbitError.NewOrbitErrorf("a conditional entry of a stack of commands should have a parallel or a run attribute")
+	default:
+		return OrbitError.NewOrbitErrorf("command must be a string or a parallel/when entry")
+	}
+
+	c.When = entry.When
+
+	return nil
+}
+
+// String returns the command, or the sub-commands separated by commas for a parallel group of commands.
+func (c orbitCommand) String() string {
+	if len(c.Parallel) > 0 {
+		return strings.Join(c.Parallel, ", ")
+	}
+
+	return c.Command
+}
+
+// plainCommands returns a stack of unconditional commands from the given commands, e.g. the ones of a hook.
+func plainCommands(cmds []string) orbitCommands {
+	commands := make(orbitCommands, len(cmds))
+	for index, cmd := range cmds {
+		commands[index] = orbitCommand{Command: cmd}
+	}
+
+	return commands
+}
+
+// stacks returns the stacks of commands of the task in their order of execution, including its hooks.
+func (t *orbitTask) stacks() []orbitCommands {
+	return []orbitCommands{
+		plainCommands(t.Before),
+		t.Run,
+		plainCommands(t.AfterSuccess),
+		plainCommands(t.AfterFailure),
+		plainCommands(t.OnFailure),
+		plainCommands(t.After),
+	}
+}
+
+// displayCommands returns the given stack of commands with the sub-commands of its parallel groups
+// flattened and prefixed by "(parallel)", and the conditional entries prefixed by their condition.
+func displayCommands(stack orbitCommands) []string {
+	var commands []string
+	for _, entry := range stack {
+		prefix := ""
+		if entry.When != nil {
+			prefix = fmt.Sprintf("(when %s) ", *entry.When)
+		}
+
+		if len(entry.Parallel) == 0 {
+			commands = append(commands, prefix+entry.Command)
+			continue
+		}
+
+		for _, cmd := range entry.Parallel {
+			commands = append(commands, prefix+"(parallel) "+cmd)
+		}
+	}
+
+	return commands
+}
+
+/*
+taskStack returns the stack of commands from Run of the given task.
+
+If the task has the parallel attribute, each sequence of consecutive commands, including the sub-commands
+of the parallel groups, becomes a parallel group. The calls to others tasks, the notifications and
+the conditional entries remain sequential, between these groups.
+*/
+func (r *OrbitRunner) taskStack(task *orbitTask) orbitCommands {
+	if !isEnabled(task.Parallel) {
+		return task.Run
+	}
+
+	var stack orbitCommands
+	var group []string
+	flush := func() {
+		if len(group) > 0 {
+			stack = append(stack, orbitCommand{Parallel: group})
+			group = nil
+		}
+	}
+
+	for _, entry := range task.Run {
+		if entry.When != nil {
+			flush()
+			stack = append(stack, entry)
+			continue
+		}
+
+		if len(entry.Parallel) > 0 {
+			group = append(group, entry.Parallel...)
+			continue
+		}
+
+		if _, ok := r.interpretNotification(entry.Command); ok || r.interpret(entry.Command) != nil {
+			flush()
+			stack = append(stack, entry)
+			continue
+		}
+
+		group = append(group, entry.Command)
+	}
+
+	flush()
+
+	return stack
+}
+
+/*
+executeParallel executes the given sub-commands from the given running task concurrently
+and waits for all of them.
+
+Each sub-command inherits the given environment, but its changes are not carried
+to the next commands of the task, even if the working environment is enabled.
+If MaxWorkers is positive, at most MaxWorkers sub-commands are executed at the same time.
+If PrefixOutput is true, each line of their outputs is prefixed by the name of the task and the index of the sub-command.
+If some sub-commands fail, returns an error aggregating their failures.
+*/
+func (r *OrbitRunner) executeParallel(cmds []string, state *orbitTaskState, environ []string) error {
+	errs := make([]error, len(cmds))
+
+	// a dry run prints the commands in their order of declaration.
+	if r.DryRun {
+		for _, cmd := range cmds {
+			if _, err := r.executeCommand(cmd, state, environ); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+
+	workers := len(cmds)
+	if r.MaxWorkers > 0 && r.MaxWorkers < workers {
+		workers = r.MaxWorkers
+	}
+
+	// the sub-commands write the outputs of the running task through the same lock,
+	// as they may be buffered (e.g. for diff_previous).
+	shared := *state
+	mutex := &sync.Mutex{}
+	shared.stdout = &syncWriter{out: state.stdout, mutex: mutex}
+	shared.stderr = &syncWriter{out: state.stderr, mutex: mutex}
+
+	semaphore := make(chan struct{}, workers)
+	var wg sync.WaitGroup
+	for index, cmd := range cmds {
+		wg.Add(1)
+		go func(index int, cmd string) {
+			defer wg.Done()
+
+			semaphore <- struct{}{}
+			defer func() { <-semaphore }()
+
+			// the sub-command is executed through its own copy of the running task,
+			// so that its outputs are prefixed by its index.
+			sub := shared
+			sub.index = index + 1
+
+			_, errs[index] = r.executeCommand(cmd, &sub, environ)
+		}(index, cmd)
+	}
+
+	wg.Wait()
+
+	var details []string
+	for index, err := range errs {
+		if err != nil {
+			details = append(details, cmds[index]+": "+err.Error())
+		}
+	}
+
+	if len(details) > 0 {
+		return OrbitError.NewOrbitErrorf("%d of %d parallel commands from task %s have failed. Details:\n%s", len(details), len(cmds), state.task.Use, strings.Join(details, "\n"))
+	}
+
+	return nil
+}
+
+// Write writes the given data to the underlying writer while holding the lock.
+func (w *syncWriter) Write(data []byte) (int, error) {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	return w.out.Write(data)
+}
@@ -0,0 +1,168 @@
+package runner
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gulien/orbit/app/context"
+	"gopkg.in/yaml.v2"
+)
+
+// Tests if the sub-commands of a parallel group of commands are executed concurrently.
+func TestExecuteParallel(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("skipping test on Windows")
+	}
+
+	templateFilePath, _ := filepath.Abs("../../_tests/orbit-parallel-empty.yml")
+	ctx, _ := context.NewOrbitContext(templateFilePath, "", "")
+
+	// case 1: uses an empty parallel group of commands.
+	if _, err := NewOrbitRunner(ctx); err == nil {
+		t.Error("Configuration file with an empty parallel group of commands should not have been valid!")
+	}
+
+	templateFilePath, _ = filepath.Abs("../../_tests/orbit-parallel.yml")
+	ctx, _ = context.NewOrbitContext(templateFilePath, "", "")
+	r, err := NewOrbitRunner(ctx)
+	if err != nil {
+		t.Fatalf("Runner should have been created, got %s!", err)
+	}
+
+	// case 2: uses a parallel group of commands followed by a command.
+	if err := r.Run("explorer"); err != nil {
+		t.Errorf("Parallel commands should have been executed concurrently, got %s!", err)
+	}
+
+	// case 3: uses a parallel group of commands with failures.
+	err = r.Run("sputnik")
+	if err == nil || !strings.Contains(err.Error(), "2 of 3 parallel commands") {
+		t.Errorf("Failures of the parallel commands should have been aggregated, got %v!", err)
+	}
+
+	if len(r.failures) != 2 {
+		t.Errorf("Failures of the parallel commands should have been recorded, got %d!", len(r.failures))
+	}
+
+	// case 4: uses a parallel task with calls to others tasks.
+	if err := r.Run("challenger"); err != nil {
+		t.Errorf("Commands of a parallel task should have been executed concurrently, got %s!", err)
+	}
+
+	// case 5: uses a parallel task with failures.
+	err = r.Run("gemini")
+	if err == nil || !strings.Contains(err.Error(), "2 of 3 parallel commands") {
+		t.Errorf("Failures of a parallel task should have been aggregated, got %v!", err)
+	}
+}
+
+// Tests if the commands of a parallel task are grouped between the calls to others tasks.
+func TestTaskStack(t *testing.T) {
+	r := &OrbitRunner{config: &orbitRunnerConfig{}}
+	condition := "true"
+	task := &orbitTask{
+		Use: "explorer",
+		Run: orbitCommands{
+			{Command: "echo 1"},
+			{Command: "run@sputnik"},
+			{Command: "echo 2"},
+			{Parallel: []string{"echo 3"}},
+			{Command: "echo 4", When: &condition},
+			{Command: "notify@done"},
+		},
+	}
+
+	// case 1: uses a sequential task.
+	if stack := r.taskStack(task); !reflect.DeepEqual(stack, task.Run) {
+		t.Errorf("Commands of a sequential task should have been kept, got %v!", stack)
+	}
+
+	// case 2: uses a parallel task.
+	parallel := true
+	task.Parallel = &parallel
+	expected := orbitCommands{
+		{Parallel: []string{"echo 1"}},
+		{Command: "run@sputnik"},
+		{Parallel: []string{"echo 2", "echo 3"}},
+		{Command: "echo 4", When: &condition},
+		{Command: "notify@done"},
+	}
+
+	if stack := r.taskStack(task); !reflect.DeepEqual(stack, expected) {
+		t.Errorf("Commands of a parallel task should have been grouped, got %v!", stack)
+	}
+}
+
+// Tests if the entries of a stack of commands are populated from the configuration file.
+func TestUnmarshalCommands(t *testing.T) {
+	// case 1: uses commands, a parallel group of commands and a conditional entry.
+	var commands orbitCommands
+	data := "- echo 1\n- parallel: [echo 2, echo 3]\n- run: echo 4\n  when: \"true\"\n"
+	if err := yaml.Unmarshal([]byte(data), &commands); err != nil {
+		t.Fatalf("Commands should have been populated, got %s!", err)
+	}
+
+	condition := "true"
+	expected := orbitCommands{{Command: "echo 1"}, {Parallel: []string{"echo 2", "echo 3"}}, {Command: "echo 4", When: &condition}}
+	if !reflect.DeepEqual(commands, expected) {
+		t.Errorf("Commands should have been %v, got %v!", expected, commands)
+	}
+
+	// case 2: uses a command with an unquoted colon, which is a mapping.
+	err := yaml.Unmarshal([]byte("- echo \"x: y\"\n"), &commands)
+	if err == nil || !strings.Contains(err.Error(), "command must be a string or a parallel/when entry") {
+		t.Errorf("Command with an unquoted colon should have been rejected, got %v!", err)
+	}
+
+	// case 3: uses parallel commands calling others tasks or sending notifications.
+	for _, cmd := range []string{"run@sputnik", "notify@done"} {
+		err := yaml.Unmarshal([]byte("- parallel: [echo 1, "+cmd+"]\n"), &commands)
+		if err == nil || !strings.Contains(err.Error(), "parallel command "+cmd) {
+			t.Errorf("Parallel command %s should have been rejected, got %v!", cmd, err)
+		}
+	}
+}
+
+// Tests if the commands executed in parallel are limited and prefixed according to the runner.
+func TestExecuteParallelOptions(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("skipping test on Windows")
+	}
+
+	templateFilePath, _ := filepath.Abs("../../_tests/orbit-parallel.yml")
+	ctx, _ := context.NewOrbitContext(templateFilePath, "", "")
+	r, _ := NewOrbitRunner(ctx)
+	defer os.Remove("../../_tests/parallel-output.log")
+
+	// case 1: uses a maximum number of workers.
+	r.MaxWorkers = 1
+	start := time.Now()
+	if err := r.Run("apollo"); err != nil || time.Since(start) < 900*time.Millisecond {
+		t.Error("Commands should have been executed one at a time!")
+	}
+
+	// case 2: uses no maximum number of workers.
+	r.MaxWorkers = 0
+	start = time.Now()
+	if err := r.Run("apollo"); err != nil || time.Since(start) >= 900*time.Millisecond {
+		t.Error("Commands should have been executed at the same time!")
+	}
+
+	// case 3: uses prefixed outputs.
+	r.PrefixOutput = true
+	if err := r.Run("mercury"); err != nil {
+		t.Errorf("Commands with prefixed outputs should have been executed, got %s!", err)
+	}
+
+	data, _ := ioutil.ReadFile("../../_tests/parallel-output.log")
+	output := string(data)
+	if !strings.Contains(output, "[mercury:1] I am the first parallel command\n") || !strings.Contains(output, "[mercury:2] I am the second parallel command\n") {
+		t.Errorf("Outputs should have been prefixed by the task and the index of the commands, got %q!", output)
+	}
+}
@@ -0,0 +1,252 @@
+package runner
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	OrbitError "github.com/gulien/orbit/app/error"
+
+	"golang.org/x/crypto/ssh/terminal"
+)
+
+// pickerHeight is the maximum number of tasks displayed at once by the interactive picker.
+const pickerHeight = 10
+
+// orbitPicker is the interactive menu from which the user picks a task, filtered by a fuzzy search.
+type orbitPicker struct {
+	// tasks contains the tasks which may be picked.
+	tasks []*orbitTask
+
+	// query is the fuzzy search typed by the user.
+	query []rune
+
+	// matches contains the tasks matching the query, in their order of declaration.
+	matches []*orbitTask
+
+	// cursor is the index of the highlighted task inside matches.
+	cursor int
+}
+
+// newPicker creates an instance of orbitPicker for the given tasks.
+func newPicker(tasks []*orbitTask) *orbitPicker {
+	p := &orbitPicker{tasks: tasks}
+	p.filter()
+
+	return p
+}
+
+// fuzzyMatch returns true if the characters of the given query appear in the given value in the same order, ignoring the case.
+func fuzzyMatch(query string, value string) bool {
+	value = strings.ToLower(value)
+	for _, r := range strings.ToLower(query) {
+		index := strings.IndexRune(value, r)
+		if index < 0 {
+			return false
+		}
+
+		value = value[index+utf8.RuneLen(r):]
+	}
+
+	return true
+}
+
+// filter updates the matches from the query: a task matches if the query fuzzy matches its name or its short description.
+func (p *orbitPicker) filter() {
+	p.matches = nil
+	for _, task := range p.tasks {
+		if fuzzyMatch(string(p.query), task.Use) || fuzzyMatch(string(p.query), task.Short) {
+			p.matches = append(p.matches, task)
+		}
+	}
+
+	if p.cursor >= len(p.matches) {
+		p.cursor = len(p.matches) - 1
+	}
+
+	if p.cursor < 0 {
+		p.cursor = 0
+	}
+}
+
+/*
+handleInput updates the picker from the given keys typed by the user.
+
+Returns true once the user has pressed Enter, with the highlighted task, or has cancelled with Escape or Ctrl+C,
+without task. The arrows (or Ctrl+P and Ctrl+N) move the highlight and the others keys edit the query.
+*/
+func (p *orbitPicker) handleInput(data []byte) (bool, *orbitTask) {
+	for len(data) > 0 {
+		switch {
+		case bytes.HasPrefix(data, []byte("\x1b[A")), bytes.HasPrefix(data, []byte("\x1bOA")):
+			p.move(-1)
+			data = data[3:]
+			continue
+		case bytes.HasPrefix(data, []byte("\x1b[B")), bytes.HasPrefix(data, []byte("\x1bOB")):
+			p.move(1)
+			data = data[3:]
+			continue
+		case bytes.HasPrefix(data, []byte("\x1b[")):
+			// other escape sequences (e.g. the left and right arrows) are ignored.
+			data = data[len(data):]
+			continue
+		}
+
+		switch data[0] {
+		case '\r', '\n':
+			if len(p.matches) == 0 {
+				data = data[1:]
+				continue
+			}
+
+			return true, p.matches[p.cursor]
+		case 0x1b, 0x03, 0x04:
+			return true, nil
+		case 0x10:
+			p.move(-1)
+		case 0x0e:
+			p.move(1)
+		case 0x7f, 0x08:
+			if len(p.query) > 0 {
+				p.query = p.query[:len(p.query)-1]
+				p.filter()
+			}
+		default:
+			r, size := utf8.DecodeRune(data)
+			if unicode.IsPrint(r) {
+				p.query = append(p.query, r)
+				p.filter()
+			}
+
+			data = data[size:]
+			continue
+		}
+
+		data = data[1:]
+	}
+
+	return false, nil
+}
+
+// move moves the highlight by the given offset, inside the matches.
+func (p *orbitPicker) move(offset int) {
+	if cursor := p.cursor + offset; cursor >= 0 && cursor < len(p.matches) {
+		p.cursor = cursor
+	}
+}
+
+/*
+render writes the picker to the given writer, from the current line, with lines of at most the given width,
+then moves the cursor back to the end of the query: this way, the next rendering replaces this one.
+As the terminal is in raw mode, the lines are separated by "\r\n".
+*/
+func (p *orbitPicker) render(w io.Writer, width int) {
+	prompt := truncate(fmt.Sprintf("pick a task (%d/%d): %s", len(p.matches), len(p.tasks), string(p.query)), width)
+
+	var b strings.Builder
+	b.WriteString("\r\x1b[J" + prompt)
+
+	// the highlighted task is always visible.
+	start := 0
+	if p.cursor >= pickerHeight {
+		start = p.cursor - pickerHeight + 1
+	}
+
+	lines := 0
+	for index := start; index < len(p.matches) && index < start+pickerHeight; index++ {
+		task := p.matches[index]
+
+		line := task.Use
+		if task.Short != "" {
+			line += " - " + task.Short
+		}
+
+		if index == p.cursor {
+			line = "\x1b[7m" + truncate("> "+line, width) + "\x1b[0m"
+		} else {
+			line = truncate("  "+line, width)
+		}
+
+		b.WriteString("\r\n" + line)
+		lines++
+	}
+
+	if lines > 0 {
+		fmt.Fprintf(&b, "\x1b[%dF", lines)
+		if columns := utf8.RuneCountInString(prompt); columns > 0 {
+			fmt.Fprintf(&b, "\x1b[%dC", columns)
+		}
+	}
+
+	io.WriteString(w, b.String())
+}
+
+// truncate returns the given line with at most the given number of characters. If the width is not positive, the line is not truncated.
+func truncate(line string, width int) string {
+	if width <= 0 || utf8.RuneCountInString(line) <= width {
+		return line
+	}
+
+	return string([]rune(line)[:width])
+}
+
+/*
+PickTask asks the user to pick one of the tasks which are not private from an interactive menu, filtered by a fuzzy search
+on their names and short descriptions. The menu is displayed on Stderr.
+
+Returns false if Stdin or Stderr is not a terminal, if there are no tasks or if the user has cancelled.
+*/
+func (r *OrbitRunner) PickTask() (string, bool, error) {
+	fd, isTerminal := terminalFd(r.Stdin)
+	stderr, ok := r.Stderr.(*os.File)
+	if !isTerminal || !ok || !terminal.IsTerminal(int(stderr.Fd())) {
+		return "", false, nil
+	}
+
+	tasks, err := r.listTasks()
+	if err != nil || len(tasks) == 0 {
+		return "", false, err
+	}
+
+	state, err := terminal.MakeRaw(fd)
+	if err != nil {
+		return "", false, OrbitError.NewOrbitErrorf("unable to display the interactive picker. Details:\n%s", err)
+	}
+
+	defer terminal.Restore(fd, state)
+	defer io.WriteString(stderr, "\r\x1b[J")
+
+	// the lines should not wrap, otherwise the next rendering would not replace them.
+	width, _, err := terminal.GetSize(int(stderr.Fd()))
+	if err != nil {
+		width = 0
+	}
+
+	p := newPicker(tasks)
+	buffer := make([]byte, 64)
+
+	for {
+		p.render(stderr, width-1)
+
+		n, err := r.Stdin.Read(buffer)
+		if err != nil {
+			if err == io.EOF {
+				return "", false, nil
+			}
+
+			return "", false, OrbitError.NewOrbitErrorf("unable to read the picked task. Details:\n%s", err)
+		}
+
+		if done, task := p.handleInput(buffer[:n]); done {
+			if task == nil {
+				return "", false, nil
+			}
+
+			return task.Use, true, nil
+		}
+	}
+}
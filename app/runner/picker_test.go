@@ -0,0 +1,65 @@
+package runner
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+// Tests if the query of the interactive picker fuzzy matches the tasks.
+func TestFuzzyMatch(t *testing.T) {
+	// case 1: uses a query whose characters appear in order.
+	if !fuzzyMatch("dpl", "Deploy") || !fuzzyMatch("", "deploy") {
+		t.Error("Query should have matched!")
+	}
+
+	// case 2: uses a query whose characters do not appear in order.
+	if fuzzyMatch("lpd", "deploy") || fuzzyMatch("deployment", "deploy") {
+		t.Error("Query should not have matched!")
+	}
+}
+
+// Tests if the interactive picker handles the keys typed by the user.
+func TestPicker(t *testing.T) {
+	tasks := []*orbitTask{
+		{Use: "build", Short: "Builds the binary"},
+		{Use: "deploy", Short: "Deploys to production"},
+		{Use: "test"},
+	}
+
+	// case 1: uses the arrows.
+	p := newPicker(tasks)
+	if done, task := p.handleInput([]byte("\x1b[B\x1b[B\x1b[B\x1b[A\r")); !done || task != tasks[1] {
+		t.Errorf("Second task should have been picked, got %v!", task)
+	}
+
+	// case 2: uses a query matching a short description, then the backspace.
+	p = newPicker(tasks)
+	if p.handleInput([]byte("prx")); len(p.matches) != 0 {
+		t.Errorf("No task should have matched, got %d!", len(p.matches))
+	}
+
+	if done, task := p.handleInput([]byte("\x7f\r")); !done || task != tasks[1] {
+		t.Errorf("Task matching the short description should have been picked, got %v!", task)
+	}
+
+	// case 3: uses Enter without matching task, then Escape.
+	p = newPicker(tasks)
+	if done, _ := p.handleInput([]byte("zzz\r")); done {
+		t.Error("Enter without matching task should have been ignored!")
+	}
+
+	if done, task := p.handleInput([]byte("\x1b")); !done || task != nil {
+		t.Error("Escape should have cancelled the picker!")
+	}
+
+	// case 4: renders the picker.
+	p = newPicker(tasks)
+	p.handleInput([]byte("\x1b[B"))
+
+	var b bytes.Buffer
+	p.render(&b, 20)
+	if output := b.String(); !strings.Contains(output, "pick a task (3/3)") || !strings.Contains(output, "\x1b[7m> deploy - Deploys t\x1b[0m") || !strings.Contains(output, "  test") {
+		t.Errorf("Picker should have rendered the tasks with the highlighted and truncated one, got %q!", output)
+	}
+}
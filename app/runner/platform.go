@@ -0,0 +1,72 @@
+package runner
+
+import (
+	"runtime"
+	"strings"
+
+	OrbitError "github.com/gulien/orbit/app/error"
+)
+
+// knownPlatforms contains the values of the os attribute which are checked by Check.
+var knownPlatforms = map[string]bool{
+	"aix": true, "android": true, "darwin": true, "dragonfly": true, "freebsd": true, "illumos": true, "ios": true,
+	"js": true, "linux": true, "netbsd": true, "openbsd": true, "plan9": true, "solaris": true, "windows": true,
+}
+
+// matchesPlatform returns true if the given task may be run on the given platform:
+// either it has no os attribute or the platform is one of its values.
+func matchesPlatform(task *orbitTask, platform string) bool {
+	if len(task.OS) == 0 {
+		return true
+	}
+
+	for _, os := range task.OS {
+		if os == platform {
+			return true
+		}
+	}
+
+	return false
+}
+
+/*
+resolvePlatforms keeps, for each task defined more than once, the definitions matching the current platform.
+
+If no definition of a task matches the current platform, the first one is kept: running it
+throws an error explaining the mismatch. The definitions matching the current platform
+are all kept, so that Check still reports them as duplicates.
+*/
+func resolvePlatforms(config *orbitRunnerConfig) {
+	matching := make(map[string]bool)
+	for _, task := range config.Tasks {
+		if matchesPlatform(task, runtime.GOOS) {
+			matching[task.Use] = true
+		}
+	}
+
+	kept := make(map[string]bool)
+	tasks := config.Tasks[:0]
+	for _, task := range config.Tasks {
+		if matching[task.Use] && !matchesPlatform(task, runtime.GOOS) {
+			continue
+		}
+
+		if !matching[task.Use] && kept[task.Use] {
+			continue
+		}
+
+		kept[task.Use] = true
+		tasks = append(tasks, task)
+	}
+
+	config.Tasks = tasks
+}
+
+// platformError returns an error if the given task may not be run on the current platform.
+func platformError(task *orbitTask) error {
+	if matchesPlatform(task, runtime.GOOS) {
+		return nil
+	}
+
+	return OrbitError.NewOrbitErrorf("task %s is only available on %s, not on %s", task.Use, strings.Join(task.OS, ", "), runtime.GOOS)
+}
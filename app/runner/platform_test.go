@@ -0,0 +1,46 @@
+package runner
+
+import (
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/gulien/orbit/app/context"
+)
+
+// Tests if the tasks are resolved and run according to the current platform.
+func TestResolvePlatforms(t *testing.T) {
+	if runtime.GOOS == "plan9" {
+		t.Skip("the fixtures use plan9 as the non-matching platform")
+	}
+
+	templateFilePath, _ := filepath.Abs("../../_tests/orbit-platforms.yml")
+	ctx, _ := context.NewOrbitContext(templateFilePath, "", "")
+	r, _ := NewOrbitRunner(ctx)
+
+	// case 1: uses a task defined for many platforms.
+	if task := r.getTask("explorer"); task == nil || task.OS[0] != runtime.GOOS {
+		t.Error("Definition of the task matching the current platform should have been kept!")
+	}
+
+	if err := r.Run("explorer"); err != nil {
+		t.Errorf("Task matching the current platform should have been run, got %s!", err)
+	}
+
+	// case 2: uses a task for another platform.
+	if err := r.Run("sputnik"); err == nil || !strings.Contains(err.Error(), "task sputnik is only available on plan9, not on "+runtime.GOOS) {
+		t.Errorf("Task for another platform should not have been run, got %v!", err)
+	}
+
+	// case 3: uses a call to a task for another platform.
+	if err := r.Run("vostok"); err == nil {
+		t.Error("Called task for another platform should not have been run!")
+	}
+
+	// case 4: uses an unknown platform.
+	problems := r.Check()
+	if len(problems) != 1 || !strings.Contains(problems[0].Error(), "unknown os atari") {
+		t.Errorf("Unknown platform should have been the only problem, got %v!", problems)
+	}
+}
@@ -0,0 +1,205 @@
+package runner
+
+import (
+	"bytes"
+	"fmt"
+	"hash/fnv"
+	"io"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/gulien/orbit/app/logger"
+)
+
+const (
+	// consoleTimeFormat is the format of the timestamps of the lines printed to the console.
+	consoleTimeFormat = "15:04:05.000"
+
+	// logFileTimeFormat is the format of the timestamps of the lines written to the log file.
+	logFileTimeFormat = "2006-01-02T15:04:05.000Z07:00"
+)
+
+// prefixColors contains the ANSI color codes of the prefixes, one of them being picked for each task.
+var prefixColors = []int{36, 32, 33, 35, 34, 91, 96, 92}
+
+/*
+prefixWriter writes each line it receives prefixed, and keeps the beginning of the current line until it's complete.
+
+A line longer than the maximum size of the buffer is written in parts (e.g. a progress bar without line break),
+each of them being prefixed and followed by a line break, so that the memory used by a command remains bounded.
+*/
+type prefixWriter struct {
+	// out is the underlying writer.
+	out io.Writer
+
+	// label is written before each line, after the timestamp (e.g. "[my_task:2] ").
+	label string
+
+	// color is the ANSI color code of the label. If 0, the label is not colored.
+	color int
+
+	// timeFormat is the format of the timestamp written before each line. If empty, the lines are not timestamped.
+	timeFormat string
+
+	// mutex is shared by the writers of all the commands, so that their lines are not mixed.
+	mutex *sync.Mutex
+
+	// buffer contains the beginning of the current line.
+	buffer bytes.Buffer
+
+	// size is the maximum size of the buffer. If 0, the current line is kept until it's complete, whatever its size.
+	size int
+}
+
+// Write writes the complete lines of the given data, prefixed, and keeps the beginning of the current line.
+// Only the given data is scanned for line breaks: the beginning of the current line is not scanned again.
+func (w *prefixWriter) Write(data []byte) (int, error) {
+	written := len(data)
+
+	for {
+		index := bytes.IndexByte(data, '\n')
+		if index < 0 {
+			break
+		}
+
+		w.buffer.Write(data[:index+1])
+		data = data[index+1:]
+
+		line := w.buffer.String()
+		w.buffer.Reset()
+
+		if err := w.writeLine(line); err != nil {
+			return written, err
+		}
+	}
+
+	// the line is not complete yet.
+	w.buffer.Write(data)
+
+	for w.size > 0 && w.buffer.Len() >= w.size {
+		if err := w.writeLine(string(w.buffer.Next(w.partSize())) + "\n"); err != nil {
+			return written, err
+		}
+	}
+
+	return written, nil
+}
+
+// partSize returns the size of the next part of the current line, which does not cut a UTF-8 character if possible.
+func (w *prefixWriter) partSize() int {
+	data := w.buffer.Bytes()
+	for size := w.size; size > 0 && size > w.size-utf8.UTFMax; size-- {
+		if utf8.RuneStart(data[size]) {
+			return size
+		}
+	}
+
+	return w.size
+}
+
+// flush writes the current line, prefixed and followed by a line break, if any.
+func (w *prefixWriter) flush() error {
+	if w.buffer.Len() == 0 {
+		return nil
+	}
+
+	line := w.buffer.String() + "\n"
+	w.buffer.Reset()
+
+	return w.writeLine(line)
+}
+
+// writeLine writes the given line, prefixed, to the underlying writer.
+func (w *prefixWriter) writeLine(line string) error {
+	prefix := w.label
+	if w.color != 0 {
+		prefix = fmt.Sprintf("\x1b[%dm%s\x1b[0m", w.color, prefix)
+	}
+
+	if w.timeFormat != "" {
+		prefix = time.Now().Format(w.timeFormat) + " " + prefix
+	}
+
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	_, err := io.WriteString(w.out, prefix+line)
+
+	return err
+}
+
+// prefixColor returns the ANSI color code of the prefixes of the given task, which is always the same for a task.
+func prefixColor(task *orbitTask) int {
+	hash := fnv.New32a()
+	hash.Write([]byte(task.Use))
+
+	return prefixColors[hash.Sum32()%uint32(len(prefixColors))]
+}
+
+// outputLabel returns the label of the lines of the outputs of the commands from the given running task:
+// the name of the task, followed by the index of the command inside a parallel group, if any.
+func outputLabel(state *orbitTaskState) string {
+	if state.index > 0 {
+		return fmt.Sprintf("[%s:%d] ", state.task.Use, state.index)
+	}
+
+	return fmt.Sprintf("[%s] ", state.task.Use)
+}
+
+/*
+commandWriters returns the writers of the standard output and error of a command from the given running task,
+and the function to call once the command has ended, which writes their last incomplete lines.
+
+If PrefixOutput is true, each line is prefixed by the label of the command, colored if the colors are enabled
+and the output is not redirected to a file. If Timestamps is true, each line is also prefixed by its time.
+If LogOutput is not nil, it also receives each line, labelled and timestamped but never colored.
+A line longer than OutputBufferSize is written in parts.
+*/
+func (r *OrbitRunner) commandWriters(state *orbitTaskState) (io.Writer, io.Writer, func()) {
+	stdout, stderr := state.stdout, state.stderr
+	if !r.PrefixOutput && !r.Timestamps && r.LogOutput == nil {
+		return stdout, stderr, func() {}
+	}
+
+	var writers []*prefixWriter
+	wrap := func(out io.Writer, redirected bool) io.Writer {
+		if !r.PrefixOutput && !r.Timestamps {
+			return out
+		}
+
+		w := &prefixWriter{out: out, mutex: &r.outputMutex, size: r.OutputBufferSize}
+		if r.PrefixOutput {
+			w.label = outputLabel(state)
+			if logger.UseColor() && !redirected {
+				w.color = prefixColor(state.task)
+			}
+		}
+
+		if r.Timestamps {
+			w.timeFormat = consoleTimeFormat
+		}
+
+		writers = append(writers, w)
+
+		return w
+	}
+
+	stdout = wrap(stdout, state.task.Stdout != "")
+	stderr = wrap(stderr, state.task.Stderr != "")
+
+	if r.LogOutput != nil {
+		logOut := &prefixWriter{out: r.LogOutput, label: outputLabel(state), timeFormat: logFileTimeFormat, mutex: &r.outputMutex, size: r.OutputBufferSize}
+		logErr := &prefixWriter{out: r.LogOutput, label: outputLabel(state), timeFormat: logFileTimeFormat, mutex: &r.outputMutex, size: r.OutputBufferSize}
+		writers = append(writers, logOut, logErr)
+
+		stdout = io.MultiWriter(stdout, logOut)
+		stderr = io.MultiWriter(stderr, logErr)
+	}
+
+	return stdout, stderr, func() {
+		for _, w := range writers {
+			w.flush()
+		}
+	}
+}
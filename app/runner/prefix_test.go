@@ -0,0 +1,98 @@
+package runner
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/gulien/orbit/app/context"
+)
+
+// Tests if the outputs of the commands are prefixed and copied to the log output.
+func TestCommandWriters(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("skipping test on Windows")
+	}
+
+	templateFilePath, _ := filepath.Abs("../../_tests/orbit-parallel.yml")
+	ctx, _ := context.NewOrbitContext(templateFilePath, "", "")
+	r, _ := NewOrbitRunner(ctx)
+	defer os.Remove("../../_tests/parallel-output.log")
+
+	// case 1: uses a log output with a task executing its commands in parallel.
+	var log bytes.Buffer
+	r.LogOutput = &log
+	if err := r.Run("mercury"); err != nil {
+		t.Errorf("Commands should have been executed, got %s!", err)
+	}
+
+	data, _ := ioutil.ReadFile("../../_tests/parallel-output.log")
+	if output := string(data); strings.Contains(output, "[mercury") {
+		t.Errorf("Outputs should not have been prefixed without the prefix output, got %q!", output)
+	}
+
+	line := regexp.MustCompile(`(?m)^\d{4}-\d{2}-\d{2}T\d{2}:\d{2}:\d{2}\.\d{3}\S* \[mercury:2\] I am the second parallel command$`)
+	if !line.MatchString(log.String()) {
+		t.Errorf("Log output should have received the timestamped and prefixed lines, got %q!", log.String())
+	}
+
+	// case 2: uses prefixed and timestamped outputs with a sequential command.
+	r.LogOutput = nil
+	r.PrefixOutput = true
+	r.Timestamps = true
+
+	state := &orbitTaskState{task: r.getTask("vostok"), stdout: &bytes.Buffer{}, stderr: &bytes.Buffer{}}
+	stdout, _, flush := r.commandWriters(state)
+	stdout.Write([]byte("I am vostok task"))
+	flush()
+
+	line = regexp.MustCompile(`^\d{2}:\d{2}:\d{2}\.\d{3} (\x1b\[\d+m)?\[vostok\] (\x1b\[0m)?I am vostok task\n$`)
+	if output := state.stdout.(*bytes.Buffer).String(); !line.MatchString(output) {
+		t.Errorf("Output should have been timestamped and prefixed by the task, got %q!", output)
+	}
+}
+
+// Tests if the lines are prefixed once complete, and the lines longer than the buffer written in parts.
+func TestPrefixWriter(t *testing.T) {
+	// case 1: uses a line written in many parts.
+	var out bytes.Buffer
+	w := &prefixWriter{out: &out, label: "[vostok] ", mutex: &sync.Mutex{}, size: 16}
+	w.Write([]byte("I am "))
+	w.Write([]byte("vostok\nI am "))
+	w.Write([]byte("soyuz\n"))
+	if expected := "[vostok] I am vostok\n[vostok] I am soyuz\n"; out.String() != expected {
+		t.Errorf("Lines should have been joined and prefixed, got %q!", out.String())
+	}
+
+	// case 2: uses a line longer than the buffer.
+	out.Reset()
+	w.Write([]byte("0123456789"))
+	w.Write([]byte("0123456789abcdefghij"))
+	w.flush()
+	if expected := "[vostok] 0123456789012345\n[vostok] 6789abcdefghij\n"; out.String() != expected {
+		t.Errorf("Line longer than the buffer should have been written in parts, got %q!", out.String())
+	}
+
+	// case 3: uses a line longer than the buffer with a multi-byte character at its limit.
+	out.Reset()
+	w.Write([]byte("012345678901234é"))
+	w.flush()
+	if expected := "[vostok] 012345678901234\n[vostok] é\n"; out.String() != expected {
+		t.Errorf("Multi-byte character should not have been cut, got %q!", out.String())
+	}
+
+	// case 4: uses a line longer than the buffer without limit.
+	out.Reset()
+	w.size = 0
+	w.Write([]byte(strings.Repeat("a", 64)))
+	w.flush()
+	if expected := "[vostok] " + strings.Repeat("a", 64) + "\n"; out.String() != expected {
+		t.Errorf("Line should not have been written in parts without limit, got %q!", out.String())
+	}
+}
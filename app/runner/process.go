@@ -0,0 +1,94 @@
+//go:build !windows
+// +build !windows
+
+package runner
+
+import (
+	gocontext "context"
+	"os"
+	"os/exec"
+	"syscall"
+	"time"
+)
+
+/*
+runProcess runs the given command in its own process group, and makes the cancellation of the given context
+terminate the whole group: the processes started by the shell are not left orphaned. A command whose input
+is a terminal remains in the foreground process group so that it may still read from it: only its process
+is then signaled.
+
+The command receives SIGTERM, then SIGKILL once the given grace period has elapsed.
+If the grace period is zero, it receives SIGKILL at once. Once the command has exited,
+the pending SIGKILL is stopped: the identifier of the group could otherwise be reused by another process.
+*/
+func runProcess(ctx gocontext.Context, e *exec.Cmd, grace time.Duration) error {
+	_, interactive := terminalFd(e.Stdin)
+	if !interactive {
+		if e.SysProcAttr == nil {
+			e.SysProcAttr = &syscall.SysProcAttr{}
+		}
+
+		e.SysProcAttr.Setpgid = true
+	}
+
+	// like exec.CommandContext, a command is not started if its context is already done.
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	if err := e.Start(); err != nil {
+		return err
+	}
+
+	kill := func(sig syscall.Signal) {
+		if interactive {
+			e.Process.Signal(sig)
+			return
+		}
+
+		syscall.Kill(-e.Process.Pid, sig)
+	}
+
+	exited := make(chan struct{})
+	terminated := make(chan struct{})
+	go func() {
+		defer close(terminated)
+
+		select {
+		case <-exited:
+			return
+		case <-ctx.Done():
+		}
+
+		if grace <= 0 {
+			kill(syscall.SIGKILL)
+			return
+		}
+
+		kill(syscall.SIGTERM)
+
+		timer := time.NewTimer(grace)
+		defer timer.Stop()
+
+		select {
+		case <-exited:
+		case <-timer.C:
+			kill(syscall.SIGKILL)
+		}
+	}()
+
+	err := e.Wait()
+	close(exited)
+	<-terminated
+
+	return err
+}
+
+// exitStatus returns the exit code of the given exited process, or -1 if it has been killed by a signal.
+func exitStatus(state *os.ProcessState) int {
+	if status, ok := state.Sys().(syscall.WaitStatus); ok {
+		return status.ExitStatus()
+	}
+
+	return -1
+}
@@ -0,0 +1,45 @@
+package runner
+
+import (
+	gocontext "context"
+	"os"
+	"os/exec"
+	"syscall"
+	"time"
+)
+
+// runProcess runs the given command, which is killed at once if the given context is done:
+// there is no termination signal on Windows, so the grace period is ignored.
+func runProcess(ctx gocontext.Context, e *exec.Cmd, grace time.Duration) error {
+	// like exec.CommandContext, a command is not started if its context is already done.
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	if err := e.Start(); err != nil {
+		return err
+	}
+
+	exited := make(chan struct{})
+	go func() {
+		select {
+		case <-exited:
+		case <-ctx.Done():
+			e.Process.Kill()
+		}
+	}()
+
+	err := e.Wait()
+	close(exited)
+
+	return err
+}
+
+// exitStatus returns the exit code of the given exited process: a killed process has the exit code given by Kill.
+func exitStatus(state *os.ProcessState) int {
+	if status, ok := state.Sys().(syscall.WaitStatus); ok {
+		return status.ExitStatus()
+	}
+
+	return -1
+}
@@ -0,0 +1,18 @@
+package runner
+
+import (
+	"fmt"
+	"io"
+	"time"
+)
+
+// ProfilePhase prints the duration of the given startup phase since the given start time into
+// the given writer, if it's not nil. Returns the current time, which is the start time of the next phase.
+func ProfilePhase(w io.Writer, phase string, start time.Time) time.Time {
+	now := time.Now()
+	if w != nil {
+		fmt.Fprintf(w, "startup: %s took %s\n", phase, now.Sub(start))
+	}
+
+	return now
+}
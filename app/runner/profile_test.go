@@ -0,0 +1,34 @@
+package runner
+
+import (
+	"bytes"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/gulien/orbit/app/context"
+)
+
+// Tests if the durations of the startup phases are printed.
+func TestProfilePhase(t *testing.T) {
+	var buf bytes.Buffer
+
+	templateFilePath, _ := filepath.Abs("../../_tests/orbit.yml")
+	ctx, _ := context.NewOrbitContext(templateFilePath, "", "")
+
+	// case 1: uses the default behavior.
+	NewOrbitRunner(ctx)
+	if buf.Len() != 0 {
+		t.Error("Startup phases should not have been printed!")
+	}
+
+	// case 2: uses the startup profile.
+	ctx.Settings.ProfileOutput = &buf
+
+	NewOrbitRunner(ctx)
+	for _, phase := range []string{"generator execute", "unmarshal", "run files", "local configuration", "validation"} {
+		if !strings.Contains(buf.String(), "startup: "+phase+" took ") {
+			t.Errorf("Startup phase %s should have been printed, got %q!", phase, buf.String())
+		}
+	}
+}
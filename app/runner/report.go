@@ -0,0 +1,139 @@
+package runner
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io/ioutil"
+	"strings"
+	"time"
+
+	OrbitError "github.com/gulien/orbit/app/error"
+	"github.com/gulien/orbit/app/logger"
+)
+
+// JUnitReport is the JUnit XML report format.
+const JUnitReport = "junit"
+
+type (
+	// orbitResult represents a task which has been run.
+	orbitResult struct {
+		// task is the task which has been run.
+		task *orbitTask
+
+		// start is the time at which the task has started.
+		start time.Time
+
+		// duration is the time spent running the task, including the tasks it calls.
+		duration time.Duration
+
+		// err is the error of the task, nil if it has succeeded.
+		err error
+
+		// failures contains the commands of this run of the task which have failed.
+		failures []*orbitFailure
+	}
+
+	// junitTestSuites is the root element of a JUnit XML report.
+	junitTestSuites struct {
+		XMLName xml.Name         `xml:"testsuites"`
+		Suites  []junitTestSuite `xml:"testsuite"`
+	}
+
+	// junitTestSuite contains the tasks which have been run.
+	junitTestSuite struct {
+		Name     string          `xml:"name,attr"`
+		Tests    int             `xml:"tests,attr"`
+		Failures int             `xml:"failures,attr"`
+		Time     string          `xml:"time,attr"`
+		Cases    []junitTestCase `xml:"testcase"`
+	}
+
+	// junitTestCase represents a task which has been run.
+	junitTestCase struct {
+		Name      string        `xml:"name,attr"`
+		ClassName string        `xml:"classname,attr"`
+		Time      string        `xml:"time,attr"`
+		Failure   *junitFailure `xml:"failure,omitempty"`
+	}
+
+	// junitFailure contains the details of a task which has failed.
+	junitFailure struct {
+		Message string `xml:"message,attr"`
+		Details string `xml:",chardata"`
+	}
+)
+
+// recordResult keeps track of the given run of a task, which has ended.
+func (r *OrbitRunner) recordResult(result *orbitResult, start time.Time, elapsed time.Duration, err error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	result.start, result.duration, result.err = start, elapsed, err
+	r.results = append(r.results, result)
+}
+
+/*
+WriteJUnitReport writes the tasks which have been run into the given file, as a JUnit XML report.
+
+Each task is a test case, including the tasks called by others tasks: the details of a failed task
+contain its failed commands. The time of the test suite is the time elapsed from the start of the first task
+to the end of the last one.
+*/
+func (r *OrbitRunner) WriteJUnitReport(filePath string) error {
+	suite := junitTestSuite{Name: "orbit"}
+
+	var first, last time.Time
+	for _, result := range r.results {
+		if first.IsZero() || result.start.Before(first) {
+			first = result.start
+		}
+
+		if end := result.start.Add(result.duration); end.After(last) {
+			last = end
+		}
+
+		testCase := junitTestCase{
+			Name:      result.task.Use,
+			ClassName: "orbit",
+			Time:      fmt.Sprintf("%.3f", result.duration.Seconds()),
+		}
+
+		if result.err != nil {
+			testCase.Failure = &junitFailure{
+				Message: result.err.Error(),
+				Details: failureDetails(result),
+			}
+
+			suite.Failures++
+		}
+
+		suite.Cases = append(suite.Cases, testCase)
+	}
+
+	suite.Tests = len(suite.Cases)
+	suite.Time = fmt.Sprintf("%.3f", last.Sub(first).Seconds())
+
+	data, err := xml.MarshalIndent(junitTestSuites{Suites: []junitTestSuite{suite}}, "", "  ")
+	if err != nil {
+		return OrbitError.NewOrbitErrorf("unable to encode the JUnit report. Details:\n%s", err)
+	}
+
+	data = append([]byte(xml.Header), data...)
+	if err := ioutil.WriteFile(filePath, append(data, '\n'), 0644); err != nil {
+		return OrbitError.NewOrbitErrorf("unable to write the JUnit report file %s. Details:\n%s", filePath, err)
+	}
+
+	logger.Infof("JUnit report file %s has been created", filePath)
+
+	return nil
+}
+
+// failureDetails returns the failed commands of the given run of a task, one per line.
+func failureDetails(result *orbitResult) string {
+	var details []string
+	for _, failure := range result.failures {
+		details = append(details, fmt.Sprintf("command %s has failed with exit code %d after %.3fs", failure.Command, failure.ExitCode, failure.Duration))
+	}
+
+	return strings.Join(details, "\n")
+}
@@ -0,0 +1,53 @@
+package runner
+
+import (
+	"encoding/xml"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/gulien/orbit/app/context"
+)
+
+// Tests if the JUnit report contains the tasks which have been run.
+func TestWriteJUnitReport(t *testing.T) {
+	templateFilePath, _ := filepath.Abs("../../_tests/orbit.yml")
+	ctx, _ := context.NewOrbitContext(templateFilePath, "", "")
+	r, _ := NewOrbitRunner(ctx)
+
+	r.Run("new shepard")
+	r.Run("challenger")
+
+	filePath := filepath.Join(os.TempDir(), "orbit-junit.xml")
+	defer os.Remove(filePath)
+
+	if err := r.WriteJUnitReport(filePath); err != nil {
+		t.Error("JUnit report should have been written!")
+	}
+
+	data, _ := ioutil.ReadFile(filePath)
+
+	var report junitTestSuites
+	if err := xml.Unmarshal(data, &report); err != nil || len(report.Suites) != 1 {
+		t.Fatalf("JUnit report should have been a valid XML file, got %s!", data)
+	}
+
+	// case 1: uses the tasks which have been run, including the called tasks.
+	suite := report.Suites[0]
+	if suite.Tests != 4 || suite.Failures != 1 {
+		t.Errorf("JUnit report should have contained 4 tests with 1 failure, got %d tests with %d failures!", suite.Tests, suite.Failures)
+	}
+
+	// case 2: uses the failed task.
+	failure := suite.Cases[3].Failure
+	if suite.Cases[3].Name != "challenger" || failure == nil || !strings.Contains(failure.Details, `failecho "I am challenger task"`) {
+		t.Errorf("JUnit report should have contained the failed command of task challenger, got %s!", data)
+	}
+
+	// case 3: uses a succeeding task.
+	if suite.Cases[0].Failure != nil {
+		t.Error("JUnit report should not have contained a failure for a succeeding task!")
+	}
+}
@@ -0,0 +1,91 @@
+package runner
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strings"
+
+	OrbitError "github.com/gulien/orbit/app/error"
+
+	"golang.org/x/crypto/ssh/terminal"
+)
+
+// secretEnvRegexp matches the names of the variables whose values are not echoed when prompted.
+var secretEnvRegexp = regexp.MustCompile(`(?i)(SECRET|TOKEN|PASS|KEY|CREDENTIAL)`)
+
+/*
+resolveRequiredEnv verifies that each variable required by the given task is defined,
+either in the environment of Orbit or in the given variables from the env files.
+
+If a variable is missing and InteractiveEnv is true, the user is asked for its value when
+the prompt is a terminal: the values of the secret-like variables are read without echo.
+Returns the given variables with the prompted ones.
+*/
+func (r *OrbitRunner) resolveRequiredEnv(task *orbitTask, env []string) ([]string, error) {
+	for _, name := range task.RequiresEnv {
+		if lookupEnv(name, env) != "" {
+			continue
+		}
+
+		fd, isTerminal := terminalFd(r.Stdin)
+		if !r.InteractiveEnv || !isTerminal {
+			return nil, OrbitError.NewOrbitErrorf("task %s requires the environment variable %s", task.Use, name)
+		}
+
+		value, err := promptEnv(name, task, r.Stdin, r.Stderr, fd)
+		if err != nil {
+			return nil, err
+		}
+
+		env = append(env, fmt.Sprintf("%s=%s", name, value))
+	}
+
+	return env, nil
+}
+
+// lookupEnv returns the value of the given variable from the given variables, or else from the environment.
+func lookupEnv(name string, env []string) string {
+	for index := len(env) - 1; index >= 0; index-- {
+		if strings.HasPrefix(env[index], name+"=") {
+			return strings.TrimPrefix(env[index], name+"=")
+		}
+	}
+
+	return os.Getenv(name)
+}
+
+// terminalFd returns the file descriptor of the given reader and true if it's a terminal.
+func terminalFd(reader io.Reader) (int, bool) {
+	file, ok := reader.(*os.File)
+	if !ok {
+		return 0, false
+	}
+
+	fd := int(file.Fd())
+
+	return fd, terminal.IsTerminal(fd)
+}
+
+// promptEnv asks the user, through the given writer, for the value of the given variable required by the given task.
+func promptEnv(name string, task *orbitTask, reader io.Reader, w io.Writer, fd int) (string, error) {
+	fmt.Fprintf(w, "task %s requires the environment variable %s: ", task.Use, name)
+
+	if secretEnvRegexp.MatchString(name) {
+		value, err := terminal.ReadPassword(fd)
+		fmt.Fprintln(w)
+		if err != nil {
+			return "", OrbitError.NewOrbitErrorf("unable to read the environment variable %s of task %s. Details:\n%s", name, task.Use, err)
+		}
+
+		return string(value), nil
+	}
+
+	value, err := readLine(reader)
+	if err != nil && err != io.EOF {
+		return "", OrbitError.NewOrbitErrorf("unable to read the environment variable %s of task %s. Details:\n%s", name, task.Use, err)
+	}
+
+	return strings.TrimSpace(value), nil
+}
@@ -0,0 +1,33 @@
+package runner
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/gulien/orbit/app/context"
+)
+
+// Tests if the variables required by a task are verified.
+func TestResolveRequiredEnv(t *testing.T) {
+	templateFilePath, _ := filepath.Abs("../../_tests/orbit-requires-env.yml")
+	ctx, _ := context.NewOrbitContext(templateFilePath, "", "")
+	r, _ := NewOrbitRunner(ctx)
+
+	// case 1: uses variables from an env file and from the environment.
+	if err := r.Run("explorer"); err != nil {
+		t.Error("Task with defined required variables should have been run!")
+	}
+
+	// case 2: uses a missing variable.
+	if err := r.Run("sputnik"); err == nil {
+		t.Error("Task with a missing required variable should not have been run!")
+	}
+
+	// case 3: uses a missing variable with a prompt which is not a terminal.
+	r.InteractiveEnv = true
+	r.Stdin = strings.NewReader("Vostok\n")
+	if err := r.Run("sputnik"); err == nil {
+		t.Error("Missing required variable should not have been prompted outside a terminal!")
+	}
+}
@@ -0,0 +1,68 @@
+package runner
+
+import (
+	"time"
+)
+
+type (
+	// TaskResult represents a task which has been run.
+	TaskResult struct {
+		// Task is the name of the task.
+		Task string
+
+		// Start is the time at which the task has started.
+		Start time.Time
+
+		// Duration is the time spent running the task, including the tasks it calls.
+		Duration time.Duration
+
+		// Err is the error of the task, nil if it has succeeded.
+		Err error
+
+		// Failures contains the commands of the task which have failed.
+		Failures []CommandFailure
+	}
+
+	// CommandFailure represents a command which has failed.
+	CommandFailure struct {
+		// Command is the command as defined in the configuration file.
+		Command string
+
+		// ExitCode is the exit code of the command, or -1 if it has not been started.
+		ExitCode int
+
+		// Duration is the time spent running the command.
+		Duration time.Duration
+	}
+)
+
+/*
+Results returns the tasks which have been run since the instantiation of the runner, including the tasks
+called by others tasks, in the order they have ended.
+
+The tasks skipped by a condition or because their files have not changed are not returned, nor the ones of a dry run.
+*/
+func (r *OrbitRunner) Results() []TaskResult {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	results := make([]TaskResult, len(r.results))
+	for index, result := range r.results {
+		results[index] = TaskResult{
+			Task:     result.task.Use,
+			Start:    result.start,
+			Duration: result.duration,
+			Err:      result.err,
+		}
+
+		for _, failure := range result.failures {
+			results[index].Failures = append(results[index].Failures, CommandFailure{
+				Command:  failure.Command,
+				ExitCode: failure.ExitCode,
+				Duration: time.Duration(failure.Duration * float64(time.Second)),
+			})
+		}
+	}
+
+	return results
+}
@@ -0,0 +1,53 @@
+package runner
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+	"text/template"
+
+	"github.com/gulien/orbit/app/context"
+)
+
+// Tests if the results of the tasks which have been run are returned with their failed commands.
+func TestResults(t *testing.T) {
+	templateFilePath, _ := filepath.Abs("../../_tests/orbit-embed.yml")
+	ctx, _ := context.NewOrbitContext(templateFilePath, "", "")
+	ctx.Payload["name"] = "orbit"
+	ctx.Funcs = template.FuncMap{"shout": strings.ToUpper}
+
+	r, err := NewOrbitRunner(ctx)
+	if err != nil {
+		t.Fatalf("Runner should have been instantiated with the functions of the context, got %s!", err)
+	}
+
+	r.Stdout = ioutil.Discard
+
+	// case 1: uses a runner which has not run any task.
+	if results := r.Results(); len(results) != 0 {
+		t.Errorf("No result should have been returned, got %v!", results)
+	}
+
+	// case 2: uses a task calling another task then failing.
+	r.Run("launch")
+
+	results := r.Results()
+	if len(results) != 2 || results[0].Task != "greet" || results[0].Err != nil || len(results[0].Failures) != 0 {
+		t.Fatalf("Called task should have been returned first without failure, got %v!", results)
+	}
+
+	if results[1].Task != "launch" || results[1].Err == nil || len(results[1].Failures) != 1 || results[1].Failures[0].Command != "exit 3" {
+		t.Errorf("Failed task should have been returned with its failed command, got %v!", results[1])
+	}
+
+	// case 3: uses the same task run twice.
+	r.Run("launch")
+
+	results = r.Results()
+	for _, index := range []int{1, 3} {
+		if results[index].Task != "launch" || len(results[index].Failures) != 1 {
+			t.Errorf("Each run of the task should have been returned with its own failed command, got %v!", results[index])
+		}
+	}
+}
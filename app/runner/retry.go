@@ -0,0 +1,66 @@
+package runner
+
+import (
+	"time"
+
+	"github.com/gulien/orbit/app/logger"
+)
+
+const (
+	// constantBackoff waits the same delay before each attempt.
+	constantBackoff = "constant"
+
+	// exponentialBackoff doubles the delay after each attempt.
+	exponentialBackoff = "exponential"
+)
+
+// orbitRetry represents the policy retrying the commands of a task as defined in the configuration file.
+type orbitRetry struct {
+	// Attempts is the maximum number of executions of a command, including the first one.
+	Attempts int `yaml:"attempts"`
+
+	// Delay is the duration to wait before the second attempt (e.g. 5s).
+	Delay time.Duration `yaml:"delay,omitempty"`
+
+	// Backoff is how the delay evolves between the attempts, either constant (default) or exponential.
+	Backoff string `yaml:"backoff,omitempty"`
+}
+
+// delay returns the duration to wait before the given attempt, starting at 2.
+func (retry *orbitRetry) delay(attempt int) time.Duration {
+	if retry.Backoff != exponentialBackoff {
+		return retry.Delay
+	}
+
+	return retry.Delay << uint(attempt-2)
+}
+
+/*
+executeWithRetry executes the given command from the given running task like execute does.
+
+If the task has a retry policy, a failing command is executed again until it succeeds
+or its number of attempts is reached. A command cancelled with its task is never retried.
+*/
+func (r *OrbitRunner) executeWithRetry(cmd string, state *orbitTaskState, environ []string) ([]string, error) {
+	result, err := r.execute(cmd, state, environ)
+	if state.task.Retry == nil {
+		return result, err
+	}
+
+	for attempt := 2; err != nil && attempt <= state.task.Retry.Attempts; attempt++ {
+		delay := state.task.Retry.delay(attempt)
+		logger.Warnf("command %s from task %s has failed, retrying in %s (attempt %d of %d). Details:\n%s", cmd, state.task.Use, delay, attempt, state.task.Retry.Attempts, err)
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-state.ctx.Done():
+			timer.Stop()
+			return result, err
+		case <-timer.C:
+		}
+
+		result, err = r.execute(cmd, state, environ)
+	}
+
+	return result, err
+}
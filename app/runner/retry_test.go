@@ -0,0 +1,83 @@
+package runner
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gulien/orbit/app/context"
+)
+
+// Tests if the failing commands are retried according to the retry policy of their task.
+func TestRetry(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("skipping test on Windows")
+	}
+
+	templateFilePath, _ := filepath.Abs("../../_tests/orbit-retry.yml")
+	ctx, _ := context.NewOrbitContext(templateFilePath, "", "")
+	r, _ := NewOrbitRunner(ctx)
+	defer os.Remove("attempts.txt")
+
+	// case 1: uses a command succeeding at its last attempt.
+	if err := r.Run("explorer"); err != nil {
+		t.Errorf("Command should have succeeded at its last attempt, got %s!", err)
+	}
+
+	if len(r.failures) != 0 {
+		t.Errorf("Failed attempts of a command which has succeeded should not have been recorded, got %d!", len(r.failures))
+	}
+
+	// case 2: uses a command failing at all its attempts.
+	if err := r.Run("sputnik"); err == nil {
+		t.Error("Command failing at all its attempts should have stopped the task!")
+	}
+
+	if len(r.failures) != 1 {
+		t.Errorf("Command failing at all its attempts should have been recorded once, got %d!", len(r.failures))
+	}
+
+	// case 3: uses a retry policy with invalid attempts and backoff.
+	if problems := r.Check(); len(problems) != 2 {
+		t.Errorf("Check should have reported 2 problems, got %d!", len(problems))
+	}
+}
+
+// Tests if the delay between the attempts follows the backoff of the retry policy.
+func TestRetryDelay(t *testing.T) {
+	// case 1: uses a constant backoff.
+	retry := &orbitRetry{Attempts: 3, Delay: time.Second}
+	if retry.delay(2) != time.Second || retry.delay(3) != time.Second {
+		t.Error("Constant backoff should have kept the same delay!")
+	}
+
+	// case 2: uses an exponential backoff.
+	retry.Backoff = exponentialBackoff
+	if retry.delay(2) != time.Second || retry.delay(4) != 4*time.Second {
+		t.Error("Exponential backoff should have doubled the delay after each attempt!")
+	}
+}
+
+// Tests if a task continuing on error executes all its commands before failing.
+func TestContinueOnError(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("skipping test on Windows")
+	}
+
+	templateFilePath, _ := filepath.Abs("../../_tests/orbit-retry.yml")
+	ctx, _ := context.NewOrbitContext(templateFilePath, "", "")
+	r, _ := NewOrbitRunner(ctx)
+	defer os.Remove("vostok.txt")
+
+	err := r.Run("vostok")
+	if err == nil || !strings.Contains(err.Error(), "2 command(s) from task vostok have failed") {
+		t.Errorf("Task continuing on error should have failed with the aggregated failures, got %v!", err)
+	}
+
+	if _, err := os.Stat("vostok.txt"); err != nil {
+		t.Error("Commands after a failure should have been executed!")
+	}
+}
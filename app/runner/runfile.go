@@ -0,0 +1,49 @@
+package runner
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+
+	OrbitError "github.com/gulien/orbit/app/error"
+)
+
+/*
+resolveRunFiles populates the stack of commands of each task having a run_file
+attribute with the lines of this file.
+
+The path of the file is relative to the given base directory, i.e. the directory of
+the configuration file or the current directory if it's a remote file. Each line is a command:
+empty lines and lines beginning with # are ignored. The commands are appended
+to the commands from the run attribute. Returns a problem for each run file which cannot be read.
+*/
+func resolveRunFiles(config *orbitRunnerConfig, baseDir string) []error {
+	var problems []error
+	for _, task := range config.Tasks {
+		if task.RunFile == "" {
+			continue
+		}
+
+		filePath := task.RunFile
+		if !filepath.IsAbs(filePath) {
+			filePath = filepath.Join(baseDir, filePath)
+		}
+
+		data, err := ioutil.ReadFile(filePath)
+		if err != nil {
+			problems = append(problems, OrbitError.NewOrbitErrorf("unable to read the run file %s of task %s. Details:\n%s", filePath, task.Use, err))
+			continue
+		}
+
+		for _, line := range strings.Split(string(data), "\n") {
+			line = strings.TrimSpace(line)
+			if line == "" || strings.HasPrefix(line, "#") {
+				continue
+			}
+
+			task.Run = append(task.Run, orbitCommand{Command: line})
+		}
+	}
+
+	return problems
+}
@@ -0,0 +1,39 @@
+package runner
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// Tests if the commands are read from the run files.
+func TestResolveRunFiles(t *testing.T) {
+	templateFilePath, _ := filepath.Abs("../../_tests/orbit-run-file.yml")
+
+	// case 1: uses a correct run file.
+	config := &orbitRunnerConfig{
+		Tasks: []*orbitTask{
+			{Use: "explorer", Run: plainCommands([]string{`echo "I am explorer task"`}), RunFile: "run-file.sh"},
+		},
+	}
+
+	if problems := resolveRunFiles(config, filepath.Dir(templateFilePath)); len(problems) > 0 {
+		t.Fatal("Run file should have been read!")
+	}
+
+	expected := plainCommands([]string{
+		`echo "I am explorer task"`,
+		`echo "I am the first command from run-file.sh"`,
+		`echo "I am the second command from run-file.sh"`,
+	})
+
+	if !reflect.DeepEqual(config.Tasks[0].Run, expected) {
+		t.Errorf("Commands should have been read from the run file, got %v!", config.Tasks[0].Run)
+	}
+
+	// case 2: uses a non existing run file.
+	config.Tasks[0].RunFile = "non-existing-run-file.sh"
+	if problems := resolveRunFiles(config, filepath.Dir(templateFilePath)); len(problems) != 1 {
+		t.Error("Non existing run file should not have been read!")
+	}
+}
@@ -10,17 +10,20 @@ Thanks to the generator package, the configuration file may be a data-driven tem
 package runner
 
 import (
+	gocontext "context"
 	"fmt"
+	"io"
 	"os"
 	"os/exec"
 	"regexp"
 	"runtime"
+	"sort"
 	"strings"
-	"text/tabwriter"
+	"sync"
+	"time"
 
 	"github.com/gulien/orbit/app/context"
 	OrbitError "github.com/gulien/orbit/app/error"
-	"github.com/gulien/orbit/app/generator"
 	"github.com/gulien/orbit/app/logger"
 
 	"gopkg.in/yaml.v2"
@@ -29,11 +32,65 @@ import (
 const defaultWindowsShellEnvVariable = "COMSPEC"
 const defaultPosixShellEnvVariable = "SHELL"
 
+const (
+	// SortByOrder sorts the tasks by their order of declaration.
+	SortByOrder = "order"
+
+	// SortByName sorts the tasks alphabetically.
+	SortByName = "name"
+)
+
+// DefaultEnvPrefix is the default prefix of the environment variables injected by Orbit.
+const DefaultEnvPrefix = "ORBIT_"
+
+// DefaultGracePeriod is the default duration between the termination signal sent to a cancelled command and its kill.
+const DefaultGracePeriod = 5 * time.Second
+
+// DefaultOutputBufferSize is the default maximum size of an incomplete line of a prefixed output.
+const DefaultOutputBufferSize = 64 * 1024
+
 type (
 	// orbitRunnerConfig represents a YAML configuration file defining tasks.
 	orbitRunnerConfig struct {
 		// Tasks array represents the tasks defined in the configuration file.
 		Tasks []*orbitTask `yaml:"tasks"`
+
+		// Dotenv contains the paths of the .env files, relative to the configuration file, whose variables
+		// are added to the environment of the commands and are accessible in the configuration file through {{ .Env }}.
+		Dotenv []string `yaml:"dotenv,omitempty"`
+
+		// Env contains the variables which are added to the environment of the commands of all the tasks.
+		// The env files and the env attribute of a task win over them.
+		Env map[string]string `yaml:"env,omitempty"`
+
+		// Default is the task run when no task is given.
+		Default *orbitDefault `yaml:"default,omitempty"`
+
+		// Before is the stack of commands to execute before the commands of each top level task.
+		Before []string `yaml:"before,omitempty"`
+
+		// After is the stack of commands to execute once each top level task has ended, whatever its result.
+		After []string `yaml:"after,omitempty"`
+
+		// OnFailure is the stack of commands to execute if a top level task has failed.
+		OnFailure []string `yaml:"on_failure,omitempty"`
+
+		// Includes contains the configuration files whose tasks are added to the configuration.
+		Includes []*orbitInclude `yaml:"includes,omitempty"`
+
+		// Groups contains named and ordered lists of tasks.
+		Groups map[string][]string `yaml:"groups,omitempty"`
+
+		// Notify is the webhook configuration used by the notify commands.
+		Notify *orbitNotifyConfig `yaml:"notify,omitempty"`
+
+		// History enables the recording of the durations of the tasks
+		// in the cache directory, next to the configuration file.
+		History bool `yaml:"history,omitempty"`
+
+		// ConfirmDestructive contains the regular expressions matching the commands
+		// which require a confirmation of the user before being executed.
+		ConfirmDestructive []string `yaml:"confirm_destructive,omitempty"`
 	}
 
 	// orbitTask represents a task as defined in the configuration file.
@@ -41,10 +98,22 @@ type (
 		// Use is the name of the task.
 		Use string `yaml:"use"`
 
+		// OS contains the platforms on which the task may be run (e.g. linux, darwin or windows).
+		// If empty, the task may be run on any platform.
+		OS []string `yaml:"os,omitempty"`
+
+		// When is the condition which has to be true to run the task (e.g. {{ eq os "linux" }}).
+		// If nil, the task is always run.
+		When *string `yaml:"when,omitempty"`
+
 		// Shell allows to choose which binary will
 		// be called to run the commands.
 		Shell string `yaml:"shell,omitempty"`
 
+		// Executor allows to run the commands inside a Docker container or on a remote host over SSH.
+		// If nil, they are run through a local shell.
+		Executor *orbitExecutorConfig `yaml:"executor,omitempty"`
+
 		// Short is the short description of the task.
 		Short string `yaml:"short,omitempty"`
 
@@ -52,8 +121,131 @@ type (
 		// printing the available tasks.
 		Private bool `yaml:"private,omitempty"`
 
-		// Run is the stack of commands to execute.
-		Run []string `yaml:"run"`
+		// Args contains the names of the arguments of the task. If not empty, the task must
+		// receive exactly one value for each of them, given as the environment variable <EnvPrefix>ARG_<NAME>
+		// (e.g. ORBIT_ARG_VERSION) and, with a POSIX shell, as a positional parameter ($1, $2, etc.).
+		// As the configuration file is executed before running any task, the values are not template data.
+		Args []string `yaml:"args,omitempty"`
+
+		// Deps contains the names of the tasks to run before the commands of the task.
+		// Each of them is run at most once per call to Run.
+		Deps []string `yaml:"deps,omitempty"`
+
+		// Run is the stack of commands to execute. Some of them may be grouped
+		// in order to be executed in parallel.
+		Run orbitCommands `yaml:"run"`
+
+		// RunFile is the path of a file, relative to the configuration file,
+		// from which each line is a command to execute after the commands from Run.
+		RunFile string `yaml:"run_file,omitempty"`
+
+		// IgnoreErrors allows to continue the task when one of its commands fails.
+		// A command may also be prefixed by "-" to ignore its failure.
+		// The boolean attributes are pointers, so that an extending task may set them back to false.
+		IgnoreErrors *bool `yaml:"ignore_errors,omitempty"`
+
+		// ContinueOnError allows to execute the remaining commands when one of the commands fails.
+		// Unlike IgnoreErrors, the task still fails once all its commands have been executed.
+		ContinueOnError *bool `yaml:"continue_on_error,omitempty"`
+
+		// Retry is the policy retrying the commands which fail.
+		Retry *orbitRetry `yaml:"retry,omitempty"`
+
+		// Parallel allows to execute the commands from Run in parallel.
+		// The calls to others tasks and the notifications remain sequential.
+		Parallel *bool `yaml:"parallel,omitempty"`
+
+		// Before is the stack of commands to execute before the commands from Run.
+		// If one of them fails, the task fails without executing the commands from Run.
+		Before []string `yaml:"before,omitempty"`
+
+		// After is the stack of commands to execute once the task has ended, whatever its result.
+		After []string `yaml:"after,omitempty"`
+
+		// OnFailure is an alias of AfterFailure: its commands are executed after the ones from AfterFailure.
+		OnFailure []string `yaml:"on_failure,omitempty"`
+
+		// AfterSuccess is the stack of commands to execute
+		// once all the commands from Run have succeeded.
+		AfterSuccess []string `yaml:"after_success,omitempty"`
+
+		// AfterFailure is the stack of commands to execute
+		// if a command from Run has failed.
+		AfterFailure []string `yaml:"after_failure,omitempty"`
+
+		// EnvFiles contains the paths of the .env files, relative to the configuration file,
+		// from which the variables are added to the environment of the commands.
+		EnvFiles []string `yaml:"env_files,omitempty"`
+
+		// Env contains the variables which are added to the environment of the commands.
+		// They win over the variables from the env files and the environment of Orbit.
+		Env map[string]string `yaml:"env,omitempty"`
+
+		// RequiresEnv contains the names of the environment variables
+		// which must be defined to run the task.
+		RequiresEnv []string `yaml:"requires_env,omitempty"`
+
+		// Mkdir contains the paths of the directories, relative to the configuration file,
+		// which are created (with their parents) before running the commands.
+		Mkdir []string `yaml:"mkdir,omitempty"`
+
+		// Dir is the path of the working directory, relative to the configuration file,
+		// of the commands. If empty, they are run in the current directory.
+		Dir string `yaml:"dir,omitempty"`
+
+		// Stdin is the path of the file, relative to the configuration file,
+		// which is given as standard input to the commands.
+		Stdin string `yaml:"stdin,omitempty"`
+
+		// Stdout is the path of the file, relative to the configuration file,
+		// which receives the standard output of the commands.
+		Stdout string `yaml:"stdout,omitempty"`
+
+		// Stderr is the path of the file, relative to the configuration file,
+		// which receives the standard error of the commands.
+		Stderr string `yaml:"stderr,omitempty"`
+
+		// Echo allows to also print to the console the outputs
+		// redirected to files.
+		Echo *bool `yaml:"echo,omitempty"`
+
+		// Sources contains the glob patterns, relative to the configuration file,
+		// of the files the task depends on.
+		Sources []string `yaml:"sources,omitempty"`
+
+		// Generates contains the glob patterns, relative to the configuration file,
+		// of the files generated by the task. If not empty, the task is skipped
+		// as long as these files and the ones from Sources remain unchanged.
+		Generates []string `yaml:"generates,omitempty"`
+
+		// RunIfChanged contains the glob patterns, relative to the configuration file,
+		// of the files which must have been modified since the last successful run to run the task.
+		RunIfChanged []string `yaml:"run_if_changed,omitempty"`
+
+		// DiffPrevious allows to print the differences between the standard output
+		// of the commands and the one from the previous run.
+		DiffPrevious *bool `yaml:"diff_previous,omitempty"`
+
+		// Timeout is the maximum duration of each command of the task (e.g. 30s).
+		Timeout time.Duration `yaml:"timeout,omitempty"`
+
+		// TaskTimeout is the maximum duration of the task, covering all its commands
+		// and the tasks it calls (e.g. 10m).
+		TaskTimeout time.Duration `yaml:"task_timeout,omitempty"`
+
+		// Watch contains the files whose changes run the task again with the watch command.
+		Watch *orbitWatch `yaml:"watch,omitempty"`
+
+		// Sentinel is the file whose creation (or deletion) aborts the task.
+		Sentinel *orbitSentinel `yaml:"sentinel,omitempty"`
+
+		// Extends is the name of the task from which
+		// the attributes are inherited.
+		Extends string `yaml:"extends,omitempty"`
+
+		// Merge allows to choose if the lists of commands
+		// from the extended task are replaced (default) or appended.
+		Merge string `yaml:"merge,omitempty"`
 	}
 
 	// OrbitRunner helps executing tasks.
@@ -63,57 +255,387 @@ type (
 
 		// context is an instance of OrbitContext.
 		context *context.OrbitContext
+
+		// EnvPrefix is the prefix applied to the environment
+		// variables injected by Orbit in the commands.
+		EnvPrefix string
+
+		// WorkingEnv enables, on POSIX systems, the capture of the environment
+		// exported by a command in order to carry it into the next command of the same task.
+		WorkingEnv bool
+
+		// DurationThreshold is the duration above which a command is reported as slow.
+		// If zero, no command is reported.
+		DurationThreshold time.Duration
+
+		// Sort is the order of the printed tasks, either SortByOrder (default) or SortByName.
+		Sort string
+
+		// Depth is the number of namespace levels (separated by ":") displayed
+		// when printing the tasks. If zero, all the tasks are printed.
+		Depth int
+
+		// Format is the format of the printed tasks, either TableFormat, PlainFormat, CSVFormat, JSONFormat or YAMLFormat.
+		// With DryRun, the commands are printed as JSON objects with JSONFormat.
+		Format string
+
+		// ShowCommands allows to print the commands of each task with the tasks
+		// in the table and plain formats.
+		ShowCommands bool
+
+		// MaxWorkers is the maximum number of commands executed in parallel.
+		// If zero or negative, there is no limit.
+		MaxWorkers int
+
+		// PrefixOutput allows to prefix each line of the outputs of the commands by the name
+		// of their task, followed by their index if they are executed in parallel.
+		PrefixOutput bool
+
+		// Timestamps allows to prefix each line of the outputs of the commands by its time.
+		Timestamps bool
+
+		// LogOutput receives a copy of the outputs of the commands, each line being prefixed
+		// by its time and the name of its task. If nil, the outputs are not copied.
+		LogOutput io.Writer
+
+		// DryRun allows to print the commands which would be executed to Stdout, instead of executing them.
+		// The tasks are resolved like in a real run, but nothing is recorded nor notified.
+		DryRun bool
+
+		// Explain allows to detail the steps of a dry run: the text, the working directory and the variables
+		// of each command, the delays of its retries, and the steps which are skipped with the reason why.
+		Explain bool
+
+		// LogTaskOutput allows to send the standard output and error of the commands
+		// to the system logger too, if the logs are sent to it.
+		LogTaskOutput bool
+
+		// Output is the output mode, either DefaultOutput or TeamCityOutput.
+		Output string
+
+		// Yes allows to execute the destructive commands without asking for a confirmation.
+		Yes bool
+
+		// StdinFile is the path of the file which replaces the standard input of Orbit during a call to Run:
+		// it's opened once and shared by the commands of all the tasks, including the dependencies
+		// and the called tasks, which read it one after the other. It takes precedence over the stdin attribute of the tasks.
+		StdinFile string
+
+		// Dir is the working directory of the commands of all the tasks.
+		// It takes precedence over the dir attribute of the tasks.
+		Dir string
+
+		// Force allows to run the tasks having a run_if_changed or a generates attribute
+		// even if none of their files has changed.
+		Force bool
+
+		// InteractiveEnv allows to ask the user for the missing required
+		// environment variables if the standard input is a terminal.
+		InteractiveEnv bool
+
+		// PushgatewayURL is the URL of the Prometheus pushgateway which receives
+		// the metrics of the tasks. If empty, no metric is pushed.
+		PushgatewayURL string
+
+		// destructivePatterns contains the compiled patterns from ConfirmDestructive.
+		destructivePatterns []*regexp.Regexp
+
+		// Stdin is the standard input of the commands, from which the confirmations
+		// and the missing required environment variables are also read.
+		Stdin io.Reader
+
+		// Stdout is the writer of the standard output of the commands and of the printed tasks.
+		Stdout io.Writer
+
+		// Stderr is the writer of the standard error of the commands and of the prompts.
+		Stderr io.Writer
+
+		// RunFunc is called before executing each command, if set. Its modifications of the command
+		// take effect and, if it returns an error, the command is not executed and the task fails.
+		RunFunc func(*exec.Cmd) error
+
+		// Context cancels the commands of the running tasks once done, e.g. when Orbit is interrupted.
+		// If nil, the commands are only cancelled by the timeouts and the sentinels.
+		Context gocontext.Context
+
+		// GracePeriod is the duration between the termination signal sent to a cancelled command
+		// and its kill. If zero, a cancelled command is killed at once.
+		GracePeriod time.Duration
+
+		// OutputBufferSize is the maximum size of an incomplete line kept by the prefixed or timestamped outputs
+		// until it's complete: a longer line is written in parts. If 0, the lines are never split.
+		OutputBufferSize int
+
+		// WatchPoll is the interval at which the files of a watched task are scanned.
+		// If zero, it's the poll attribute of the task or, if not set, 100ms.
+		WatchPoll time.Duration
+
+		// ClearScreen allows to clear the terminal before each run of a watched task but the first one.
+		// It has no effect if Stdout is not a terminal.
+		ClearScreen bool
+
+		// cancelContext is the context cancelling the commands of the running tasks.
+		cancelContext gocontext.Context
+
+		// failures contains the commands which have failed.
+		failures []*orbitFailure
+
+		// mutex protects the confirmations and the failures from the commands executed in parallel.
+		mutex sync.Mutex
+
+		// outputMutex is shared by the prefixed outputs of the commands, so that their lines are not mixed.
+		outputMutex sync.Mutex
+
+		// results contains the tasks which have been run.
+		results []*orbitResult
+
+		// RecordCommands enables the recording of the executed commands, with the tail
+		// of their standard error, in order to write them with WriteJSONReport.
+		RecordCommands bool
+
+		// commands contains the commands which have been executed, if RecordCommands is true.
+		commands []*orbitCommandResult
+
+		// completed contains the names of the tasks which have been run successfully
+		// since the last call to Run.
+		completed map[string]bool
+
+		// callStack contains the names of the tasks being run, from the first caller to the current task.
+		callStack []string
+
+		// stdinFile is the file from StdinFile opened for the current call to Run.
+		stdinFile *os.File
 	}
 )
 
 // NewOrbitRunner creates an instance of OrbitRunner.
 func NewOrbitRunner(context *context.OrbitContext) (*OrbitRunner, error) {
-	// first retrieves the data from the configuration file...
-	g := generator.NewOrbitGenerator(context)
-	data, err := g.Execute()
+	r, problems := loadOrbitRunner(context, false)
+	if len(problems) > 0 {
+		return nil, problems[0]
+	}
+
+	return r, nil
+}
+
+/*
+LoadOrbitRunner creates an instance of OrbitRunner like NewOrbitRunner, but goes on when the configuration file
+has problems (e.g. an invalid attribute, a missing run file or included file, a cycle of extends or dependencies)
+in order to return all of them, like Check does once the runner is instantiated.
+
+The runner is nil if the configuration file cannot be read at all, i.e. if its template cannot be executed
+or if it's not a YAML file.
+*/
+func LoadOrbitRunner(context *context.OrbitContext) (*OrbitRunner, []error) {
+	return loadOrbitRunner(context, false)
+}
+
+// loadOrbitRunner creates an instance of OrbitRunner and returns all the problems of the configuration file.
+// If skipTypeErrors is true, the attributes of the wrong type are not reported: the caller reports them itself.
+func loadOrbitRunner(context *context.OrbitContext, skipTypeErrors bool) (*OrbitRunner, []error) {
+	start := time.Now()
+
+	// first retrieves the data from the configuration file, with the variables of its dotenv files...
+	data, err := executeConfig(context)
 	if err != nil {
-		return nil, err
+		return nil, []error{err}
 	}
 
-	// then populates the orbitRunnerConfig.
+	start = ProfilePhase(context.Settings.ProfileOutput, "generator execute", start)
+
+	// then populates the orbitRunnerConfig: the attributes of the wrong type are skipped.
+	var problems []error
 	var config = &orbitRunnerConfig{}
 	if err := yaml.Unmarshal(data.Bytes(), &config); err != nil {
-		return nil, OrbitError.NewOrbitErrorf("configuration file %s is not a valid YAML file. Details:\n%s", context.TemplateFilePath, err)
+		if _, ok := err.(*yaml.TypeError); !ok {
+			return nil, []error{OrbitError.NewOrbitErrorf("configuration file %s is not a valid YAML file. Details:\n%s", context.TemplateFilePath, err)}
+		}
+
+		if !skipTypeErrors {
+			problems = append(problems, OrbitError.NewOrbitErrorf("configuration file %s is not a valid YAML file. Details:\n%s", context.TemplateFilePath, err))
+		}
+	}
+
+	// keeps the definitions of the tasks matching the current platform...
+	resolvePlatforms(config)
+
+	start = ProfilePhase(context.Settings.ProfileOutput, "unmarshal", start)
+
+	// reads the commands from the run files...
+	problems = append(problems, resolveRunFiles(config, context.BaseDir())...)
+
+	start = ProfilePhase(context.Settings.ProfileOutput, "run files", start)
+
+	// adds the tasks from the included configuration files...
+	if err := resolveIncludes(config, context); err != nil {
+		problems = append(problems, err)
+	}
+
+	start = ProfilePhase(context.Settings.ProfileOutput, "included configuration files", start)
+
+	// merges the local configuration file...
+	if err := loadLocalConfig(config, context); err != nil {
+		problems = append(problems, err)
 	}
 
+	start = ProfilePhase(context.Settings.ProfileOutput, "local configuration", start)
+
+	// then resolves the tasks extending others tasks...
+	for _, err := range resolveExtends(config) {
+		problems = append(problems, OrbitError.NewOrbitErrorf("configuration file %s has invalid tasks. Details:\n%s", context.TemplateFilePath, err))
+	}
+
+	// and verifies that the dependencies of the tasks form a graph without cycle.
+	for _, err := range checkDepsCycles(config) {
+		problems = append(problems, OrbitError.NewOrbitErrorf("configuration file %s has invalid tasks. Details:\n%s", context.TemplateFilePath, err))
+	}
+
+	destructivePatterns, patternsProblems := compileDestructivePatterns(config.ConfirmDestructive)
+	problems = append(problems, patternsProblems...)
+
+	ProfilePhase(context.Settings.ProfileOutput, "validation", start)
+
 	r := &OrbitRunner{
-		config:  config,
-		context: context,
+		config:              config,
+		context:             context,
+		EnvPrefix:           DefaultEnvPrefix,
+		OutputBufferSize:    DefaultOutputBufferSize,
+		destructivePatterns: destructivePatterns,
+		Stdin:               os.Stdin,
+		Stdout:              os.Stdout,
+		Stderr:              os.Stderr,
 	}
 
-	logger.Debugf("runner has been instantiated with config %s and context %s", r.config, r.context)
+	logger.Debugf("runner has been instantiated with config %v and context %v", r.config, r.context)
 
-	return r, nil
+	return r, problems
 }
 
 // Print prints the available tasks from the configuration file
 // to Stdout.
-func (r *OrbitRunner) Print() {
-	w := tabwriter.NewWriter(os.Stdout, 0, 0, 1, ' ', tabwriter.TabIndent)
-
-	fmt.Fprint(w, "Configuration file:")
-	fmt.Fprintf(w, "\n  %s\t\n", r.context.TemplateFilePath)
-	fmt.Fprint(w, "\nAvailable tasks:")
+func (r *OrbitRunner) Print() error {
+	return r.printTasks(r.Stdout)
+}
 
+// listTasks returns the tasks which are not private, sorted according to Sort.
+func (r *OrbitRunner) listTasks() ([]*orbitTask, error) {
+	var tasks []*orbitTask
 	for _, task := range r.config.Tasks {
 		if !task.Private {
-			fmt.Fprintf(w, "\n  %s\t%s", task.Use, task.Short)
+			tasks = append(tasks, task)
 		}
 	}
 
-	// clears the writer as it may contain some weird characters.
-	fmt.Fprintln(w, "")
+	switch r.Sort {
+	case "", SortByOrder:
+		return tasks, nil
+	case SortByName:
+		sort.SliceStable(tasks, func(i, j int) bool {
+			return tasks[i].Use < tasks[j].Use
+		})
 
-	w.Flush()
+		return tasks, nil
+	default:
+		return nil, OrbitError.NewOrbitErrorf("unknown sort %s, expected %s or %s", r.Sort, SortByOrder, SortByName)
+	}
+}
+
+/*
+PrintCommands prints the commands of the given task to Stdout, one per line.
+
+The commands are printed after templating but before being wrapped by a shell.
+If expand is true, the commands calling others tasks are replaced by the commands of these tasks.
+*/
+func (r *OrbitRunner) PrintCommands(name string, expand bool) error {
+	commands, err := r.listCommands(name, expand, nil)
+	if err != nil {
+		return err
+	}
+
+	for _, cmd := range commands {
+		fmt.Fprintln(r.Stdout, cmd)
+	}
+
+	return nil
+}
+
+// listCommands returns the commands of the given task.
+// The chain argument contains the names of the tasks being expanded.
+func (r *OrbitRunner) listCommands(name string, expand bool, chain []string) ([]string, error) {
+	task := r.getTask(name)
+	if task == nil {
+		return nil, OrbitError.NewOrbitErrorf("task %s does not exist in configuration file %s", name, r.context.TemplateFilePath)
+	}
+
+	for _, previous := range chain {
+		if previous == name {
+			return nil, OrbitError.NewOrbitErrorf("unable to expand the commands of task %s as it calls itself", name)
+		}
+	}
+
+	if !expand {
+		return displayCommands(task.Run), nil
+	}
+
+	var commands []string
+	for _, entry := range task.Run {
+		call := r.interpret(entry.Command)
+		if call == nil {
+			commands = append(commands, displayCommands(orbitCommands{entry})...)
+			continue
+		}
+
+		for _, subtask := range call.tasks {
+			subcommands, err := r.listCommands(subtask, expand, append(chain, name))
+			if err != nil {
+				return nil, err
+			}
+
+			commands = append(commands, subcommands...)
+		}
+	}
+
+	return commands, nil
 }
 
 // Run runs the given tasks.
+// Within a call, each dependency of the tasks is run at most once.
 func (r *OrbitRunner) Run(names ...string) error {
+	return r.RunWithArgs(nil, names...)
+}
+
+/*
+RunWithArgs runs the given tasks, whose commands receive the given arguments.
+
+If StdinFile is not empty, the file is opened at the beginning of the call and closed at its end.
+*/
+func (r *OrbitRunner) RunWithArgs(args []string, names ...string) error {
+	if len(r.callStack) > 0 {
+		return r.runWithArgs(args, names...)
+	}
+
+	r.completed = make(map[string]bool)
+
+	if r.StdinFile != "" {
+		file, err := os.Open(r.StdinFile)
+		if err != nil {
+			return OrbitError.NewOrbitErrorf("unable to open the input file %s. Details:\n%s", r.StdinFile, err)
+		}
+
+		r.stdinFile = file
+		defer func() {
+			r.stdinFile = nil
+			if err := file.Close(); err != nil {
+				logger.Error(OrbitError.NewOrbitErrorf("unable to close the input file %s. Details:\n%s", r.StdinFile, err))
+			}
+		}()
+	}
+
+	return r.runWithArgs(args, names...)
+}
+
+// runWithArgs runs the given tasks, whose commands receive the given arguments.
+func (r *OrbitRunner) runWithArgs(args []string, names ...string) error {
 	// populates an array of instances of orbitTask.
 	// if a given name doest not match with any tasks defined in the configuration file, throws an error.
 	tasks := make([]*orbitTask, len(names))
@@ -126,14 +648,27 @@ func (r *OrbitRunner) Run(names ...string) error {
 
 	// alright, let's run each task.
 	for _, task := range tasks {
-		if err := r.run(task); err != nil {
+		if err := r.run(task, args); err != nil {
 			return err
 		}
+
+		r.completed[task.Use] = true
 	}
 
 	return nil
 }
 
+// tasksExist returns true if all the given tasks are defined in the configuration file.
+func (r *OrbitRunner) tasksExist(names []string) bool {
+	for _, name := range names {
+		if r.getTask(name) == nil {
+			return false
+		}
+	}
+
+	return true
+}
+
 // getTask returns an instance of orbitTask if found or nil.
 func (r *OrbitRunner) getTask(name string) *orbitTask {
 	for _, task := range r.config.Tasks {
@@ -145,44 +680,383 @@ func (r *OrbitRunner) getTask(name string) *orbitTask {
 	return nil
 }
 
-// run executes the stack of commands from the given task.
-func (r *OrbitRunner) run(task *orbitTask) error {
+/*
+run executes the stack of commands from the given task, which receive the given arguments.
+
+According to the result, it then executes either the after_success
+or the after_failure commands. A failure of these follow-up commands is
+reported but does not replace the result of the task.
+
+If the task is already being run, i.e. it calls itself directly or through
+others tasks, returns an error instead of looping forever.
+*/
+func (r *OrbitRunner) run(task *orbitTask, args []string) error {
+	for _, name := range r.callStack {
+		if name == task.Use {
+			return OrbitError.NewOrbitErrorf("cyclic task reference detected: %s -> %s", strings.Join(r.callStack, " -> "), task.Use)
+		}
+	}
+
+	if err := platformError(task); err != nil {
+		return err
+	}
+
+	if err := checkArgs(task, args); err != nil {
+		return err
+	}
+
+	if task.When != nil {
+		run, err := evaluateWhen(*task.When)
+		if err != nil {
+			return OrbitError.NewOrbitErrorf("unable to evaluate the condition of task %s. Details:\n%s", task.Use, err)
+		}
+
+		if !run {
+			logger.Infof("skipping task %s as its condition is false", task.Use)
+			return r.printDryRunSkip(task, "", "its condition is false")
+		}
+	}
+
+	r.callStack = append(r.callStack, task.Use)
+	defer func() { r.callStack = r.callStack[:len(r.callStack)-1] }()
+
 	if task.Short == "" {
 		logger.Infof("running task %s", task.Use)
 	} else {
 		logger.Infof("running task %s: %s", task.Use, task.Short)
 	}
 
-	for _, cmd := range task.Run {
+	if len(args) > 0 {
+		logger.Infof("task %s receives the arguments %s", task.Use, args)
+	}
+
+	if err := r.runDeps(task); err != nil {
+		return err
+	}
+
+	output, err := getOutput(r.Output, r.Stdout)
+	if err != nil {
+		return err
+	}
+
+	changed, err := r.hasChanged(task)
+	if err != nil {
+		return err
+	}
+
+	if !changed {
+		logger.Infof("skipping task %s as none of its files has changed since its last successful run", task.Use)
+		return r.printDryRunSkip(task, "", "none of its files has changed since its last successful run")
+	}
+
+	upToDate, sources, err := r.isUpToDate(task)
+	if err != nil {
+		return err
+	}
+
+	if upToDate {
+		logger.Infof("skipping task %s as its sources and generated files are unchanged since its last successful run", task.Use)
+		return r.printDryRunSkip(task, "", "its sources and generated files are unchanged since its last successful run")
+	}
+
+	ctx, release := r.taskContext(task)
+	defer release()
+
+	ctx, sentinel, err := r.watchSentinel(ctx, task)
+	if err != nil {
+		return err
+	}
+
+	defer sentinel.stop()
+
+	state, err := r.newTaskState(ctx, task, args)
+	if err != nil {
+		return err
+	}
+
+	defer state.close()
+
+	start := time.Now()
+	defer func() {
+		if r.DryRun {
+			return
+		}
+
+		elapsed := time.Since(start)
+		r.recordDuration(task, elapsed)
+		r.pushMetrics(task, elapsed, err)
+		r.recordResult(state.result, start, elapsed, err)
+	}()
+
+	output.taskStarted(task)
+	if before := r.beforeStack(task); len(before) > 0 {
+		logger.Infof("running before commands from task %s", task.Use)
+		err = r.runStack(state, plainCommands(before))
+	}
+
+	if err == nil {
+		err = r.runStack(state, r.taskStack(task))
+	}
+
+	err = sentinel.error(task, r.timeoutError(ctx, task, err))
+
+	if err == nil && len(task.AfterSuccess) > 0 {
+		logger.Infof("running after_success commands from task %s", task.Use)
+		if hookErr := r.runStack(state, plainCommands(task.AfterSuccess)); hookErr != nil {
+			logger.Error(OrbitError.NewOrbitErrorf("after_success commands from task %s have failed. Details:\n%s", task.Use, hookErr))
+		}
+	}
+
+	if failure := r.failureStack(task); err != nil && len(failure) > 0 {
+		logger.Infof("running after_failure commands from task %s", task.Use)
+		if hookErr := r.runStack(state, plainCommands(failure)); hookErr != nil {
+			logger.Error(OrbitError.NewOrbitErrorf("after_failure commands from task %s have failed. Details:\n%s", task.Use, hookErr))
+		}
+	}
+
+	r.runAfter(state)
+
+	if state.output != nil {
+		r.diffPrevious(task, state.output.Bytes())
+	}
+
+	if err == nil && !r.DryRun {
+		r.recordSuccess(task, start)
+		r.recordFingerprint(task, sources)
+	}
+
+	output.taskFinished(task, err)
+
+	return err
+}
+
+// runStack executes the given stack of commands from the given running task.
+func (r *OrbitRunner) runStack(state *orbitTaskState, stack orbitCommands) error {
+	// environ is the environment carried from one command to another
+	// if the working environment is enabled.
+	var environ []string
+
+	// errs contains the failures which have not stopped the task, if it continues on error.
+	var errs []error
+
+	for _, entry := range stack {
+		// a task whose deadline has been exceeded does not execute other commands.
+		if err := state.ctx.Err(); err != nil {
+			return err
+		}
+
+		// check if the current entry is conditional.
+		if entry.When != nil {
+			run, err := evaluateWhen(*entry.When)
+			if err != nil {
+				return OrbitError.NewOrbitErrorf("unable to evaluate the condition of command %s from task %s. Details:\n%s", entry, state.task.Use, err)
+			}
+
+			if !run {
+				logger.Infof("skipping command %s from task %s as its condition is false", entry, state.task.Use)
+				if err := r.printDryRunSkip(state.task, entry.String(), "its condition is false"); err != nil {
+					return err
+				}
+
+				continue
+			}
+		}
+
+		// check if the current entry is a parallel group of commands.
+		if len(entry.Parallel) > 0 {
+			if err := r.executeParallel(entry.Parallel, state, environ); err != nil {
+				if !continueOnError(state, err) {
+					return err
+				}
+
+				errs = append(errs, err)
+			}
+
+			continue
+		}
+
+		cmd := entry.Command
+
 		// check if the current command is calling others tasks.
-		tasks := r.interpret(cmd)
-		if tasks != nil {
-			if err := r.Run(tasks...); err != nil {
+		if call := r.interpret(cmd); call != nil {
+			if err := r.call(call, state.task); err != nil {
+				if !continueOnError(state, err) {
+					return err
+				}
+
+				errs = append(errs, err)
+			}
+
+			continue
+		}
+
+		// check if the current command is a notification.
+		if message, ok := r.interpretNotification(cmd); ok {
+			if r.DryRun {
+				if err := r.printDryRunNotification(message, state); err != nil {
+					return err
+				}
+
+				continue
+			}
+
+			if err := r.notify(message, state); err != nil {
 				return err
 			}
-		} else {
-			e := r.buildCommand(cmd, task)
-			e.Stdout = os.Stdout
-			e.Stderr = os.Stderr
-			e.Stdin = os.Stdin
 
-			logger.Infof("executing command %s from task %s", e.Args, task.Use)
+			continue
+		}
 
-			if err := e.Run(); err != nil {
+		result, err := r.executeCommand(cmd, state, environ)
+		if err != nil {
+			if !continueOnError(state, err) {
 				return err
 			}
+
+			errs = append(errs, err)
+			continue
 		}
+
+		environ = result
 	}
 
-	return nil
+	return failuresError(state.task, errs)
+}
+
+// call runs the tasks called by the given task if the condition of the call is true.
+func (r *OrbitRunner) call(call *orbitCall, task *orbitTask) error {
+	if call.when != "" {
+		ok, err := evaluateWhen(call.when)
+		if err != nil {
+			return OrbitError.NewOrbitErrorf("unable to call tasks %s from task %s. Details:\n%s", call.tasks, task.Use, err)
+		}
+
+		if !ok {
+			logger.Infof("skipping tasks %s called from task %s as condition %s is false", call.tasks, task.Use, call.when)
+			return r.printDryRunSkip(task, "the call to tasks "+strings.Join(call.tasks, ", "), "its condition is false")
+		}
+	}
+
+	return r.runWithArgs(call.args, call.tasks...)
+}
+
+/*
+execute executes the given command from the given running task, once its references
+to the runtime variables have been replaced.
+
+If environ is nil, the command inherits the environment of the current process.
+Returns the environment to use for the next command of the task, which is only
+captured if the working environment is enabled.
+*/
+func (r *OrbitRunner) execute(cmd string, state *orbitTaskState, environ []string) ([]string, error) {
+	task := state.task
+	cmd = interpolate(cmd, r.commandEnv(state, environ))
+
+	if r.DryRun {
+		return environ, r.printDryRun(cmd, state, environ)
+	}
+
+	if err := r.confirm(cmd, task); err != nil {
+		return nil, err
+	}
+
+	var workingEnvFilePath string
+	command := cmd
+
+	// the environment exported by a command inside a container or on a remote host cannot be captured.
+	captureEnv := r.WorkingEnv && runtime.GOOS != "windows" && isLocal(task)
+	if shell, ok := workingEnvShell(task); captureEnv && !ok {
+		logger.Warnf("working environment of task %s is ignored as its shell %s is not a POSIX shell", task.Use, shell)
+		captureEnv = false
+	}
+
+	if captureEnv {
+		filePath, err := newWorkingEnvFile()
+		if err != nil {
+			return nil, err
+		}
+
+		defer removeWorkingEnvFile(filePath)
+
+		workingEnvFilePath = filePath
+		command = wrapWorkingEnv(cmd, filePath)
+	}
+
+	ctx, release := commandContext(state)
+	defer release()
+
+	e := r.buildCommand(command, state, environ)
+
+	stdout, stderr, flush := r.commandWriters(state)
+	defer flush()
+
+	e.Stdout = stdout
+	e.Stderr = stderr
+	e.Stdin = state.stdin
+
+	var stderrTail *tailWriter
+	if r.RecordCommands {
+		stderrTail = &tailWriter{size: stderrTailSize}
+		e.Stderr = io.MultiWriter(e.Stderr, stderrTail)
+	}
+
+	if r.RunFunc != nil {
+		if err := r.RunFunc(e); err != nil {
+			err = OrbitError.NewOrbitErrorf("command %s from task %s has been aborted. Details:\n%s", e.Args, task.Use, err)
+			state.failure = newFailure(task, cmd, err, 0)
+			r.recordCommand(task, cmd, time.Now(), 0, err, nil)
+			return nil, err
+		}
+	}
+
+	logger.Infof("executing command %s from task %s", e.Args, task.Use)
+
+	start := time.Now()
+	err := runProcess(ctx, e, r.GracePeriod)
+	elapsed := time.Since(start)
+	r.recordCommand(task, cmd, start, elapsed, err, stderrTail)
+
+	if r.DurationThreshold > 0 && elapsed > r.DurationThreshold {
+		logger.Warnf("command %s from task %s took %s, which exceeds the threshold of %s", e.Args, task.Use, elapsed, r.DurationThreshold)
+	}
+
+	if err != nil {
+		state.failure = newFailure(task, cmd, err, elapsed)
+		return nil, commandTimeoutError(ctx, state, e.Args, err)
+	}
+
+	if workingEnvFilePath != "" {
+		return readWorkingEnv(workingEnvFilePath, environ)
+	}
+
+	return environ, nil
 }
 
 // compiledRegexp is a simple regex pattern used to match a string created by
-// the template function run.
-var compiledRegexp = regexp.MustCompile(`^run@(.+)$`)
+// the template functions run and runIf.
+var compiledRegexp = regexp.MustCompile(`^run(?:\?([^@]*))?@(.+)$`)
+
+// orbitCall represents a command calling others tasks.
+type orbitCall struct {
+	// tasks contains the names of the called tasks.
+	tasks []string
 
-// interpret checks if the command is calling others tasks.
-func (r *OrbitRunner) interpret(cmd string) []string {
+	// args contains the arguments given to the called tasks.
+	args []string
+
+	// when is the condition which has to be true to call the tasks.
+	// If empty, the tasks are always called.
+	when string
+}
+
+/*
+interpret checks if the command is calling others tasks.
+
+The arguments follow the names of the tasks, separated by whitespaces: "run@deploy staging"
+calls the task deploy with the argument staging. If the whole string names existing tasks
+(e.g. "run@falcon 9"), there are no arguments.
+*/
+func (r *OrbitRunner) interpret(cmd string) *orbitCall {
 	// let's check if the command match our pattern.
 	match := compiledRegexp.FindStringSubmatch(cmd)
 
@@ -191,25 +1065,62 @@ func (r *OrbitRunner) interpret(cmd string) []string {
 		return nil
 	}
 
-	// ok, let's retrieve the tasks from the command.
-	return strings.Split(match[1], ",")
+	// ok, let's retrieve the condition, the tasks and the arguments from the command.
+	call := &orbitCall{
+		tasks: strings.Split(match[2], ","),
+		when:  match[1],
+	}
+
+	if r.tasksExist(call.tasks) {
+		return call
+	}
+
+	if fields := strings.Fields(match[2]); len(fields) > 1 {
+		call.tasks = strings.Split(fields[0], ",")
+		call.args = fields[1:]
+	}
+
+	return call
 }
 
-// buildCommand returns an exec.Cmd instance.
-func (r *OrbitRunner) buildCommand(cmd string, task *orbitTask) *exec.Cmd {
-	if task.Shell != "" {
-		// the user has specified a custom binary to use.
-		shellAndParams := strings.Fields(task.Shell)
-		shell := shellAndParams[0]
-		parameters := append(shellAndParams[1:], cmd)
+/*
+buildCommand returns an exec.Cmd instance for the given running task, built by its executor.
+
+If environ is nil, the command inherits the environment of the current process
+followed by the variables from the env files and the env attribute of the task.
+The executors running the commands elsewhere forward only these variables and the ones injected by Orbit.
+*/
+func (r *OrbitRunner) buildCommand(cmd string, state *orbitTaskState, environ []string) *exec.Cmd {
+	forwarded := append(append([]string{}, state.env...), r.buildEnv(state)...)
+
+	e := state.executor.command(cmd, state.task, state.args, forwarded)
+	e.Env = r.commandEnv(state, environ)
+	e.Dir = state.dir
 
-		return exec.Command(shell, parameters...)
+	return e
+}
+
+// commandEnv returns the environment of a command from the given running task, followed by the variables injected by Orbit.
+// If environ is nil, it's the environment of the current process followed by the variables from the env files and the env attribute of the task.
+func (r *OrbitRunner) commandEnv(state *orbitTaskState, environ []string) []string {
+	if environ == nil {
+		environ = append(os.Environ(), state.env...)
 	}
 
-	// if no custom binary specified, detects the current shell of the user.
-	if runtime.GOOS == "windows" {
-		return exec.Command(os.Getenv(defaultWindowsShellEnvVariable), "/c", cmd)
+	env := make([]string, 0, len(environ)+1)
+	env = append(env, environ...)
+
+	return append(env, r.buildEnv(state)...)
+}
+
+// buildEnv returns the environment variables injected by Orbit in the commands of the given running task:
+// its name, its arguments separated by spaces and the values of its declared arguments.
+// Each variable name starts with the prefix from EnvPrefix.
+func (r *OrbitRunner) buildEnv(state *orbitTaskState) []string {
+	env := []string{
+		fmt.Sprintf("%sTASK=%s", r.EnvPrefix, state.task.Use),
+		fmt.Sprintf("%sARGS=%s", r.EnvPrefix, strings.Join(state.args, " ")),
 	}
 
-	return exec.Command(os.Getenv(defaultPosixShellEnvVariable), "-c", cmd)
+	return append(env, r.argsEnv(state)...)
 }
@@ -1,8 +1,15 @@
 package runner
 
 import (
+	"errors"
+	"os"
+	"os/exec"
 	"path/filepath"
+	"reflect"
+	"runtime"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/gulien/orbit/app/context"
 )
@@ -33,13 +40,101 @@ func TestNewOrbitRunner(t *testing.T) {
 	}
 }
 
-// A dumb test to improve code coverage.
+// Tests if loading an OrbitRunner reports all the problems of the configuration file.
+func TestLoadOrbitRunner(t *testing.T) {
+	// case 1: uses a broken configuration file.
+	brokenTemplateFilePath, _ := filepath.Abs("../../_tests/broken-template.yml")
+	ctx, _ := context.NewOrbitContext(brokenTemplateFilePath, "", "")
+	if r, problems := LoadOrbitRunner(ctx); r != nil || len(problems) != 1 {
+		t.Errorf("OrbitRunner should not have been instantiated, got %v!", problems)
+	}
+
+	// case 2: uses a configuration file with an invalid attribute, a missing included file and cycles.
+	templateFilePath, _ := filepath.Abs("../../_tests/broken-orbit-load.yml")
+	ctx, _ = context.NewOrbitContext(templateFilePath, "", "")
+	r, problems := LoadOrbitRunner(ctx)
+	if r == nil {
+		t.Fatal("OrbitRunner should have been instantiated!")
+	}
+
+	expected := []string{"cannot unmarshal", "missing.yml does not exist", "cyclic extends detected", "cyclic dependency detected"}
+	if len(problems) != len(expected) {
+		t.Fatalf("Loading should have reported %d problems, got %v!", len(expected), problems)
+	}
+
+	for index, problem := range problems {
+		if !strings.Contains(problem.Error(), expected[index]) {
+			t.Errorf("Problem should have contained %s, got %s!", expected[index], problem)
+		}
+	}
+
+	// case 3: checks the loaded configuration file too.
+	if problems := r.Check(); len(problems) != 1 || !strings.Contains(problems[0].Error(), "task empty") {
+		t.Errorf("Check should have reported the task empty, got %v!", problems)
+	}
+
+	// case 4: uses the same configuration file with NewOrbitRunner.
+	if _, err := NewOrbitRunner(ctx); err == nil {
+		t.Error("OrbitRunner should not have been instantiated!")
+	}
+}
+
+// Tests Print function with the different sorts.
 func TestPrint(t *testing.T) {
 	templateFilePath, _ := filepath.Abs("../../_tests/orbit.yml")
 	ctx, _ := context.NewOrbitContext(templateFilePath, "", "")
 	r, _ := NewOrbitRunner(ctx)
 
-	r.Print()
+	// case 1: uses the default sort.
+	if err := r.Print(); err != nil {
+		t.Error("Tasks should have been printed!")
+	}
+
+	// case 2: uses the sort by name.
+	r.Sort = SortByName
+	if err := r.Print(); err != nil {
+		t.Error("Tasks should have been printed by name!")
+	}
+
+	tasks, _ := r.listTasks()
+	for i := 1; i < len(tasks); i++ {
+		if tasks[i-1].Use > tasks[i].Use {
+			t.Errorf("Task %s should have been listed after task %s!", tasks[i-1].Use, tasks[i].Use)
+		}
+	}
+
+	// case 3: uses an unknown sort.
+	r.Sort = "size"
+	if err := r.Print(); err == nil {
+		t.Error("Tasks should not have been printed with an unknown sort!")
+	}
+}
+
+// Tests PrintCommands function with existing and non existing tasks.
+func TestPrintCommands(t *testing.T) {
+	templateFilePath, _ := filepath.Abs("../../_tests/orbit.yml")
+	ctx, _ := context.NewOrbitContext(templateFilePath, "", "")
+	r, _ := NewOrbitRunner(ctx)
+
+	// case 1: uses a non existing task.
+	if err := r.PrintCommands("discovery", false); err == nil {
+		t.Error("Task should not exist!")
+	}
+
+	// case 2: uses a task which calls others tasks.
+	if err := r.PrintCommands("new shepard", false); err != nil {
+		t.Error("Commands should have been printed!")
+	}
+
+	// case 3: uses a task which calls others tasks with expansion.
+	if err := r.PrintCommands("new shepard", true); err != nil {
+		t.Error("Expanded commands should have been printed!")
+	}
+
+	// case 4: uses a task which calls a non existing task with expansion.
+	if err := r.PrintCommands("new glenn", true); err == nil {
+		t.Error("Commands calling a non existing task should not have been expanded!")
+	}
 }
 
 // Tests Run function by running different kind of tasks.
@@ -92,4 +187,205 @@ func TestRun(t *testing.T) {
 	if err := r.Run("new glenn"); err == nil {
 		t.Error("Task calling another task should not have been run!")
 	}
+
+	// case 10: uses a task with a failing after_success command.
+	if err := r.Run("soyuz"); err != nil {
+		t.Error("A failing after_success command should not have failed the task!")
+	}
+
+	// case 11: uses a failing task with an after_failure command.
+	if err := r.Run("proton"); err == nil {
+		t.Error("Task with after_failure commands should have failed!")
+	}
+
+	// case 12: uses a task which calls others tasks according to conditions.
+	if err := r.Run("saturn"); err != nil {
+		t.Error("Task calling others tasks with conditions should have been run!")
+	}
+
+	// case 13: uses a task which calls another task with an invalid condition.
+	if err := r.Run("apollo"); err == nil {
+		t.Error("Task calling another task with an invalid condition should not have been run!")
+	}
+
+	// case 14: uses a correct task with a duration threshold.
+	r.DurationThreshold = time.Nanosecond
+	if err := r.Run("explorer"); err != nil {
+		t.Error("Slow task should have been run!")
+	}
+
+	r.DurationThreshold = 0
+
+	// case 15: uses a task which reads a variable injected by Orbit.
+	if err := r.Run("vostok"); err != nil {
+		t.Error("Task reading a variable injected by Orbit should have been run!")
+	}
+
+	// case 16: uses a task which reads a variable injected by Orbit with another prefix.
+	r.EnvPrefix = "CUSTOM_"
+	if err := r.Run("vostok"); err == nil {
+		t.Error("Task reading a variable injected by Orbit with another prefix should have failed!")
+	}
+}
+
+// Tests if the environment exported by a command is carried
+// into the next command when the working environment is enabled.
+func TestRunWithWorkingEnv(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("working environment is only available on POSIX systems")
+	}
+
+	templateFilePath, _ := filepath.Abs("../../_tests/orbit.yml")
+	ctx, _ := context.NewOrbitContext(templateFilePath, "", "")
+	r, _ := NewOrbitRunner(ctx)
+
+	// case 1: uses a task which reads a variable exported by a previous command.
+	if err := r.Run("gemini"); err == nil {
+		t.Error("Task should have failed without working environment!")
+	}
+
+	// case 2: uses the same task with working environment.
+	r.WorkingEnv = true
+	if err := r.Run("gemini"); err != nil {
+		t.Error("Task should have been run with working environment!")
+	}
+
+	// case 3: uses a failing task with working environment.
+	if err := r.Run("challenger"); err == nil {
+		t.Error("Task should have failed with working environment!")
+	}
+}
+
+// Tests if the RunFunc hook is able to modify or abort the commands.
+func TestRunWithRunFunc(t *testing.T) {
+	templateFilePath, _ := filepath.Abs("../../_tests/orbit.yml")
+	ctx, _ := context.NewOrbitContext(templateFilePath, "", "")
+	r, _ := NewOrbitRunner(ctx)
+
+	// case 1: uses a hook modifying the command.
+	var args []string
+	r.RunFunc = func(e *exec.Cmd) error {
+		args = append([]string{}, e.Args...)
+		e.Args = append(e.Args[:len(e.Args)-1], `echo "I am a substituted command"`)
+		return nil
+	}
+
+	if err := r.Run("challenger"); err != nil {
+		t.Error("Substituted command should have succeeded!")
+	}
+
+	if len(args) == 0 || args[len(args)-1] != `failecho "I am challenger task"` {
+		t.Errorf("Hook should have received the command, got %v!", args)
+	}
+
+	// case 2: uses a hook aborting the command.
+	r.RunFunc = func(e *exec.Cmd) error {
+		return errors.New("sandboxed")
+	}
+
+	if err := r.Run("explorer"); err == nil {
+		t.Error("Aborted command should have failed the task!")
+	}
+}
+
+// Tests if running tasks never changes the working directory of the current process.
+func TestRunKeepsWorkingDirectory(t *testing.T) {
+	templateFilePath, _ := filepath.Abs("../../_tests/orbit.yml")
+	ctx, _ := context.NewOrbitContext(templateFilePath, "", "")
+	r, _ := NewOrbitRunner(ctx)
+
+	wd, _ := os.Getwd()
+
+	// case 1: uses a succeeding task calling others tasks.
+	r.Run("new shepard")
+	if current, _ := os.Getwd(); current != wd {
+		t.Errorf("Working directory should have been %s, got %s!", wd, current)
+	}
+
+	// case 2: uses a failing task.
+	r.Run("challenger")
+	if current, _ := os.Getwd(); current != wd {
+		t.Errorf("Working directory should have been %s after a failure, got %s!", wd, current)
+	}
+
+	if runtime.GOOS == "windows" {
+		return
+	}
+
+	// case 3: uses a task with a working directory followed by a task without.
+	templateFilePath, _ = filepath.Abs("../../_tests/orbit-dir.yml")
+	ctx, _ = context.NewOrbitContext(templateFilePath, "", "")
+	r, _ = NewOrbitRunner(ctx)
+	if err := r.Run("explorer", "voskhod"); err != nil {
+		t.Errorf("Task without working directory should have been run in %s after a task with one, got %s!", wd, err)
+	}
+
+	if current, _ := os.Getwd(); current != wd {
+		t.Errorf("Working directory should have been %s after a task with a working directory, got %s!", wd, current)
+	}
+}
+
+// Tests if a task calling itself through others tasks is detected.
+func TestRunCyclicTasks(t *testing.T) {
+	templateFilePath, _ := filepath.Abs("../../_tests/orbit-cyclic.yml")
+	ctx, _ := context.NewOrbitContext(templateFilePath, "", "")
+	r, _ := NewOrbitRunner(ctx)
+
+	// case 1: uses two tasks calling each other.
+	err := r.Run("build")
+	if err == nil || !strings.Contains(err.Error(), "cyclic task reference detected: build -> test -> build") {
+		t.Errorf("Cyclic task reference should have been detected, got %v!", err)
+	}
+
+	// case 2: uses a task calling another task twice in sequence.
+	if err := r.Run("ci"); err != nil {
+		t.Errorf("Task called twice in sequence should have been run, got %s!", err)
+	}
+}
+
+// Tests if the arguments of a call are given to the called tasks.
+func TestRunWithArgs(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("skipping test on Windows")
+	}
+
+	templateFilePath, _ := filepath.Abs("../../_tests/orbit-args.yml")
+	ctx, _ := context.NewOrbitContext(templateFilePath, "", "")
+	r, _ := NewOrbitRunner(ctx)
+
+	// case 1: uses the same task called with different arguments.
+	if err := r.Run("release"); err != nil {
+		t.Errorf("Called task should have received its arguments, got %s!", err)
+	}
+
+	// case 2: uses a call to a task whose name contains a space.
+	if err := r.Run("launch"); err != nil {
+		t.Errorf("Task whose name contains a space should have been called without arguments, got %s!", err)
+	}
+
+	// case 3: uses a call with arguments to many tasks.
+	call := r.interpret("run@deploy,release prod eu")
+	if !reflect.DeepEqual(call.tasks, []string{"deploy", "release"}) || !reflect.DeepEqual(call.args, []string{"prod", "eu"}) {
+		t.Errorf("Call should have been split into tasks and arguments, got %v and %v!", call.tasks, call.args)
+	}
+
+	// case 4: uses a task declaring arguments.
+	if err := r.RunWithArgs([]string{"myapp", "1.2.0"}, "publish"); err != nil {
+		t.Errorf("Task should have received its declared arguments, got %s!", err)
+	}
+
+	// case 5: uses a call to a task declaring arguments.
+	if err := r.Run("ship"); err != nil {
+		t.Errorf("Called task should have received its declared arguments, got %s!", err)
+	}
+
+	// case 6: uses a task declaring arguments with a missing value.
+	if err := r.RunWithArgs([]string{"myapp"}, "publish"); err == nil {
+		t.Error("Task with a missing argument should have thrown an error!")
+	}
+
+	// case 7: uses a task declaring an invalid argument name.
+	if problems := r.Check(); len(problems) != 1 {
+		t.Errorf("Check should have reported 1 problem, got %d!", len(problems))
+	}
 }
@@ -0,0 +1,144 @@
+package runner
+
+import (
+	gocontext "context"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	OrbitError "github.com/gulien/orbit/app/error"
+)
+
+const (
+	// sentinelCreated aborts the task when the sentinel file exists.
+	sentinelCreated = "created"
+
+	// sentinelDeleted aborts the task when the sentinel file does not exist.
+	sentinelDeleted = "deleted"
+)
+
+// sentinelPollInterval is the delay between two checks of the sentinel file.
+var sentinelPollInterval = 100 * time.Millisecond
+
+type (
+	// orbitSentinel represents the sentinel file of a task as defined in the configuration file.
+	orbitSentinel struct {
+		// Path is the path of the sentinel file, relative to the configuration file.
+		Path string `yaml:"path"`
+
+		// On is the event which aborts the task, either created (default) or deleted.
+		On string `yaml:"on,omitempty"`
+	}
+
+	// orbitSentinelWatch watches the sentinel file of a running task.
+	orbitSentinelWatch struct {
+		// path is the resolved path of the sentinel file.
+		path string
+
+		// triggered is different from zero once the sentinel has aborted the task.
+		triggered int32
+
+		// done stops the watch.
+		done chan struct{}
+
+		// wg waits for the end of the watch.
+		wg sync.WaitGroup
+
+		// release cancels the context of the task and restores the context of the runner.
+		release func()
+	}
+)
+
+/*
+watchSentinel returns a context derived from the given one, which is cancelled once the sentinel file
+of the given task has been created (or deleted), and the watch of this file.
+
+The sentinel file is checked periodically from the start of the task. If the task has no sentinel,
+returns the given context and a nil watch.
+*/
+func (r *OrbitRunner) watchSentinel(ctx gocontext.Context, task *orbitTask) (gocontext.Context, *orbitSentinelWatch, error) {
+	if task.Sentinel == nil || task.Sentinel.Path == "" {
+		return ctx, nil, nil
+	}
+
+	switch task.Sentinel.On {
+	case "", sentinelCreated, sentinelDeleted:
+	default:
+		return nil, nil, OrbitError.NewOrbitErrorf("task %s has an unknown sentinel event %s, expected %s or %s", task.Use, task.Sentinel.On, sentinelCreated, sentinelDeleted)
+	}
+
+	parent := r.cancelContext
+	ctx, cancel := gocontext.WithCancel(ctx)
+	r.cancelContext = ctx
+
+	watch := &orbitSentinelWatch{
+		path: r.resolvePath(task.Sentinel.Path),
+		done: make(chan struct{}),
+		release: func() {
+			cancel()
+			r.cancelContext = parent
+		},
+	}
+
+	deleted := task.Sentinel.On == sentinelDeleted
+	check := func() bool {
+		_, err := os.Stat(watch.path)
+		if (err == nil) != deleted {
+			atomic.StoreInt32(&watch.triggered, 1)
+			cancel()
+			return true
+		}
+
+		return false
+	}
+
+	if check() {
+		return ctx, watch, nil
+	}
+
+	watch.wg.Add(1)
+	go func() {
+		defer watch.wg.Done()
+
+		ticker := time.NewTicker(sentinelPollInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-watch.done:
+				return
+			case <-ticker.C:
+				if check() {
+					return
+				}
+			}
+		}
+	}()
+
+	return ctx, watch, nil
+}
+
+// stop stops the watch of the sentinel file.
+func (watch *orbitSentinelWatch) stop() {
+	if watch == nil {
+		return
+	}
+
+	close(watch.done)
+	watch.wg.Wait()
+	watch.release()
+}
+
+// error returns a sentinel error if the sentinel file has aborted the given task, otherwise the given error.
+func (watch *orbitSentinelWatch) error(task *orbitTask, err error) error {
+	if watch == nil || err == nil || atomic.LoadInt32(&watch.triggered) == 0 {
+		return err
+	}
+
+	if task.Sentinel.On == sentinelDeleted {
+		return OrbitError.NewOrbitErrorf("task %s has been aborted as its sentinel file %s has been deleted", task.Use, watch.path)
+	}
+
+	return OrbitError.NewOrbitErrorf("task %s has been aborted as its sentinel file %s has been created", task.Use, watch.path)
+}
@@ -0,0 +1,67 @@
+package runner
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gulien/orbit/app/context"
+)
+
+// Tests if a task is aborted once its sentinel file has been created or deleted.
+func TestWatchSentinel(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("sleep is not available on Windows")
+	}
+
+	sentinelPollInterval = 10 * time.Millisecond
+
+	templateFilePath, _ := filepath.Abs("../../_tests/orbit-sentinel.yml")
+	ctx, _ := context.NewOrbitContext(templateFilePath, "", "")
+	r, _ := NewOrbitRunner(ctx)
+
+	// case 1: uses a sentinel file created while the task is running.
+	stopFilePath, _ := filepath.Abs("../../_tests/explorer.stop")
+	defer os.Remove(stopFilePath)
+
+	created := make(chan struct{})
+	go func() {
+		defer close(created)
+		time.Sleep(200 * time.Millisecond)
+		ioutil.WriteFile(stopFilePath, nil, 0644)
+	}()
+
+	start := time.Now()
+	if err := r.Run("explorer"); err == nil || !strings.Contains(err.Error(), "sentinel file") {
+		t.Errorf("Task should have been aborted by its sentinel file, got %v!", err)
+	}
+
+	if time.Since(start) > 4*time.Second {
+		t.Error("Command should have been cancelled!")
+	}
+
+	<-created
+
+	// case 2: uses a sentinel file which has been deleted before the task.
+	if err := r.Run("sputnik"); err == nil || !strings.Contains(err.Error(), "has been deleted") {
+		t.Errorf("Task should have been aborted by its deleted sentinel file, got %v!", err)
+	}
+
+	// case 3: uses a sentinel file which exists.
+	lockFilePath, _ := filepath.Abs("../../_tests/sputnik.lock")
+	ioutil.WriteFile(lockFilePath, nil, 0644)
+	defer os.Remove(lockFilePath)
+
+	if err := r.Run("sputnik"); err != nil {
+		t.Error("Task should not have been aborted by its sentinel file!")
+	}
+
+	// case 4: uses an unknown sentinel event.
+	if err := r.Run("vostok"); err == nil {
+		t.Error("Task with an unknown sentinel event should not have been run!")
+	}
+}
@@ -0,0 +1,106 @@
+package runner
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	OrbitError "github.com/gulien/orbit/app/error"
+)
+
+/*
+PrintEffectiveShell prints to Stdout, for each command of the given task, the shell invocation
+which would execute it, without executing any command.
+
+The invocation is resolved like when running the task: from the executor and the shell attribute of the task
+or, if empty, from the shell of the user.
+*/
+func (r *OrbitRunner) PrintEffectiveShell(name string) error {
+	return r.printEffectiveShell(r.Stdout, name)
+}
+
+// printEffectiveShell prints the shell invocations of the commands of the given task to the given writer.
+func (r *OrbitRunner) printEffectiveShell(w io.Writer, name string) error {
+	task := r.getTask(name)
+	if task == nil {
+		return OrbitError.NewOrbitErrorf("task %s does not exist in configuration file %s", name, r.context.TemplateFilePath)
+	}
+
+	executor, err := r.getExecutor(task)
+	if err != nil {
+		return err
+	}
+
+	stacks := []struct {
+		name  string
+		stack orbitCommands
+	}{
+		{"before", plainCommands(task.Before)},
+		{"run", task.Run},
+		{"after_success", plainCommands(task.AfterSuccess)},
+		{"after_failure", plainCommands(task.AfterFailure)},
+		{"on_failure", plainCommands(task.OnFailure)},
+		{"after", plainCommands(task.After)},
+	}
+
+	for _, stack := range stacks {
+		if len(stack.stack) == 0 {
+			continue
+		}
+
+		fmt.Fprintf(w, "%s:\n", stack.name)
+
+		for _, entry := range stack.stack {
+			if entry.When != nil {
+				fmt.Fprintf(w, "  when %s:\n", *entry.When)
+			}
+
+			if len(entry.Parallel) > 0 {
+				fmt.Fprintf(w, "  runs in parallel:\n")
+				for _, cmd := range entry.Parallel {
+					fmt.Fprintf(w, "    %s\n", r.effectiveShell(cmd, task, executor))
+				}
+
+				continue
+			}
+
+			cmd := entry.Command
+
+			if call := r.interpret(cmd); call != nil {
+				fmt.Fprintf(w, "  calls tasks %s\n", strings.Join(call.tasks, ", "))
+				continue
+			}
+
+			if message, ok := r.interpretNotification(cmd); ok {
+				fmt.Fprintf(w, "  notifies %s\n", strconv.Quote(message))
+				continue
+			}
+
+			fmt.Fprintf(w, "  %s\n", r.effectiveShell(cmd, task, executor))
+		}
+	}
+
+	return nil
+}
+
+// effectiveShell returns the shell invocation of the given command from the given task by the given executor, with its arguments quoted.
+func (r *OrbitRunner) effectiveShell(cmd string, task *orbitTask, executor orbitExecutor) string {
+	cmd, _ = ignoredFailure(cmd, task)
+	args := executor.command(cmd, task, nil, nil).Args
+	quoted := make([]string, len(args))
+	for index, arg := range args {
+		quoted[index] = quoteArg(arg)
+	}
+
+	return strings.Join(quoted, " ")
+}
+
+// quoteArg quotes the given argument if it's empty or contains whitespaces or quotes.
+func quoteArg(arg string) string {
+	if arg == "" || strings.ContainsAny(arg, " \t\n\"'") {
+		return strconv.Quote(arg)
+	}
+
+	return arg
+}
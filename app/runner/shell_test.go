@@ -0,0 +1,34 @@
+package runner
+
+import (
+	"bytes"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/gulien/orbit/app/context"
+)
+
+// Tests if the shell invocations of the commands of a task are printed.
+func TestPrintEffectiveShell(t *testing.T) {
+	templateFilePath, _ := filepath.Abs("../../_tests/orbit.yml")
+	ctx, _ := context.NewOrbitContext(templateFilePath, "", "")
+	r, _ := NewOrbitRunner(ctx)
+
+	// case 1: uses a task with a custom shell.
+	var buf bytes.Buffer
+	if err := r.printEffectiveShell(&buf, "falcon"); err != nil || buf.String() != "run:\n  bash -c \"echo \\\"I am falcon task\\\"\"\n" {
+		t.Errorf("Shell invocation of task falcon should have been printed, got %q!", buf.String())
+	}
+
+	// case 2: uses a task calling others tasks.
+	buf.Reset()
+	if err := r.printEffectiveShell(&buf, "new shepard"); err != nil || !strings.HasSuffix(buf.String(), "  calls tasks explorer, sputnik\n") {
+		t.Errorf("Called tasks of task new shepard should have been printed, got %q!", buf.String())
+	}
+
+	// case 3: uses a non existing task.
+	if err := r.printEffectiveShell(&buf, "vulcan"); err == nil {
+		t.Error("Shell invocations of a non existing task should not have been printed!")
+	}
+}
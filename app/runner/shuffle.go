@@ -0,0 +1,15 @@
+package runner
+
+import "math/rand"
+
+// ShuffleTasks returns the given tasks in a pseudo-random order given by the seed.
+// The same seed always gives the same order.
+func ShuffleTasks(names []string, seed int64) []string {
+	shuffled := append([]string{}, names...)
+
+	rand.New(rand.NewSource(seed)).Shuffle(len(shuffled), func(i, j int) {
+		shuffled[i], shuffled[j] = shuffled[j], shuffled[i]
+	})
+
+	return shuffled
+}
@@ -0,0 +1,32 @@
+package runner
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+)
+
+// Tests if the tasks are shuffled reproducibly.
+func TestShuffleTasks(t *testing.T) {
+	names := []string{"explorer", "sputnik", "vostok", "gemini", "apollo", "soyuz"}
+
+	// case 1: uses the same seed twice.
+	shuffled := ShuffleTasks(names, 42)
+	if !reflect.DeepEqual(shuffled, ShuffleTasks(names, 42)) {
+		t.Error("Same seed should have given the same order!")
+	}
+
+	// case 2: verifies that no task has been lost.
+	sorted := append([]string{}, shuffled...)
+	sort.Strings(sorted)
+	expected := append([]string{}, names...)
+	sort.Strings(expected)
+	if !reflect.DeepEqual(sorted, expected) {
+		t.Errorf("Shuffled tasks should have been %v, got %v!", names, shuffled)
+	}
+
+	// case 3: verifies that the given tasks are not modified.
+	if names[0] != "explorer" || names[5] != "soyuz" {
+		t.Error("Given tasks should not have been modified!")
+	}
+}
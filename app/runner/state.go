@@ -0,0 +1,284 @@
+package runner
+
+import (
+	"bytes"
+	gocontext "context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+
+	OrbitError "github.com/gulien/orbit/app/error"
+	"github.com/gulien/orbit/app/generator"
+	"github.com/gulien/orbit/app/logger"
+
+	"github.com/joho/godotenv"
+)
+
+// orbitTaskState represents a task being run.
+type orbitTaskState struct {
+	// task is the task being run.
+	task *orbitTask
+
+	// ctx is the context cancelling the commands of the task.
+	ctx gocontext.Context
+
+	// stdin is the reader of the standard input of the commands.
+	stdin io.Reader
+
+	// stdout is the writer of the standard output of the commands.
+	stdout io.Writer
+
+	// stderr is the writer of the standard error of the commands.
+	stderr io.Writer
+
+	// executor builds the commands of the task.
+	executor orbitExecutor
+
+	// index is the index, starting at 1, of the command being executed inside a parallel group.
+	// If 0, the command is not executed in parallel.
+	index int
+
+	// result is the run of the task, which receives the failures of its commands.
+	result *orbitResult
+
+	// failure is the last failure of the command being executed, recorded
+	// once the command has failed at all its attempts and has not been ignored.
+	failure *orbitFailure
+
+	// output contains the standard output of the commands if the task
+	// is diffed with its previous run.
+	output *bytes.Buffer
+
+	// args contains the arguments given to the task by the task calling it.
+	args []string
+
+	// dir is the working directory of the commands.
+	// If empty, it's the current directory.
+	dir string
+
+	// env contains the variables from the env files and the env attribute of the task.
+	env []string
+
+	// files contains the files opened for the task.
+	files []*os.File
+
+	// writers contains the writers to the system logger opened for the task.
+	writers []io.Closer
+}
+
+/*
+newTaskState creates an instance of orbitTaskState for the given task, whose commands are cancelled by the given context
+and receive the given arguments.
+
+It reads the env files of the task, verifies the variables required by the task, creates its directories,
+verifies its working directory and, if the task redirects the standard input, output or error of its commands, it opens (or creates) the files.
+All these paths are relative to the configuration file. If the runner has opened its StdinFile,
+the commands read this file instead of the one of the task.
+*/
+func (r *OrbitRunner) newTaskState(ctx gocontext.Context, task *orbitTask, args []string) (*orbitTaskState, error) {
+	executor, err := r.getExecutor(task)
+	if err != nil {
+		return nil, err
+	}
+
+	env, err := r.readEnvFiles(task)
+	if err != nil {
+		return nil, err
+	}
+
+	if env, err = r.resolveRequiredEnv(task, env); err != nil {
+		return nil, err
+	}
+
+	// a dry run does not create anything.
+	if !r.DryRun {
+		for _, path := range task.Mkdir {
+			path = r.resolvePath(path)
+			if err := os.MkdirAll(path, 0755); err != nil {
+				return nil, OrbitError.NewOrbitErrorf("unable to create the directory %s of task %s. Details:\n%s", path, task.Use, err)
+			}
+		}
+	}
+
+	var dir string
+	if r.Dir != "" || task.Dir != "" {
+		dir = r.resolvePath(task.Dir)
+		if r.Dir != "" {
+			dir = r.Dir
+		}
+
+		// in a dry run, the directory may not have been created by mkdir.
+		if !r.DryRun {
+			if info, err := os.Stat(dir); err != nil {
+				return nil, OrbitError.NewOrbitErrorf("unable to use the working directory %s of task %s. Details:\n%s", dir, task.Use, err)
+			} else if !info.IsDir() {
+				return nil, OrbitError.NewOrbitErrorf("unable to use the working directory %s of task %s. Details:\n%s is not a directory", dir, task.Use, dir)
+			}
+		}
+	}
+
+	state := &orbitTaskState{
+		task:     task,
+		executor: executor,
+		args:     args,
+		dir:      dir,
+		ctx:      ctx,
+		stdin:    r.Stdin,
+		result:   &orbitResult{task: task},
+		stdout:   r.Stdout,
+		stderr:   r.Stderr,
+		env:      env,
+	}
+
+	opened := make(map[string]*os.File)
+	open := func(path string, console io.Writer) (io.Writer, error) {
+		if path == "" || r.DryRun {
+			return console, nil
+		}
+
+		path = r.resolvePath(path)
+
+		file, ok := opened[path]
+		if !ok {
+			var err error
+			if file, err = os.Create(path); err != nil {
+				return nil, OrbitError.NewOrbitErrorf("unable to create the output file %s of task %s. Details:\n%s", path, task.Use, err)
+			}
+
+			opened[path] = file
+			state.files = append(state.files, file)
+		}
+
+		if isEnabled(task.Echo) {
+			return io.MultiWriter(console, file), nil
+		}
+
+		return file, nil
+	}
+
+	if state.stdout, err = open(task.Stdout, r.Stdout); err != nil {
+		state.close()
+		return nil, err
+	}
+
+	if state.stderr, err = open(task.Stderr, r.Stderr); err != nil {
+		state.close()
+		return nil, err
+	}
+
+	if r.stdinFile != nil {
+		state.stdin = r.stdinFile
+	} else if task.Stdin != "" {
+		stdinFilePath := r.resolvePath(task.Stdin)
+		file, err := os.Open(stdinFilePath)
+		if err != nil {
+			state.close()
+			return nil, OrbitError.NewOrbitErrorf("unable to open the input file %s of task %s. Details:\n%s", stdinFilePath, task.Use, err)
+		}
+
+		state.files = append(state.files, file)
+		state.stdin = file
+	}
+
+	if r.LogTaskOutput {
+		if stdout, stderr := logger.SystemWriters(); stdout != nil {
+			state.stdout = io.MultiWriter(state.stdout, stdout)
+			state.stderr = io.MultiWriter(state.stderr, stderr)
+			state.writers = append(state.writers, stdout, stderr)
+		}
+	}
+
+	if isEnabled(task.DiffPrevious) && !r.DryRun {
+		state.output = &bytes.Buffer{}
+		state.stdout = io.MultiWriter(state.stdout, state.output)
+	}
+
+	return state, nil
+}
+
+/*
+readEnvFiles returns the variables from the global env attribute of the configuration file and from the dotenv files,
+followed by the variables from the env files of the given task and from its env attribute.
+
+The files are read in the order of declaration: if a variable is defined in many files,
+the last definition wins. The dotenv files win over the global env attribute, the env files
+over the dotenv files, and the env attribute of the task wins over the files.
+
+Like the dotenv files, the permissions of the env files are checked first. Each env file is then
+executed as a data-driven template, with the same payload and additional templates as the configuration file.
+*/
+func (r *OrbitRunner) readEnvFiles(task *orbitTask) ([]string, error) {
+	env := appendVariables(nil, r.config.Env)
+	env = appendVariables(env, r.context.Env)
+	for _, path := range task.EnvFiles {
+		path = r.resolvePath(path)
+
+		variables, err := r.readEnvFile(path)
+		if err != nil {
+			return nil, OrbitError.NewOrbitErrorf("unable to read the env file %s of task %s. Details:\n%s", path, task.Use, err)
+		}
+
+		env = appendVariables(env, variables)
+	}
+
+	return appendVariables(env, task.Env), nil
+}
+
+// readEnvFile returns the variables of the given env file once its permissions have been checked and it has been executed.
+func (r *OrbitRunner) readEnvFile(filePath string) (map[string]string, error) {
+	if err := r.context.CheckPermissions(filePath); err != nil {
+		return nil, err
+	}
+
+	fileContext := *r.context
+	fileContext.TemplateFilePath = filePath
+
+	data, err := generator.NewOrbitGenerator(&fileContext).Execute()
+	if err != nil {
+		return nil, err
+	}
+
+	return godotenv.Parse(&data)
+}
+
+// appendVariables appends the given variables to the given environment, sorted by name.
+func appendVariables(env []string, variables map[string]string) []string {
+	keys := make([]string, 0, len(variables))
+	for key := range variables {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		env = append(env, fmt.Sprintf("%s=%s", key, variables[key]))
+	}
+
+	return env
+}
+
+// resolvePath returns the given path relative to the configuration file if it's not absolute.
+func (r *OrbitRunner) resolvePath(path string) string {
+	if filepath.IsAbs(path) {
+		return path
+	}
+
+	return filepath.Join(r.context.BaseDir(), path)
+}
+
+// close closes the files and the writers to the system logger opened for the task.
+func (state *orbitTaskState) close() {
+	for _, writer := range state.writers {
+		if err := writer.Close(); err != nil {
+			logger.Error(OrbitError.NewOrbitErrorf("unable to send the outputs of task %s to the system logger. Details:\n%s", state.task.Use, err))
+		}
+	}
+
+	for _, file := range state.files {
+		if err := file.Close(); err != nil {
+			logger.Error(OrbitError.NewOrbitErrorf("unable to close the file %s of task %s. Details:\n%s", file.Name(), state.task.Use, err))
+		}
+	}
+}
@@ -0,0 +1,177 @@
+package runner
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/gulien/orbit/app/context"
+)
+
+// Tests if the outputs of the commands are redirected to the files of the task.
+func TestNewTaskState(t *testing.T) {
+	templateFilePath, _ := filepath.Abs("../../_tests/orbit-outputs.yml")
+	ctx, _ := context.NewOrbitContext(templateFilePath, "", "")
+	r, _ := NewOrbitRunner(ctx)
+
+	// case 1: uses distinct files for standard output and error.
+	if err := r.Run("explorer"); err != nil {
+		t.Error("Task with redirected outputs should have been run!")
+	}
+
+	outFilePath, _ := filepath.Abs("../../_tests/explorer.out.log")
+	errFilePath, _ := filepath.Abs("../../_tests/explorer.err.log")
+	out, _ := ioutil.ReadFile(outFilePath)
+	errOut, _ := ioutil.ReadFile(errFilePath)
+	os.Remove(outFilePath)
+	os.Remove(errFilePath)
+
+	if string(out) != "I am explorer task\n" || string(errOut) != "I am an explorer error\n" {
+		t.Errorf("Outputs should have been redirected to distinct files, got %q and %q!", out, errOut)
+	}
+
+	// case 2: uses the same file for standard output and error with echo.
+	if err := r.Run("sputnik"); err != nil {
+		t.Error("Task with redirected outputs should have been run!")
+	}
+
+	filePath, _ := filepath.Abs("../../_tests/sputnik.log")
+	out, _ = ioutil.ReadFile(filePath)
+	os.Remove(filePath)
+
+	if string(out) != "I am sputnik task\nI am a sputnik error\n" {
+		t.Errorf("Outputs should have been redirected to the same file, got %q!", out)
+	}
+
+	// case 3: uses a broken output path.
+	if err := r.Run("challenger"); err == nil {
+		t.Error("Task with a broken output path should not have been run!")
+	}
+
+	// case 4: uses a directory to create for the output file.
+	if err := r.Run("vostok"); err != nil {
+		t.Error("Task with a directory to create should have been run!")
+	}
+
+	dirPath, _ := filepath.Abs("../../_tests/vostok")
+	out, _ = ioutil.ReadFile(filepath.Join(dirPath, "logs", "vostok.log"))
+	os.RemoveAll(dirPath)
+
+	if string(out) != "I am vostok task\n" {
+		t.Errorf("Output should have been redirected to a file in the created directory, got %q!", out)
+	}
+
+	// case 5: uses a directory which cannot be created.
+	if err := r.Run("soyuz"); err == nil {
+		t.Error("Task with a directory which cannot be created should not have been run!")
+	}
+
+	// case 6: uses a file as standard input.
+	if err := r.Run("gemini"); err != nil {
+		t.Error("Task with a redirected input should have been run!")
+	}
+
+	// case 7: uses a non existing file as standard input.
+	if err := r.Run("apollo"); err == nil {
+		t.Error("Task with a non existing input file should not have been run!")
+	}
+
+	// case 8: uses an input file from the runner over the one from the task.
+	r.StdinFile = templateFilePath
+	if err := r.Run("gemini"); err == nil {
+		t.Error("Input file from the runner should have taken precedence!")
+	}
+
+	// case 9: uses an input file from the runner shared by a task, its dependency and the task it calls.
+	r.StdinFile, _ = filepath.Abs("../../_tests/stdin-launchers.txt")
+	if err := r.Run("mercury"); err != nil {
+		t.Errorf("Tasks should have read the input file from the runner one after the other, got %s!", err)
+	}
+
+	// case 10: uses a non existing input file from the runner.
+	r.StdinFile = "non-existing.txt"
+	if err := r.Run("explorer"); err == nil || !strings.Contains(err.Error(), "non-existing.txt") {
+		t.Errorf("Non existing input file from the runner should have thrown an error, got %v!", err)
+	}
+}
+
+// Tests if the variables from the env files of a task are added to the environment of its commands.
+func TestReadEnvFiles(t *testing.T) {
+	templateFilePath, _ := filepath.Abs("../../_tests/orbit-env-files.yml")
+	ctx, _ := context.NewOrbitContext(templateFilePath, "", "")
+	r, _ := NewOrbitRunner(ctx)
+
+	// case 1: uses many env files.
+	if err := r.Run("explorer"); err != nil {
+		t.Error("Task with env files should have been run!")
+	}
+
+	// case 2: uses a non existing env file.
+	if err := r.Run("sputnik"); err == nil {
+		t.Error("Task with a non existing env file should not have been run!")
+	}
+
+	// case 3: uses an env attribute overriding the env files and the environment, with a task call.
+	if err := r.Run("challenger"); err != nil {
+		t.Errorf("Task with an env attribute should have been run, got %s!", err)
+	}
+
+	// case 4: uses a templated env file.
+	if err := r.Run("soyuz"); err != nil {
+		t.Errorf("Task with a templated env file should have been run, got %s!", err)
+	}
+
+	// case 5: uses an env file accessible by others users with the strict permissions.
+	if runtime.GOOS != "windows" {
+		os.Chmod("../../_tests/launchers.env", 0644)
+		ctx.Settings.StrictPermissions = true
+		if err := r.Run("challenger"); err == nil || !strings.Contains(err.Error(), "too open") {
+			t.Errorf("Task with an env file accessible by others users should not have been run, got %v!", err)
+		}
+	}
+}
+
+// Tests if the commands of a task are run in its working directory.
+func TestTaskDir(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("skipping test on Windows")
+	}
+
+	templateFilePath, _ := filepath.Abs("../../_tests/orbit-dir.yml")
+	ctx, _ := context.NewOrbitContext(templateFilePath, "", "")
+	r, _ := NewOrbitRunner(ctx)
+
+	// case 1: uses a working directory relative to the configuration file.
+	if err := r.Run("explorer"); err != nil {
+		t.Error("Task should have been run in the directory of the configuration file!")
+	}
+
+	// case 2: uses no working directory.
+	if err := r.Run("sputnik"); err == nil {
+		t.Error("Task should have been run in the current directory!")
+	}
+
+	// case 3: uses a non existing working directory.
+	if err := r.Run("challenger"); err == nil {
+		t.Error("Task with a non existing working directory should not have been run!")
+	}
+
+	// case 4: uses a file as working directory.
+	if err := r.Run("vostok"); err == nil {
+		t.Error("Task with a file as working directory should not have been run!")
+	}
+
+	// case 5: uses a templated working directory.
+	if err := r.Run("soyuz"); err != nil {
+		t.Error("Task with a templated working directory should have been run!")
+	}
+
+	// case 6: uses a working directory overriding the ones of the tasks.
+	r.Dir = filepath.Dir(templateFilePath)
+	if err := r.Run("sputnik", "challenger"); err != nil {
+		t.Errorf("Tasks should have been run in the overriding directory, got %s!", err)
+	}
+}
@@ -0,0 +1,77 @@
+package runner
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"time"
+
+	OrbitError "github.com/gulien/orbit/app/error"
+	"github.com/gulien/orbit/app/logger"
+)
+
+// orbitFailure represents a command which has failed.
+type orbitFailure struct {
+	// Task is the name of the task running the command.
+	Task string `json:"task"`
+
+	// Command is the command as defined in the configuration file.
+	Command string `json:"command"`
+
+	// ExitCode is the exit code of the command, or -1 if it has not been started.
+	ExitCode int `json:"exit_code"`
+
+	// Duration is the time spent running the command, in seconds.
+	Duration float64 `json:"duration"`
+}
+
+// newFailure creates an instance of orbitFailure for the given command from the given task,
+// which has failed with the given error after the given duration.
+func newFailure(task *orbitTask, cmd string, err error, elapsed time.Duration) *orbitFailure {
+	return &orbitFailure{
+		Task:     task.Use,
+		Command:  cmd,
+		ExitCode: exitCode(err),
+		Duration: elapsed.Seconds(),
+	}
+}
+
+// recordFailure keeps track of the command of the given running task which has failed, if any.
+func (r *OrbitRunner) recordFailure(state *orbitTaskState) {
+	if state.failure == nil {
+		return
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	r.failures = append(r.failures, state.failure)
+	if state.result != nil {
+		state.result.failures = append(state.result.failures, state.failure)
+	}
+}
+
+/*
+WriteFailSummary writes the commands which have failed into the given file,
+as a JSON array.
+
+If no command has failed, the array is empty.
+*/
+func (r *OrbitRunner) WriteFailSummary(filePath string) error {
+	failures := r.failures
+	if failures == nil {
+		failures = []*orbitFailure{}
+	}
+
+	data, err := json.MarshalIndent(failures, "", "  ")
+	if err != nil {
+		return OrbitError.NewOrbitErrorf("unable to encode the fail summary. Details:\n%s", err)
+	}
+
+	if err := ioutil.WriteFile(filePath, data, 0644); err != nil {
+		return OrbitError.NewOrbitErrorf("unable to write the fail summary file %s. Details:\n%s", filePath, err)
+	}
+
+	logger.Infof("fail summary file %s has been created", filePath)
+
+	return nil
+}
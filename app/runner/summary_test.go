@@ -0,0 +1,48 @@
+package runner
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gulien/orbit/app/context"
+)
+
+// Tests if the fail summary file contains the commands which have failed.
+func TestWriteFailSummary(t *testing.T) {
+	templateFilePath, _ := filepath.Abs("../../_tests/orbit.yml")
+	ctx, _ := context.NewOrbitContext(templateFilePath, "", "")
+	r, _ := NewOrbitRunner(ctx)
+
+	// case 1: uses a correct task.
+	r.Run("explorer")
+	if err := r.WriteFailSummary("summary.json"); err != nil {
+		t.Error("Fail summary file should have been written!")
+	}
+
+	var failures []*orbitFailure
+	data, _ := ioutil.ReadFile("summary.json")
+	json.Unmarshal(data, &failures)
+	if failures == nil || len(failures) != 0 {
+		t.Errorf("Fail summary file should contain an empty array, got %s!", data)
+	}
+
+	// case 2: uses a task which has a non existing command.
+	r.Run("challenger")
+	r.WriteFailSummary("summary.json")
+
+	data, _ = ioutil.ReadFile("summary.json")
+	json.Unmarshal(data, &failures)
+	if len(failures) != 1 || failures[0].Task != "challenger" || failures[0].Command != `failecho "I am challenger task"` || failures[0].ExitCode == 0 {
+		t.Errorf("Fail summary file should contain the failed command, got %s!", data)
+	}
+
+	os.Remove("summary.json")
+
+	// case 3: uses a broken output path.
+	if err := r.WriteFailSummary("/.../..."); err == nil {
+		t.Error("Fail summary file should not have been written!")
+	}
+}
@@ -0,0 +1,77 @@
+package runner
+
+import (
+	gocontext "context"
+
+	OrbitError "github.com/gulien/orbit/app/error"
+)
+
+/*
+taskContext returns the context cancelling the commands of the given task, and a function releasing it.
+
+If the task has a timeout, the context has a deadline which covers all its commands, including the ones
+from the tasks it calls. As this deadline is derived from the context of the running tasks,
+the earliest deadline always wins.
+*/
+func (r *OrbitRunner) taskContext(task *orbitTask) (gocontext.Context, func()) {
+	parent := r.cancelContext
+	if parent == nil {
+		parent = r.Context
+	}
+
+	if parent == nil {
+		parent = gocontext.Background()
+	}
+
+	if task.TaskTimeout <= 0 {
+		return parent, func() {}
+	}
+
+	ctx, cancel := gocontext.WithTimeout(parent, task.TaskTimeout)
+	r.cancelContext = ctx
+
+	return ctx, func() {
+		cancel()
+		r.cancelContext = parent
+	}
+}
+
+// timeoutError returns a timeout error if the deadline of the given task has been exceeded,
+// an interruption error if the context from Context is done, otherwise the given error.
+func (r *OrbitRunner) timeoutError(ctx gocontext.Context, task *orbitTask, err error) error {
+	if err != nil && task.TaskTimeout > 0 && ctx.Err() == gocontext.DeadlineExceeded {
+		return OrbitError.NewOrbitErrorf("task %s has exceeded its timeout of %s", task.Use, task.TaskTimeout)
+	}
+
+	if err != nil && r.Context != nil && r.Context.Err() != nil {
+		return OrbitError.NewOrbitErrorf("task %s has been interrupted", task.Use)
+	}
+
+	return err
+}
+
+/*
+commandContext returns the context cancelling a command of the given running task, and a function releasing it.
+
+If the task has a timeout per command, the context has a deadline derived from the context of the task.
+Otherwise, it's the context of the task.
+*/
+func commandContext(state *orbitTaskState) (gocontext.Context, func()) {
+	if state.task.Timeout <= 0 {
+		return state.ctx, func() {}
+	}
+
+	ctx, cancel := gocontext.WithTimeout(state.ctx, state.task.Timeout)
+
+	return ctx, cancel
+}
+
+// commandTimeoutError returns a timeout error if the given command from the given running task has exceeded
+// the timeout per command of the task, otherwise the given error.
+func commandTimeoutError(ctx gocontext.Context, state *orbitTaskState, args []string, err error) error {
+	if state.task.Timeout > 0 && ctx.Err() == gocontext.DeadlineExceeded && state.ctx.Err() == nil {
+		return OrbitError.NewOrbitErrorf("command %s from task %s has exceeded its timeout of %s and has been killed", args, state.task.Use, state.task.Timeout)
+	}
+
+	return err
+}
@@ -0,0 +1,134 @@
+package runner
+
+import (
+	gocontext "context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gulien/orbit/app/context"
+)
+
+// Tests if a task is cancelled once its timeout has been exceeded.
+func TestTaskTimeout(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("sleep is not available on Windows")
+	}
+
+	templateFilePath, _ := filepath.Abs("../../_tests/orbit-timeout.yml")
+	ctx, _ := context.NewOrbitContext(templateFilePath, "", "")
+	r, _ := NewOrbitRunner(ctx)
+
+	// case 1: uses a task exceeding its timeout.
+	start := time.Now()
+	if err := r.Run("explorer"); err == nil || !strings.Contains(err.Error(), "timeout") {
+		t.Errorf("Task exceeding its timeout should have failed with a timeout error, got %v!", err)
+	}
+
+	if time.Since(start) > 4*time.Second {
+		t.Error("Task exceeding its timeout should have been cancelled!")
+	}
+
+	// case 2: uses a task whose called task exceeds its timeout.
+	start = time.Now()
+	if err := r.Run("sputnik"); err == nil || !strings.Contains(err.Error(), "task sputnik has exceeded its timeout") {
+		t.Errorf("Task whose called task exceeds its timeout should have failed with a timeout error, got %v!", err)
+	}
+
+	if time.Since(start) > 4*time.Second {
+		t.Error("Called task should have been cancelled!")
+	}
+
+	// case 3: uses a task not exceeding its timeout.
+	if err := r.Run("gemini"); err != nil {
+		t.Error("Task not exceeding its timeout should have been run!")
+	}
+
+	// case 4: uses a task exceeding its timeout, whose command's child would create a file.
+	defer os.Remove("zarya.txt")
+	if err := r.Run("zarya"); err == nil || !strings.Contains(err.Error(), "timeout") {
+		t.Errorf("Task exceeding its timeout should have failed with a timeout error, got %v!", err)
+	}
+
+	time.Sleep(1500 * time.Millisecond)
+	if _, err := os.Stat("zarya.txt"); err == nil {
+		t.Error("Child process of the cancelled command should have been killed!")
+	}
+}
+
+// Tests if a command is killed, with the processes it has started, once the timeout per command has been exceeded.
+func TestCommandTimeout(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("sleep is not available on Windows")
+	}
+
+	templateFilePath, _ := filepath.Abs("../../_tests/orbit-timeout.yml")
+	ctx, _ := context.NewOrbitContext(templateFilePath, "", "")
+	r, _ := NewOrbitRunner(ctx)
+	defer os.Remove("orphan.txt")
+
+	// case 1: uses a command exceeding its timeout, whose child would create a file.
+	err := r.Run("soyuz")
+	if err == nil || !strings.Contains(err.Error(), "from task soyuz has exceeded its timeout of 200ms") {
+		t.Errorf("Command exceeding its timeout should have failed with a timeout error, got %v!", err)
+	}
+
+	time.Sleep(1500 * time.Millisecond)
+	if _, err := os.Stat("orphan.txt"); err == nil {
+		t.Error("Child process of the command should have been killed!")
+	}
+
+	// case 2: uses commands not exceeding their timeout.
+	if err := r.Run("apollo"); err != nil {
+		t.Errorf("Commands not exceeding their timeout should have been run, got %s!", err)
+	}
+
+	// case 3: uses a failing command with a timeout.
+	if err := r.Run("mercury"); err == nil || strings.Contains(err.Error(), "timeout") {
+		t.Errorf("Failing command should not have failed with a timeout error, got %v!", err)
+	}
+}
+
+// Tests if a cancelled command receives a termination signal before being killed.
+func TestGracePeriod(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("signals are not available on Windows")
+	}
+
+	templateFilePath, _ := filepath.Abs("../../_tests/orbit-timeout.yml")
+	ctx, _ := context.NewOrbitContext(templateFilePath, "", "")
+	r, _ := NewOrbitRunner(ctx)
+	r.GracePeriod = 2 * time.Second
+	defer os.Remove("terminated.txt")
+	defer os.Remove("interrupted.txt")
+
+	// case 1: uses a command exceeding its timeout.
+	if err := r.Run("voskhod"); err == nil || !strings.Contains(err.Error(), "timeout") {
+		t.Errorf("Command exceeding its timeout should have failed with a timeout error, got %v!", err)
+	}
+
+	if _, err := os.Stat("terminated.txt"); err != nil {
+		t.Error("Command exceeding its timeout should have received a termination signal!")
+	}
+
+	// case 2: uses a command interrupted by the cancellation of the context of the runner.
+	runnerContext, cancel := gocontext.WithCancel(gocontext.Background())
+	time.AfterFunc(200*time.Millisecond, cancel)
+	r.Context = runnerContext
+
+	start := time.Now()
+	if err := r.Run("salyut"); err == nil || !strings.Contains(err.Error(), "task salyut has been interrupted") {
+		t.Errorf("Interrupted task should have failed with an interruption error, got %v!", err)
+	}
+
+	if time.Since(start) > 4*time.Second {
+		t.Error("Interrupted command should have been cancelled!")
+	}
+
+	if _, err := os.Stat("interrupted.txt"); err != nil {
+		t.Error("Interrupted command should have received a termination signal!")
+	}
+}
@@ -0,0 +1,146 @@
+package runner
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/gulien/orbit/app/context"
+	OrbitError "github.com/gulien/orbit/app/error"
+
+	"gopkg.in/yaml.v2"
+)
+
+// yamlLineRegexp matches the line number of a YAML error.
+var yamlLineRegexp = regexp.MustCompile(`^(?:yaml: )?line (\d+): (.*)$`)
+
+// yamlTypeRegexp matches the names of the types in the YAML errors, e.g. "type runner.orbitTask".
+var yamlTypeRegexp = regexp.MustCompile(`type runner\.orbit(\w+)`)
+
+// yamlWordRegexp matches the words of a type name, e.g. "Notify" and "Config" in "NotifyConfig".
+var yamlWordRegexp = regexp.MustCompile(`[A-Z][a-z]*`)
+
+// useLineRegexp matches the line declaring the name of a task.
+var useLineRegexp = regexp.MustCompile(`^\s*(?:-\s+)?use:\s*(.+?)\s*$`)
+
+/*
+Validate validates the given configuration file without executing any command, and returns all the problems found.
+
+Unlike Check, the configuration file is parsed strictly: an unknown attribute or an attribute defined twice is a problem.
+Once parsed, it's checked like Check does. The problems are prefixed by the configuration file and, if possible,
+by the lines concerned in the configuration file as executed by the generator.
+The problems found while loading the configuration file (e.g. a missing included file or a cycle of extends)
+do not stop the validation. A template error or a YAML syntax error does, as the rest of the file cannot be read.
+*/
+func Validate(ctx *context.OrbitContext) []error {
+	fileName := ctx.TemplateFilePath
+	if ctx.TemplateURL != "" {
+		fileName = ctx.TemplateURL
+	}
+
+	data, err := executeConfig(ctx)
+	if err != nil {
+		return []error{err}
+	}
+
+	var problems []error
+	if err := yaml.UnmarshalStrict(data.Bytes(), &orbitRunnerConfig{}); err != nil {
+		typeErr, ok := err.(*yaml.TypeError)
+		if !ok {
+			return []error{locateYAMLError(fileName, err.Error())}
+		}
+
+		for _, message := range typeErr.Errors {
+			problems = append(problems, locateYAMLError(fileName, message))
+		}
+	}
+
+	// the attributes of the wrong type have already been reported above.
+	r, loadProblems := loadOrbitRunner(ctx, true)
+	problems = append(problems, loadProblems...)
+	if r == nil {
+		return problems
+	}
+
+	// a task defined many times is located by its occurrence, if all its definitions are in the configuration file.
+	lines := taskLines(data.Bytes())
+	occurrences := make(map[string][]*orbitTask)
+	for _, task := range r.config.Tasks {
+		occurrences[task.Use] = append(occurrences[task.Use], task)
+	}
+
+	r.check(func(task *orbitTask, err error) {
+		var taskLines []string
+		if task != nil {
+			taskLines = lines[task.Use]
+			if len(taskLines) == len(occurrences[task.Use]) {
+				for index, occurrence := range occurrences[task.Use] {
+					if occurrence == task {
+						taskLines = taskLines[index : index+1]
+					}
+				}
+			}
+		}
+
+		if len(taskLines) > 0 {
+			err = OrbitError.NewOrbitErrorf("%s:%s: %s", fileName, strings.Join(taskLines, ","), err)
+		} else {
+			err = OrbitError.NewOrbitErrorf("%s: %s", fileName, err)
+		}
+
+		problems = append(problems, err)
+	})
+
+	return problems
+}
+
+// locateYAMLError returns the given YAML error message prefixed by the given file name and its line, with readable type names.
+func locateYAMLError(fileName string, message string) error {
+	message = yamlTypeRegexp.ReplaceAllStringFunc(message, func(match string) string {
+		name := strings.TrimPrefix(match, "type runner.orbit")
+		if name == "RunnerConfig" {
+			return "configuration"
+		}
+
+		// e.g. NotifyConfig becomes notify config.
+		var words []string
+		for _, word := range yamlWordRegexp.FindAllString(name, -1) {
+			words = append(words, strings.ToLower(word))
+		}
+
+		return strings.Join(words, " ")
+	})
+
+	if match := yamlLineRegexp.FindStringSubmatch(message); match != nil {
+		return OrbitError.NewOrbitErrorf("%s:%s: %s", fileName, match[1], match[2])
+	}
+
+	return OrbitError.NewOrbitErrorf("%s: %s", fileName, strings.TrimPrefix(message, "yaml: "))
+}
+
+// taskLines returns the numbers of the lines declaring each task of the given executed configuration file.
+func taskLines(data []byte) map[string][]string {
+	lines := make(map[string][]string)
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for number := 1; scanner.Scan(); number++ {
+		match := useLineRegexp.FindStringSubmatch(scanner.Text())
+		if match == nil {
+			continue
+		}
+
+		name := match[1]
+		if unquoted, err := strconv.Unquote(name); err == nil {
+			name = unquoted
+		} else if len(name) > 1 && name[0] == '\'' && name[len(name)-1] == '\'' {
+			name = name[1 : len(name)-1]
+		}
+
+		lines[name] = append(lines[name], fmt.Sprint(number))
+	}
+
+	return lines
+}
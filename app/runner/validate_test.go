@@ -0,0 +1,86 @@
+package runner
+
+import (
+	"errors"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/gulien/orbit/app/context"
+)
+
+// Tests if all the problems of a configuration file are reported with their lines.
+func TestValidate(t *testing.T) {
+	// case 1: uses a valid configuration file.
+	templateFilePath, _ := filepath.Abs("../../_tests/orbit-parallel.yml")
+	ctx, _ := context.NewOrbitContext(templateFilePath, "", "")
+	if problems := Validate(ctx); len(problems) != 0 {
+		t.Errorf("Configuration file should have been valid, got %v!", problems)
+	}
+
+	// case 2: uses a configuration file with unknown attributes and invalid tasks.
+	templateFilePath, _ = filepath.Abs("../../_tests/orbit-validate.yml")
+	ctx, _ = context.NewOrbitContext(templateFilePath, "", "")
+
+	expected := []string{
+		":3: field shel not found in task",
+		":11: field attemps not found in retry",
+		":6: task build is defined more than once",
+		":6: task build calls task missing which does not exist",
+		":9: task empty has no commands to run nor dependencies",
+		":9: task empty has a retry policy with 0 attempts, expected at least 1",
+		":12: shell nosuchshell from task shelly is not available",
+	}
+
+	problems := Validate(ctx)
+	if len(problems) != len(expected) {
+		t.Fatalf("Configuration file should have had %d problems, got %v!", len(expected), problems)
+	}
+
+	for index, problem := range problems {
+		if problem.Error() != templateFilePath+expected[index] {
+			t.Errorf("Problem should have been %s, got %s!", expected[index], problem)
+		}
+	}
+
+	// case 3: uses a configuration file which cannot be loaded entirely.
+	templateFilePath, _ = filepath.Abs("../../_tests/broken-orbit-load.yml")
+	ctx, _ = context.NewOrbitContext(templateFilePath, "", "")
+
+	expected = []string{"cannot unmarshal", "missing.yml does not exist", "cyclic extends detected", "cyclic dependency detected", "task empty"}
+	problems = Validate(ctx)
+	if len(problems) != len(expected) {
+		t.Fatalf("Configuration file should have had %d problems, got %v!", len(expected), problems)
+	}
+
+	for index, problem := range problems {
+		if !strings.Contains(problem.Error(), expected[index]) {
+			t.Errorf("Problem should have contained %s, got %s!", expected[index], problem)
+		}
+	}
+}
+
+// Tests if the YAML errors are located in the configuration file.
+func TestLocateYAMLError(t *testing.T) {
+	// case 1: uses a syntax error.
+	if err := locateYAMLError("orbit.yml", "yaml: line 2: did not find expected key"); err.Error() != "orbit.yml:2: did not find expected key" {
+		t.Errorf("Syntax error should have been located, got %s!", err)
+	}
+
+	// case 2: uses an unknown attribute of a nested type.
+	if err := locateYAMLError("orbit.yml", "line 4: field token not found in type runner.orbitNotifyConfig"); err.Error() != "orbit.yml:4: field token not found in notify config" {
+		t.Errorf("Unknown attribute should have been located with a readable type, got %s!", err)
+	}
+
+	// case 3: uses an error without line.
+	if err := locateYAMLError("orbit.yml", errors.New("yaml: control characters are not allowed").Error()); err.Error() != "orbit.yml: control characters are not allowed" {
+		t.Errorf("Error without line should have been prefixed by the file, got %s!", err)
+	}
+
+	// case 4: uses the lines declaring the tasks.
+	lines := taskLines([]byte(strings.Join([]string{"tasks:", "  - use: build", `  - use: "falcon 9"`, "    run:", "  - use: 'build'"}, "\n")))
+	if !reflect.DeepEqual(lines, map[string][]string{"build": {"2", "5"}, "falcon 9": {"3"}}) {
+		t.Errorf("Lines declaring the tasks should have been found, got %v!", lines)
+	}
+}
@@ -0,0 +1,201 @@
+package runner
+
+import (
+	gocontext "context"
+	"fmt"
+	"os"
+	"time"
+
+	OrbitError "github.com/gulien/orbit/app/error"
+	"github.com/gulien/orbit/app/logger"
+
+	"golang.org/x/crypto/ssh/terminal"
+)
+
+const (
+	// defaultWatchDebounce is the default duration without changes to wait before running a watched task again.
+	defaultWatchDebounce = 500 * time.Millisecond
+
+	// defaultWatchPoll is the default interval at which the watched files are scanned.
+	defaultWatchPoll = 100 * time.Millisecond
+
+	// SlowWatchPoll is a slower interval at which the watched files are scanned,
+	// for the filesystems on which a scan is expensive (e.g. network filesystems).
+	SlowWatchPoll = time.Second
+)
+
+// orbitWatch represents the files watched to run a task again, as defined in the configuration file.
+type orbitWatch struct {
+	// Paths contains the glob patterns, relative to the configuration file, of the watched files.
+	Paths []string `yaml:"paths"`
+
+	// Debounce is the duration without changes to wait before running the task again (e.g. 1s).
+	Debounce time.Duration `yaml:"debounce,omitempty"`
+
+	// Poll is the interval at which the watched files are scanned (e.g. 2s), like WatchPoll.
+	Poll time.Duration `yaml:"poll,omitempty"`
+
+	// Clear allows to clear the terminal before running the task again, like ClearScreen.
+	Clear bool `yaml:"clear,omitempty"`
+}
+
+// clearScreenSequence moves the cursor to the top left corner of the terminal and clears it.
+const clearScreenSequence = "\033[H\033[2J"
+
+/*
+Watch runs the given task, then runs it again each time one of the files matching the given glob patterns
+(relative to the configuration file) is created, modified or deleted, until the given context is done.
+
+If no pattern is given, the ones from the watch attribute of the task are used. If the task is still running
+when the files change, it's cancelled first. A failure of the task is logged and does not stop the watch.
+If ClearScreen is true or the watch attribute of the task has the clear attribute, the terminal is cleared
+before running the task again.
+
+The changes are detected by scanning the modification times of the files at a regular interval, rather than
+relying on the notifications of the system: the watch works on every filesystem, including network filesystems.
+The interval is WatchPoll if set, otherwise the poll attribute of the task, and 100ms by default.
+*/
+func (r *OrbitRunner) Watch(ctx gocontext.Context, patterns []string, name string) error {
+	task := r.getTask(name)
+	if task == nil {
+		return OrbitError.NewOrbitErrorf("task %s does not exist in configuration file %s", name, r.context.TemplateFilePath)
+	}
+
+	debounce := defaultWatchDebounce
+	poll := defaultWatchPoll
+	clearTerminal := r.ClearScreen
+	if task.Watch != nil {
+		clearTerminal = clearTerminal || task.Watch.Clear
+
+		if task.Watch.Poll > 0 {
+			poll = task.Watch.Poll
+		}
+
+		if len(patterns) == 0 {
+			patterns = task.Watch.Paths
+		}
+
+		if task.Watch.Debounce > 0 {
+			debounce = task.Watch.Debounce
+		}
+	}
+
+	if r.WatchPoll > 0 {
+		poll = r.WatchPoll
+	}
+
+	if len(patterns) == 0 {
+		return OrbitError.NewOrbitErrorf("task %s has no files to watch", name)
+	}
+
+	files, err := r.watchedFiles(patterns)
+	if err != nil {
+		return err
+	}
+
+	for {
+		runContext, cancel := gocontext.WithCancel(ctx)
+		r.Context = runContext
+
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+
+			if err := r.Run(name); err != nil {
+				logger.Error(err)
+			}
+		}()
+
+		files, err = r.waitForChanges(ctx, patterns, files, poll, debounce)
+		cancel()
+		<-done
+
+		if err != nil {
+			return err
+		}
+
+		if ctx.Err() != nil {
+			return nil
+		}
+
+		if clearTerminal {
+			r.clearScreen()
+		}
+
+		logger.Infof("files of task %s have changed, running it again", name)
+	}
+}
+
+// clearScreen clears the terminal if Stdout is a terminal.
+func (r *OrbitRunner) clearScreen() {
+	if file, ok := r.Stdout.(*os.File); ok && terminal.IsTerminal(int(file.Fd())) {
+		fmt.Fprint(r.Stdout, clearScreenSequence)
+	}
+}
+
+/*
+waitForChanges scans the files matching the given patterns at the given interval until they differ from the given ones
+and then remain the same during the given debounce duration. Returns the new files.
+
+If the given context is done, returns the given files.
+*/
+func (r *OrbitRunner) waitForChanges(ctx gocontext.Context, patterns []string, files map[string]time.Time, poll time.Duration, debounce time.Duration) (map[string]time.Time, error) {
+	ticker := time.NewTicker(poll)
+	defer ticker.Stop()
+
+	var changedAt time.Time
+	for {
+		select {
+		case <-ctx.Done():
+			return files, nil
+		case <-ticker.C:
+		}
+
+		current, err := r.watchedFiles(patterns)
+		if err != nil {
+			return nil, err
+		}
+
+		if !sameFiles(files, current) {
+			files = current
+			changedAt = time.Now()
+			continue
+		}
+
+		if !changedAt.IsZero() && time.Since(changedAt) >= debounce {
+			return files, nil
+		}
+	}
+}
+
+// watchedFiles returns the modification times of the files matching the given patterns, relative to the configuration file.
+func (r *OrbitRunner) watchedFiles(patterns []string) (map[string]time.Time, error) {
+	files := make(map[string]time.Time)
+
+	// a file deleted during the walk is simply not watched.
+	err := r.walkSources(patterns, func(rel string, info os.FileInfo) error {
+		files[rel] = info.ModTime()
+		return nil
+	})
+
+	if err != nil {
+		return nil, OrbitError.NewOrbitErrorf("unable to find the watched files. Details:\n%s", err)
+	}
+
+	return files, nil
+}
+
+// sameFiles returns true if the given files have the same paths and modification times.
+func sameFiles(files map[string]time.Time, others map[string]time.Time) bool {
+	if len(files) != len(others) {
+		return false
+	}
+
+	for path, modTime := range files {
+		if other, ok := others[path]; !ok || !other.Equal(modTime) {
+			return false
+		}
+	}
+
+	return true
+}
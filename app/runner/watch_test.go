@@ -0,0 +1,128 @@
+package runner
+
+import (
+	"bytes"
+	gocontext "context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gulien/orbit/app/context"
+)
+
+// countRuns returns the number of lines written by the watched task.
+func countRuns() int {
+	data, _ := ioutil.ReadFile("watch-runs.txt")
+
+	return strings.Count(string(data), "\n")
+}
+
+// waitForRuns waits until the watched task has been run the given number of times, or returns false after 5 seconds.
+func waitForRuns(count int) bool {
+	for start := time.Now(); time.Since(start) < 5*time.Second; time.Sleep(50 * time.Millisecond) {
+		if countRuns() >= count {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Tests if a watched task is run again when its files change.
+func TestWatch(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("skipping test on Windows")
+	}
+
+	templateFilePath, _ := filepath.Abs("../../_tests/orbit-watch.yml")
+	watchedFilePath := filepath.Join(filepath.Dir(templateFilePath), "orbit-watch-1.tmp")
+	ctx, _ := context.NewOrbitContext(templateFilePath, "", "")
+	r, _ := NewOrbitRunner(ctx)
+	defer os.Remove("watch-runs.txt")
+	defer os.Remove(watchedFilePath)
+
+	watchContext, cancel := gocontext.WithCancel(gocontext.Background())
+	done := make(chan error, 1)
+	go func() { done <- r.Watch(watchContext, nil, "explorer") }()
+
+	// case 1: uses the first run of the task.
+	if !waitForRuns(1) {
+		t.Fatal("Watched task should have been run!")
+	}
+
+	// case 2: uses a created watched file.
+	ioutil.WriteFile(watchedFilePath, []byte("I am a watched file"), 0644)
+	if !waitForRuns(2) {
+		t.Error("Watched task should have been run again once its file has been created!")
+	}
+
+	// case 3: uses the cancellation of the watch.
+	cancel()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Errorf("Watch should have stopped without error, got %s!", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Error("Watch should have stopped once its context has been cancelled!")
+	}
+
+	// case 4: uses the poll attribute of the task.
+	if poll := r.getTask("explorer").Watch.Poll; poll != 50*time.Millisecond {
+		t.Errorf("Task explorer should have been scanned every 50ms, got %s!", poll)
+	}
+
+	// case 5: uses a task without watched files.
+	if err := r.Watch(gocontext.Background(), nil, "sputnik"); err == nil {
+		t.Error("Task without watched files should not have been watched!")
+	}
+
+	// case 6: uses a non existing task.
+	if err := r.Watch(gocontext.Background(), nil, "unknown"); err == nil {
+		t.Error("Non existing task should not have been watched!")
+	}
+}
+
+// Tests if the terminal is cleared only if Stdout is a terminal.
+func TestClearScreen(t *testing.T) {
+	templateFilePath, _ := filepath.Abs("../../_tests/orbit-watch.yml")
+	ctx, _ := context.NewOrbitContext(templateFilePath, "", "")
+	r, _ := NewOrbitRunner(ctx)
+
+	// case 1: uses the clear attribute of a watched task.
+	if explorer := r.getTask("explorer"); !explorer.Watch.Clear {
+		t.Error("Task explorer should have cleared the terminal!")
+	}
+
+	// case 2: uses a Stdout which is not a terminal.
+	var buf bytes.Buffer
+	r.Stdout = &buf
+	r.clearScreen()
+	if buf.Len() != 0 {
+		t.Errorf("Stdout which is not a terminal should not have been cleared, got %q!", buf.String())
+	}
+}
+
+// Tests if the watched files are compared with their paths and modification times.
+func TestSameFiles(t *testing.T) {
+	now := time.Now()
+
+	// case 1: uses the same files.
+	if !sameFiles(map[string]time.Time{"a.go": now}, map[string]time.Time{"a.go": now}) {
+		t.Error("Same files should have been considered as unchanged!")
+	}
+
+	// case 2: uses a modified file.
+	if sameFiles(map[string]time.Time{"a.go": now}, map[string]time.Time{"a.go": now.Add(time.Second)}) {
+		t.Error("Modified file should have been considered as changed!")
+	}
+
+	// case 3: uses a deleted file.
+	if sameFiles(map[string]time.Time{"a.go": now}, map[string]time.Time{}) {
+		t.Error("Deleted file should have been considered as changed!")
+	}
+}
@@ -0,0 +1,30 @@
+package runner
+
+import (
+	"strconv"
+	"strings"
+
+	OrbitError "github.com/gulien/orbit/app/error"
+)
+
+/*
+evaluateWhen evaluates a condition.
+
+As the configuration file is a data-driven template, a condition is
+the result of a template expression (e.g. {{ eq os "linux" }}) which has been
+executed by the generator: it has to be a boolean like "true", "false", "1" or "0".
+An empty condition is false.
+*/
+func evaluateWhen(condition string) (bool, error) {
+	condition = strings.TrimSpace(condition)
+	if condition == "" {
+		return false, nil
+	}
+
+	result, err := strconv.ParseBool(condition)
+	if err != nil {
+		return false, OrbitError.NewOrbitErrorf("condition %s is not a boolean", condition)
+	}
+
+	return result, nil
+}
@@ -0,0 +1,89 @@
+package runner
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/gulien/orbit/app/context"
+)
+
+// Tests if evaluating a condition returns the expected result.
+func TestEvaluateWhen(t *testing.T) {
+	// case 1: uses true conditions.
+	for _, condition := range []string{"true", "1", " true "} {
+		if ok, err := evaluateWhen(condition); !ok || err != nil {
+			t.Errorf("Condition %s should have been true!", condition)
+		}
+	}
+
+	// case 2: uses false conditions.
+	for _, condition := range []string{"false", "0", ""} {
+		if ok, err := evaluateWhen(condition); ok || err != nil {
+			t.Errorf("Condition %s should have been false!", condition)
+		}
+	}
+
+	// case 3: uses a condition which is not a boolean.
+	if _, err := evaluateWhen("maybe"); err == nil {
+		t.Error("Condition should not have been evaluated!")
+	}
+}
+
+// Tests if the tasks and the commands whose condition is false are skipped.
+func TestWhen(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("skipping test on Windows")
+	}
+
+	templateFilePath, _ := filepath.Abs("../../_tests/orbit-when.yml")
+	ctx, _ := context.NewOrbitContext(templateFilePath, "", "")
+	r, err := NewOrbitRunner(ctx)
+	if err != nil {
+		t.Fatalf("Runner should have been instantiated, got %s!", err)
+	}
+
+	defer os.Remove("when.log")
+
+	// case 1: uses a task with conditional commands calling a task whose condition is false.
+	if err := r.Run("explorer"); err != nil {
+		t.Errorf("Task should have been run, got %s!", err)
+	}
+
+	data, _ := ioutil.ReadFile("when.log")
+	os.Remove("when.log")
+	if expected := "explorer\nalways\nvostok\n"; string(data) != expected {
+		t.Errorf("Commands and tasks whose condition is false should have been skipped, got %q!", data)
+	}
+
+	// case 2: uses a task inheriting the condition of the extended task.
+	if err := r.Run("voskhod"); err != nil {
+		t.Errorf("Task should have been skipped without error, got %s!", err)
+	}
+
+	if _, err := os.Stat("when.log"); err == nil {
+		t.Error("Task inheriting a false condition should have been skipped!")
+	}
+
+	// case 3: uses a task with an invalid condition.
+	if err := r.Run("soyuz"); err == nil {
+		t.Error("Task with an invalid condition should have thrown an error!")
+	}
+
+	// case 4: uses a task whose condition is true thanks to an environment variable.
+	os.Setenv("ORBIT_MISSION", "sputnik")
+	defer os.Unsetenv("ORBIT_MISSION")
+
+	ctx, _ = context.NewOrbitContext(templateFilePath, "", "")
+	r, _ = NewOrbitRunner(ctx)
+	if err := r.Run("sputnik"); err != nil {
+		t.Errorf("Task should have been run, got %s!", err)
+	}
+
+	data, _ = ioutil.ReadFile("when.log")
+	if string(data) != "sputnik\n" {
+		t.Errorf("Task whose condition is true should have been run, got %q!", data)
+	}
+}
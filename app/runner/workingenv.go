@@ -0,0 +1,140 @@
+package runner
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	OrbitError "github.com/gulien/orbit/app/error"
+	"github.com/gulien/orbit/app/logger"
+)
+
+// ignoredWorkingEnvVariables contains the variables maintained by the shell itself
+// which should not be carried from one command to another.
+var ignoredWorkingEnvVariables = map[string]bool{
+	"_":      true,
+	"PWD":    true,
+	"OLDPWD": true,
+	"SHLVL":  true,
+}
+
+// workingEnvVariableRegexp is a simple regex pattern used to match the beginning of a line
+// created by the env command.
+var workingEnvVariableRegexp = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*=`)
+
+// posixShells contains the names of the shells understanding the instructions of wrapWorkingEnv.
+var posixShells = map[string]bool{
+	"sh":   true,
+	"bash": true,
+	"dash": true,
+	"zsh":  true,
+	"ksh":  true,
+}
+
+// doubleQuotedReplacer escapes the characters which keep a special meaning between double quotes.
+var doubleQuotedReplacer = strings.NewReplacer(`\`, `\\`, `$`, `\$`, "`", "\\`", `"`, `\"`)
+
+/*
+wrapWorkingEnv appends to the given command the instructions which write its
+exported environment into the given file, while keeping its exit status.
+
+The resulting command is only understood by POSIX shells.
+*/
+func wrapWorkingEnv(cmd string, filePath string) string {
+	return fmt.Sprintf("%s\n__orbit_status=$?\nenv > \"%s\"\nexit $__orbit_status", cmd, doubleQuotedReplacer.Replace(filePath))
+}
+
+// workingEnvShell returns the shell running the commands of the given task, i.e. its shell or the shell of the user,
+// and true if it's a POSIX shell.
+func workingEnvShell(task *orbitTask) (string, bool) {
+	shell := os.Getenv(defaultPosixShellEnvVariable)
+	if task.Shell != "" {
+		shell = strings.Fields(task.Shell)[0]
+	}
+
+	return shell, posixShells[filepath.Base(shell)]
+}
+
+// newWorkingEnvFile creates an empty file which will contain the environment of a command.
+func newWorkingEnvFile() (string, error) {
+	file, err := ioutil.TempFile("", "orbit-env")
+	if err != nil {
+		return "", OrbitError.NewOrbitErrorf("unable to create the working environment file. Details:\n%s", err)
+	}
+
+	defer file.Close()
+
+	return file.Name(), nil
+}
+
+/*
+readWorkingEnv reads the environment written by a command into the given file
+and returns the environment to use for the next command.
+
+If the file is empty (e.g. the command has exited before writing its environment),
+the previous environment is returned.
+*/
+func readWorkingEnv(filePath string, previous []string) ([]string, error) {
+	data, err := ioutil.ReadFile(filePath)
+	if err != nil {
+		return nil, OrbitError.NewOrbitErrorf("unable to read the working environment file %s. Details:\n%s", filePath, err)
+	}
+
+	if len(data) == 0 {
+		return previous, nil
+	}
+
+	var env []string
+	for _, line := range strings.Split(strings.TrimSuffix(string(data), "\n"), "\n") {
+		// a line which does not start with a variable name belongs to a multi-line value.
+		if !workingEnvVariableRegexp.MatchString(line) && len(env) > 0 {
+			env[len(env)-1] += "\n" + line
+			continue
+		}
+
+		env = append(env, line)
+	}
+
+	var result []string
+	for _, variable := range env {
+		if !ignoredWorkingEnvVariables[strings.SplitN(variable, "=", 2)[0]] {
+			result = append(result, variable)
+		}
+	}
+
+	logWorkingEnvDiff(previous, result)
+
+	return result, nil
+}
+
+// logWorkingEnvDiff logs the variables which have been added, updated or removed by a command.
+func logWorkingEnvDiff(previous []string, current []string) {
+	before := make(map[string]bool)
+	for _, variable := range previous {
+		before[variable] = true
+	}
+
+	after := make(map[string]bool)
+	for _, variable := range current {
+		after[variable] = true
+		if !before[variable] {
+			logger.Debugf("working environment has been updated with %s", variable)
+		}
+	}
+
+	for _, variable := range previous {
+		if !after[variable] {
+			logger.Debugf("working environment no longer contains %s", variable)
+		}
+	}
+}
+
+// removeWorkingEnvFile removes the file which contained the environment of a command.
+func removeWorkingEnvFile(filePath string) {
+	if err := os.Remove(filePath); err != nil {
+		logger.Debugf("unable to remove the working environment file %s: %s", filePath, err)
+	}
+}
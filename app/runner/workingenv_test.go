@@ -0,0 +1,67 @@
+package runner
+
+import (
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"testing"
+)
+
+// Tests if the environment is only captured through POSIX shells.
+func TestWorkingEnvShell(t *testing.T) {
+	shell := os.Getenv(defaultPosixShellEnvVariable)
+	defer os.Setenv(defaultPosixShellEnvVariable, shell)
+
+	// case 1: uses POSIX shells.
+	for _, shell := range []string{"bash -c", "/bin/sh -c", "/usr/bin/zsh -c"} {
+		if _, ok := workingEnvShell(&orbitTask{Shell: shell}); !ok {
+			t.Errorf("Shell %s should have been a POSIX shell!", shell)
+		}
+	}
+
+	// case 2: uses non-POSIX shells.
+	for _, shell := range []string{"fish -c", "nu -c", "python3 -c"} {
+		if _, ok := workingEnvShell(&orbitTask{Shell: shell}); ok {
+			t.Errorf("Shell %s should not have been a POSIX shell!", shell)
+		}
+	}
+
+	// case 3: uses the shell of the user.
+	os.Setenv(defaultPosixShellEnvVariable, "/usr/bin/fish")
+	if shell, ok := workingEnvShell(&orbitTask{}); ok || shell != "/usr/bin/fish" {
+		t.Errorf("Shell of the user should have been used, got %s!", shell)
+	}
+}
+
+// Tests if the environment is written into a file whose path contains special characters.
+func TestWrapWorkingEnv(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("working environment is only available on POSIX systems")
+	}
+
+	dir, _ := ioutil.TempDir("", "orbit-env")
+	defer os.RemoveAll(dir)
+
+	filePath := filepath.Join(dir, "a $HOME `b` \"c\" 'd' \\e")
+	cmd := exec.Command("sh", "-c", wrapWorkingEnv("export ORBIT_WORKING_ENV=1", filePath))
+	if err := cmd.Run(); err != nil {
+		t.Errorf("Wrapped command should have been run, got %s!", err)
+	}
+
+	if environ, _ := readWorkingEnv(filePath, nil); !containsVariable(environ, "ORBIT_WORKING_ENV=1") {
+		t.Error("Environment should have been written into the file!")
+	}
+}
+
+// containsVariable returns true if the given environment contains the given variable.
+func containsVariable(environ []string, variable string) bool {
+	for _, value := range environ {
+		if value == variable {
+			return true
+		}
+	}
+
+	return false
+}
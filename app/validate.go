@@ -0,0 +1,55 @@
+package app
+
+import (
+	"github.com/gulien/orbit/app/context"
+	OrbitError "github.com/gulien/orbit/app/error"
+	"github.com/gulien/orbit/app/logger"
+	"github.com/gulien/orbit/app/runner"
+
+	"github.com/spf13/cobra"
+)
+
+// validateCmd is the instance of validate command.
+var validateCmd = &cobra.Command{
+	Use:           "validate",
+	Short:         "Validates a configuration file",
+	Long:          "Validates a configuration file without executing any command: reports its unknown attributes and all its problems, with their lines.",
+	Args:          cobra.NoArgs,
+	SilenceUsage:  true,
+	SilenceErrors: true,
+	RunE:          validate,
+}
+
+// init adds the validateCmd instance to the RootCmd.
+func init() {
+	RootCmd.AddCommand(validateCmd)
+}
+
+// validate reports all the problems of the configuration file.
+func validate(cmd *cobra.Command, args []string) error {
+	if templateFilePath == "" {
+		templateFilePath = orbitFilePath
+	}
+
+	ctx, err := context.NewOrbitContextWithSettings(templateFilePath, payload, templates, contextSettings())
+	if err != nil {
+		return err
+	}
+
+	if err := ctx.LoadDotenv(envFiles...); err != nil {
+		return err
+	}
+
+	problems := runner.Validate(ctx)
+	for _, problem := range problems {
+		logger.Error(problem)
+	}
+
+	if len(problems) > 0 {
+		return OrbitError.NewOrbitErrorf("configuration file %s has %d problem(s)", templateFilePath, len(problems))
+	}
+
+	logger.Infof("configuration file %s is valid", templateFilePath)
+
+	return nil
+}
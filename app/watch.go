@@ -0,0 +1,72 @@
+package app
+
+import (
+	"time"
+
+	"github.com/gulien/orbit/app/context"
+	"github.com/gulien/orbit/app/runner"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	// watchPaths contains the glob patterns of the watched files.
+	watchPaths []string
+
+	// watchPoll is the interval at which the watched files are scanned.
+	watchPoll time.Duration
+
+	// clearScreen enables the clearing of the terminal before each run of the task but the first one if true.
+	clearScreen bool
+
+	// watchCmd is the instance of watch command.
+	watchCmd = &cobra.Command{
+		Use:           "watch <task>",
+		Short:         "Runs a task again each time its files change",
+		Long:          "Runs a task defined in a configuration file, then runs it again each time the watched files change.",
+		Args:          cobra.ExactArgs(1),
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		RunE:          watch,
+	}
+)
+
+// init initializes a watchCmd instance with some flags and adds it to the RootCmd.
+func init() {
+	watchCmd.Flags().StringArrayVar(&watchPaths, "path", nil, "specify a glob pattern of the watched files, relative to the configuration file (e.g. src/**/*.go)")
+	watchCmd.Flags().DurationVar(&watchPoll, "poll", 0, "specify the interval at which the watched files are scanned, 1s if no value is given (default 100ms)")
+	watchCmd.Flags().Lookup("poll").NoOptDefVal = runner.SlowWatchPoll.String()
+	watchCmd.Flags().BoolVar(&clearScreen, "clear", false, "clear the terminal before running the task again (no effect if the output is not a terminal)")
+	watchCmd.Flags().DurationVar(&gracePeriod, "grace-period", runner.DefaultGracePeriod, "specify the duration between the termination signal sent to a cancelled command and its kill (POSIX only)")
+	RootCmd.AddCommand(watchCmd)
+}
+
+// watch runs a task each time its files change, until Orbit is interrupted.
+func watch(cmd *cobra.Command, args []string) error {
+	if templateFilePath == "" {
+		templateFilePath = orbitFilePath
+	}
+
+	ctx, err := context.NewOrbitContextWithSettings(templateFilePath, payload, templates, contextSettings())
+	if err != nil {
+		return err
+	}
+
+	if err := ctx.LoadDotenv(envFiles...); err != nil {
+		return err
+	}
+
+	r, err := runner.NewOrbitRunner(ctx)
+	if err != nil {
+		return err
+	}
+
+	r.GracePeriod = gracePeriod
+	r.WatchPoll = watchPoll
+	r.ClearScreen = clearScreen
+
+	signalContext, stop := newSignalContext()
+	defer stop()
+
+	return r.Watch(signalContext, watchPaths, args[0])
+}